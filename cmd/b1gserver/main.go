@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/alecthomas/kong"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/server"
+)
+
+type globalCmd struct {
+	ProjectID string `help:"GCP project ID. Set FIRESTORE_EMULATOR_HOST to serve a local Firestore emulator instead." env:"GCP_PROJECT"`
+}
+
+var CLI struct {
+	globalCmd
+
+	Serve serveCmd `cmd:"" help:"Serve the pool's data as JSON and as a web UI over HTTP."`
+	Token tokenCmd `cmd:"" help:"Make a new API token for a picker for a tokens file. Tokens stored with pickers are made with b1gtool pickers token."`
+}
+
+type serveCmd struct {
+	Addr   string `help:"Address to listen on." default:":8080"`
+	Tokens string `help:"JSON file of picker tokens: SHA-256 token hashes (see the token command) mapped to picker LukeNames. If not given, tokens are checked against those stored with the pickers in Firestore, which also carry the pickers' roles." type:"existingfile"`
+}
+
+func (s serveCmd) Run(g *globalCmd) error {
+	if g.ProjectID == "" {
+		return fmt.Errorf("a GCP project ID is required")
+	}
+	ctx := context.Background()
+	client, err := fs.NewClient(ctx, g.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to create Firestore client: %w", err)
+	}
+	defer client.Close()
+
+	var auth server.Authenticator = server.FirestoreTokens{Client: client}
+	if s.Tokens != "" {
+		if auth, err = server.LoadTokens(s.Tokens); err != nil {
+			return err
+		}
+	}
+	srv := server.New(server.NewFirestoreStore(client), auth)
+	log.Printf("Listening on %s", s.Addr)
+	return http.ListenAndServe(s.Addr, srv.Handler())
+}
+
+type tokenCmd struct {
+	Picker string `arg:"" help:"LukeName of the picker."`
+}
+
+func (t tokenCmd) Run(g *globalCmd) error {
+	token, err := firestore.NewToken()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Token for %s (give this to the picker): %s\n", t.Picker, token)
+	fmt.Printf("Tokens file entry: \"%s\": \"%s\"\n", firestore.HashToken(token), t.Picker)
+	return nil
+}
+
+func main() {
+	ctx := kong.Parse(&CLI)
+	err := ctx.Run(&CLI.globalCmd)
+	ctx.FatalIfErrorf(err)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// whoami returns the picker the token given on the command line belongs to.
+func (g *globalCmd) whoami(ctx context.Context, client *fs.Client) (firestore.Picker, error) {
+	p, _, err := firestore.Authenticate(ctx, client, g.Token)
+	return p, err
+}
+
+// requireCommissioner returns the picker the token given on the command line belongs to, or an error unless that picker is a commissioner.
+func (g *globalCmd) requireCommissioner(ctx context.Context, client *fs.Client, what string) (firestore.Picker, error) {
+	p, err := g.whoami(ctx, client)
+	if err != nil {
+		return p, err
+	}
+	return p, p.CheckCommissioner(what)
+}
+
+// requirePicksOf returns the picker the token given on the command line belongs to, or an error unless that picker may change the picks of the named picker.
+// Overriding locked picks is reserved for commissioners.
+func (g *globalCmd) requirePicksOf(ctx context.Context, client *fs.Client, picker string, override string) (firestore.Picker, error) {
+	p, err := g.whoami(ctx, client)
+	if err != nil {
+		return p, err
+	}
+	if override != "" {
+		if err := p.CheckCommissioner("override locked picks"); err != nil {
+			return p, err
+		}
+	}
+	return p, p.CheckPicksOf(picker)
+}
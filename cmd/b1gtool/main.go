@@ -6,6 +6,7 @@ import (
 
 type globalCmd struct {
 	ProjectID string `help:"GCP project ID." env:"GCP_PROJECT" required:""`
+	Token     string `help:"Your API token (see the pickers token command). Commands that change picks or run the pool check that it lets you make the change." env:"B1GPICKEM_TOKEN"`
 }
 
 var CLI struct {
@@ -18,16 +19,22 @@ var CLI struct {
 		Edit       editPickerCmd        `cmd:"" help:"Edit picker."`
 		Activate   activatePickersCmd   `cmd:"" help:"Activate pickers for a season."`
 		Deactivate deactivatePickersCmd `cmd:"" help:"Deactivate pickers for a season."`
+		Token      tokenPickerCmd       `cmd:"" help:"Make a new API token for a picker."`
+		Revoke     revokeTokensCmd      `cmd:"" help:"Revoke all API tokens of a picker."`
+		Role       rolePickerCmd        `cmd:"" help:"Give a picker a role: picker or commissioner."`
 	} `cmd:""`
 
 	Teams struct {
-		Edit editTeamCmd `cmd:"" help:"Edit team."`
-		Ls   lsTeamsCmd  `cmd:"" help:"List teams."`
+		Edit           editTeamCmd       `cmd:"" help:"Edit team."`
+		Ls             lsTeamsCmd        `cmd:"" help:"List teams."`
+		Find           findTeamCmd       `cmd:"" help:"Find a team by name."`
+		ApproveAliases approveAliasesCmd `cmd:"" help:"Add approved aliases from a pending aliases file to their teams."`
 	} `cmd:""`
 
 	Season struct {
 		Setup     setupSeasonCmd `cmd:"" help:"Setup season."`
 		SplitWeek splitWeekCmd   `cmd:"" help:"Split week based on time of kickoff."`
+		Lock      pickLockCmd    `cmd:"" help:"Set when picks lock: at kickoff, or also at a deadline relative to the first game of each week."`
 	} `cmd:""`
 
 	Models struct {
@@ -37,16 +44,35 @@ var CLI struct {
 		Add            addModelsCmd      `cmd:"" help:"Add new model information."`
 		Rm             rmModelsCmd       `cmd:"" help:"Remove model."`
 		Ls             lsModelsCmd       `cmd:"" help:"List all models."`
+		Select         selectModelsCmd   `cmd:"" help:"Select the best model for each type of slate game from past results."`
 	} `cmd:""`
 
 	Slate struct {
-		Parse parseSlateCmd `cmd:"" help:"Parse official slate."`
+		Parse    parseSlateCmd    `cmd:"" help:"Parse official slate."`
+		Layout   slateLayoutCmd   `cmd:"" help:"Print the default slate layout as JSON to use as a starting point for a custom layout."`
+		Diff     diffSlateCmd     `cmd:"" help:"Compare two slates and report picks that refer to games outside of the newer slate."`
+		Generate generateSlateCmd `cmd:"" help:"Generate a suggested slate from the week's games, rankings, and model predictions."`
+	} `cmd:""`
+
+	Rankings struct {
+		Update updateRankingsCmd `cmd:"" help:"Store poll rankings from CollegeFootballData.com."`
+		Check  checkRankingsCmd  `cmd:"" help:"Compare the ranks on a slate with stored poll rankings."`
 	} `cmd:""`
 
 	Picks struct {
-		Pickem pickemCmd      `cmd:"" help:"Make picks."`
-		Export exportPicksCmd `cmd:"" help:"Export picks."`
+		Pickem      pickemCmd           `cmd:"" help:"Make picks."`
+		Interactive interactivePicksCmd `cmd:"" help:"Make picks interactively, game by game."`
+		Export      exportPicksCmd      `cmd:"" help:"Export picks."`
+		History     pickHistoryCmd      `cmd:"" help:"Show every change made to picks, with who made it, when, and from which model prediction."`
+		Missing     missingPicksCmd     `cmd:"" help:"List pickers who have not finished their picks: slate games without a pick, no superdog, or no Beat the Streak pick."`
 	} `cmd:""`
+
+	Pipeline struct {
+		Run      pipelineRunCmd      `cmd:"" help:"Run the weekly pipeline for a week, resuming where the last run stopped."`
+		Schedule pipelineScheduleCmd `cmd:"" help:"Run the weekly pipeline for each week of a season ahead of its first game."`
+	} `cmd:""`
+
+	Recap recapCmd `cmd:"" help:"Render a recap of a week: standings, notable picks, upsets, superdogs, streaks, and ponies."`
 }
 
 func main() {
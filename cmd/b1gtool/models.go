@@ -4,6 +4,8 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
+	"github.com/reallyasi9/b1gpickem/internal/tools/selectmodels"
 	"github.com/reallyasi9/b1gpickem/internal/tools/updatemodels"
 )
 
@@ -54,6 +56,8 @@ type updateSagarinCmd struct {
 	Force  bool `help:"Force overwrite or delete data from datastore."`
 	Season int  `arg:"" help:"Season ID to update." required:""`
 	Week   int  `arg:"" help:"Week of update." required:""`
+
+	editteams.ResolverFlags
 }
 
 func (a *updateSagarinCmd) Run(g *globalCmd) error {
@@ -67,6 +71,7 @@ func (a *updateSagarinCmd) Run(g *globalCmd) error {
 	}
 	ctx.Season = a.Season
 	ctx.Week = a.Week
+	ctx.Resolver = a.Resolver()
 	return updatemodels.UpdateSagarin(ctx)
 }
 
@@ -123,3 +128,26 @@ func (a *lsModelsCmd) Run(g *globalCmd) error {
 	}
 	return updatemodels.LsModels(ctx)
 }
+
+type selectModelsCmd struct {
+	DryRun   bool `help:"Print database writes to log and exit without writing."`
+	Season   int  `arg:"" help:"Season of week to select models for." required:""`
+	Week     int  `arg:"" help:"Week to select models for." required:""`
+	History  int  `help:"Number of previous seasons to include when evaluating models." default:"2"`
+	MinGames int  `help:"Minimum number of decided games of a type a model must have predicted to be selected for that type." default:"10"`
+}
+
+func (a *selectModelsCmd) Run(g *globalCmd) error {
+	ctx := selectmodels.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.History = a.History
+	ctx.MinGames = a.MinGames
+	return selectmodels.SelectModels(ctx)
+}
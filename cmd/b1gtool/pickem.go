@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/tools/pickem"
@@ -15,6 +16,7 @@ type pickemCmd struct {
 	Picker   string   `arg:"" help:"Picker." required:""`
 	Picks    []string `arg:"" help:"Names of teams to pick."`
 	SuperDog string   `help:"Superdog pick."`
+	Override string   `help:"Commissioner's reason for changing picks after they locked. The override is recorded with the changes."`
 }
 
 func (a *pickemCmd) Run(g *globalCmd) error {
@@ -26,22 +28,61 @@ func (a *pickemCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	ctx.Actor, err = g.requirePicksOf(ctx, ctx.FirestoreClient, a.Picker, a.Override)
+	if err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.Week = a.Week
 	ctx.Picker = a.Picker
 	ctx.Picks = a.Picks
 	ctx.SuperDog = a.SuperDog
+	ctx.Override = a.Override
 	return pickem.Pickem(ctx)
 }
 
+type interactivePicksCmd struct {
+	DryRun   bool     `help:"Print database writes to log and exit without writing."`
+	Season   int      `arg:"" help:"Season of slate." required:""`
+	Week     int      `arg:"" help:"Week of slate." required:""`
+	Picker   string   `arg:"" help:"Picker." required:""`
+	Model    []string `help:"Short names of models whose predictions to show. The first model with a prediction of a game suggests the pick." default:"line"`
+	Override string   `help:"Commissioner's reason for changing picks after they locked. The override is recorded with the changes."`
+}
+
+func (a *interactivePicksCmd) Run(g *globalCmd) error {
+	ctx := pickem.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Actor, err = g.requirePicksOf(ctx, ctx.FirestoreClient, a.Picker, a.Override)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Picker = a.Picker
+	ctx.Models = a.Model
+	ctx.Override = a.Override
+	return pickem.InteractivePickem(ctx)
+}
+
 type exportPicksCmd struct {
 	Season int    `arg:"" help:"Season of slate." required:""`
 	Week   int    `arg:"" help:"Week of slate." required:""`
-	Picker string `arg:"" help:"Picker." required:""`
+	Picker string `arg:"" help:"Picker. Ignored with --all." optional:""`
 	Output string `help:"Output path. Can be a local path or a Google Storage path prefixed by 'gs://'. Default: print to stdout."`
+	Format string `help:"Output format: xlsx, csv, json, markdown, or html." enum:"xlsx,csv,json,markdown,html" default:"xlsx"`
+	All    bool   `help:"Export the picks of every picker in the season. Excel output has one sheet per picker plus a summary sheet."`
 }
 
 func (a *exportPicksCmd) Run(g *globalCmd) error {
+	if a.Picker == "" && !a.All {
+		return fmt.Errorf("a picker is required unless exporting with --all")
+	}
 	ctx := pickem.NewContext(context.Background())
 	var err error
 	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
@@ -52,5 +93,45 @@ func (a *exportPicksCmd) Run(g *globalCmd) error {
 	ctx.Week = a.Week
 	ctx.Picker = a.Picker
 	ctx.Output = a.Output
+	ctx.Format = a.Format
+	ctx.All = a.All
 	return pickem.ExportPicks(ctx)
 }
+
+type pickHistoryCmd struct {
+	Season int    `arg:"" help:"Season of slate." required:""`
+	Week   int    `arg:"" help:"Week of slate." required:""`
+	Picker string `arg:"" help:"Picker. Default: show the changes to every picker's picks." optional:""`
+}
+
+func (a *pickHistoryCmd) Run(g *globalCmd) error {
+	ctx := pickem.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Picker = a.Picker
+	return pickem.PickHistory(ctx)
+}
+
+type missingPicksCmd struct {
+	Season int  `arg:"" help:"Season of slate." required:""`
+	Week   int  `arg:"" help:"Week of slate." required:""`
+	Remind bool `help:"Print a reminder message for each picker with missing picks."`
+}
+
+func (a *missingPicksCmd) Run(g *globalCmd) error {
+	ctx := pickem.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Remind = a.Remind
+	return pickem.Missing(ctx)
+}
@@ -22,6 +22,9 @@ func (a *addPickersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit pickers"); err != nil {
+		return err
+	}
 	ctx.Pickers = a.Pickers
 	return editpickers.AddPickers(ctx)
 }
@@ -42,6 +45,9 @@ func (a *rmPickersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit pickers"); err != nil {
+		return err
+	}
 	pickers := make([]firestore.Picker, len(a.Pickers))
 	for i, picker := range a.Pickers {
 		pickers[i] = firestore.Picker{LukeName: picker}
@@ -81,6 +87,9 @@ func (a *editPickerCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit pickers"); err != nil {
+		return err
+	}
 	pickers := []firestore.Picker{
 		{
 			LukeName: a.LukeName,
@@ -107,6 +116,9 @@ func (a *activatePickersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit pickers"); err != nil {
+		return err
+	}
 	pickers := make([]firestore.Picker, len(a.Pickers))
 	for i, picker := range a.Pickers {
 		pickers[i] = firestore.Picker{LukeName: picker}
@@ -130,6 +142,9 @@ func (a *deactivatePickersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit pickers"); err != nil {
+		return err
+	}
 	pickers := make([]firestore.Picker, len(a.Pickers))
 	for i, picker := range a.Pickers {
 		pickers[i] = firestore.Picker{LukeName: picker}
@@ -138,3 +153,87 @@ func (a *deactivatePickersCmd) Run(g *globalCmd) error {
 	ctx.Season = a.Season
 	return editpickers.DeactivatePickers(ctx)
 }
+
+type tokenPickerCmd struct {
+	DryRun bool   `help:"Print database writes to log and exit without writing."`
+	Picker string `arg:"" help:"LukeName of picker to make a token for." required:""`
+}
+
+func (a *tokenPickerCmd) Run(g *globalCmd) error {
+	ctx := editpickers.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	// pickers can replace their own tokens, but only commissioners can hand out tokens to others
+	me, err := g.whoami(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return err
+	}
+	if me.LukeName != a.Picker {
+		if err := me.CheckCommissioner("make tokens for other pickers"); err != nil {
+			return err
+		}
+	}
+	ctx.Pickers = []firestore.Picker{{LukeName: a.Picker}}
+	return editpickers.AddToken(ctx)
+}
+
+type revokeTokensCmd struct {
+	Force  bool   `help:"Force overwriting or deleting data in database." xor:"Force,DryRun"`
+	DryRun bool   `help:"Print database writes to log and exit without writing." xor:"Force,DryRun"`
+	Picker string `arg:"" help:"LukeName of picker whose tokens to revoke." required:""`
+}
+
+func (a *revokeTokensCmd) Run(g *globalCmd) error {
+	ctx := editpickers.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	ctx.Force = a.Force
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	me, err := g.whoami(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return err
+	}
+	if me.LukeName != a.Picker {
+		if err := me.CheckCommissioner("revoke the tokens of other pickers"); err != nil {
+			return err
+		}
+	}
+	ctx.Pickers = []firestore.Picker{{LukeName: a.Picker}}
+	return editpickers.RevokeTokens(ctx)
+}
+
+type rolePickerCmd struct {
+	DryRun bool           `help:"Print database writes to log and exit without writing."`
+	Picker string         `arg:"" help:"LukeName of picker." required:""`
+	Role   firestore.Role `arg:"" help:"Role to give the picker: picker or commissioner." required:""`
+}
+
+func (a *rolePickerCmd) Run(g *globalCmd) error {
+	ctx := editpickers.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	// the first commissioner is appointed by whoever has access to the database
+	hasCommissioner, err := firestore.HasCommissioner(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return err
+	}
+	if hasCommissioner {
+		if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "change roles"); err != nil {
+			return err
+		}
+	}
+	ctx.Pickers = []firestore.Picker{{LukeName: a.Picker}}
+	ctx.Role = a.Role
+	return editpickers.SetRole(ctx)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
+	"github.com/reallyasi9/b1gpickem/internal/tools/pipeline"
+)
+
+type pipelineFlags struct {
+	DryRun      bool     `help:"Print database writes to log and exit without writing." xor:"Force,DryRun"`
+	Force       bool     `help:"Force overwriting or deleting data in database." xor:"Force,DryRun"`
+	Slate       string   `help:"Path to the official slate. Can be either a local path or a Google Storage URL starting with 'gs://'. '{week}' is replaced with the week number. If not given, the slate must already be parsed."`
+	Layout      string   `help:"Path to a JSON file describing the slate layout. If not given, the default layout is used."`
+	Migrate     bool     `help:"Migrate picks made against a previously parsed slate of the week to the new slate. Only picks whose games' terms did not change are migrated, and locked picks are not. Without it, stale picks are only reported."`
+	Iterations  int      `help:"Number of simulated annealing iterations per streaker." default:"1000000"`
+	Pickem4me   string   `name:"pickem4me" help:"The pickem4me program used to make picks for auto pickers." default:"pickem4me"`
+	AutoPickers []string `name:"auto-picker" help:"LukeNames of pickers to make picks for with pickem4me. Can be given more than once."`
+	ExportDir   string   `help:"Directory to export the week's picks to." default:"." type:"path"`
+	StateDir    string   `help:"Directory where the state of each week's run is kept." default:"." type:"path"`
+
+	editteams.ResolverFlags
+}
+
+func (p *pipelineFlags) context(g *globalCmd) (*pipeline.Context, error) {
+	ctx := pipeline.NewContext(context.Background())
+	ctx.DryRun = p.DryRun
+	ctx.Force = p.Force
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Actor, err = g.requireCommissioner(ctx, ctx.FirestoreClient, "run the pipeline")
+	if err != nil {
+		return nil, err
+	}
+	ctx.ProjectID = g.ProjectID
+	ctx.Token = g.Token
+	ctx.Slate = p.Slate
+	ctx.Layout = p.Layout
+	ctx.Migrate = p.Migrate
+	ctx.Resolver = p.Resolver()
+	ctx.Iterations = p.Iterations
+	ctx.Pickem4me = p.Pickem4me
+	ctx.AutoPickers = p.AutoPickers
+	ctx.ExportDir = p.ExportDir
+	ctx.StateDir = p.StateDir
+	return ctx, nil
+}
+
+type pipelineRunCmd struct {
+	Season  int      `arg:"" help:"Season to run." required:""`
+	Week    int      `arg:"" help:"Week to run." required:""`
+	Restart bool     `help:"Ignore the state of previous runs and run every step again."`
+	Only    []string `help:"Run only the named steps, regardless of the state of previous runs."`
+
+	pipelineFlags
+}
+
+func (a *pipelineRunCmd) Run(g *globalCmd) error {
+	ctx, err := a.context(g)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Restart = a.Restart
+	ctx.Only = a.Only
+	return pipeline.Run(ctx)
+}
+
+type pipelineScheduleCmd struct {
+	Season int           `arg:"" help:"Season to run." required:""`
+	Lead   time.Duration `help:"How long before the first game of each week to run the pipeline." default:"48h"`
+	Retry  time.Duration `help:"How long to wait before trying a failed run again." default:"1h"`
+
+	pipelineFlags
+}
+
+func (a *pipelineScheduleCmd) Run(g *globalCmd) error {
+	ctx, err := a.context(g)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Lead = a.Lead
+	ctx.Retry = a.Retry
+	return pipeline.Schedule(ctx)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/rankings"
+)
+
+type updateRankingsCmd struct {
+	DryRun     bool     `help:"Print database writes to log and exit without writing."`
+	Force      bool     `help:"Force overwrite or delete data from datastore."`
+	ApiKey     string   `arg:"" help:"CollegeFootballData.com API key." required:""`
+	Season     int      `arg:"" help:"Season of rankings." required:""`
+	Week       int      `arg:"" help:"Week of rankings. Polls are stored under the week they were released before, so week 1 holds the preseason polls." required:""`
+	SeasonType string   `help:"Season type of the week." enum:"regular,postseason" default:"regular"`
+	Poll       []string `help:"Names of polls to store. If not given, all polls are stored."`
+}
+
+func (a *updateRankingsCmd) Run(g *globalCmd) error {
+	ctx := rankings.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	ctx.Force = a.Force
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.ApiKey = a.ApiKey
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.SeasonType = a.SeasonType
+	ctx.Polls = a.Poll
+	return rankings.UpdateRankings(ctx)
+}
+
+type checkRankingsCmd struct {
+	Season int      `arg:"" help:"Season of slate." required:""`
+	Week   int      `arg:"" help:"Week of slate." required:""`
+	Slate  string   `help:"ID of the slate to check. If not given, the most recently parsed slate is checked."`
+	Poll   []string `help:"Names of polls to check against." default:"AP Top 25"`
+}
+
+func (a *checkRankingsCmd) Run(g *globalCmd) error {
+	ctx := rankings.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Slate = a.Slate
+	ctx.Polls = a.Poll
+	return rankings.CheckSlate(ctx)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/recap"
+)
+
+type recapCmd struct {
+	Season   int    `arg:"" help:"Season of week to recap." required:""`
+	Week     int    `arg:"" help:"Week to recap." required:""`
+	Format   string `help:"Output format: markdown or html." enum:"markdown,html" default:"markdown"`
+	Template string `help:"Template file to render instead of the built-in template for the format. See internal/tools/recap/templates for the built-in templates." type:"existingfile"`
+	Output   string `short:"o" help:"Path of the rendered recap. Defaults to stdout." type:"path"`
+}
+
+func (a *recapCmd) Run(g *globalCmd) error {
+	ctx := recap.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Format = a.Format
+	ctx.Template = a.Template
+	ctx.Output = a.Output
+	return recap.GenerateRecap(ctx)
+}
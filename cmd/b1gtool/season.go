@@ -5,6 +5,7 @@ import (
 	"time"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/tools/setupseason"
 )
 
@@ -57,3 +58,26 @@ func (a *splitWeekCmd) Run(g *globalCmd) error {
 	ctx.NewWeekNumber = a.NewWeekNumber
 	return setupseason.SplitWeek(ctx)
 }
+
+type pickLockCmd struct {
+	DryRun   bool          `help:"Print database writes to log and exit without writing."`
+	Season   int           `arg:"" help:"Season ID to modify." required:""`
+	Deadline bool          `help:"Lock all of a week's picks at a deadline relative to the start of the week's first game, in addition to locking each game's picks at kickoff."`
+	Offset   time.Duration `help:"Time from the start of the week's first game to the deadline. Negative offsets lock picks before the first game starts." default:"0s"`
+}
+
+func (a *pickLockCmd) Run(g *globalCmd) error {
+	ctx := setupseason.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "change pick locks"); err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.PickLock = firestore.PickLock{Deadline: a.Deadline, Offset: a.Offset}
+	return setupseason.SetPickLock(ctx)
+}
@@ -2,17 +2,28 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
+	"github.com/reallyasi9/b1gpickem/internal/tools/generateslate"
 	"github.com/reallyasi9/b1gpickem/internal/tools/parseslate"
+	"github.com/reallyasi9/b1gpickem/internal/tools/slatediff"
 )
 
 type parseSlateCmd struct {
-	DryRun bool   `help:"Print database writes to log and exit without writing."`
-	Force  bool   `help:"Force overwrite or delete data from datastore."`
-	Season int    `arg:"" help:"Season of slate." required:""`
-	Week   int    `arg:"" help:"Week of slate." required:""`
-	Slate  string `arg:"" help:"Path to slate. Can be either a local path or a Google Storage URL starting with 'gs://'." required:""`
+	DryRun  bool   `help:"Print database writes to log and exit without writing."`
+	Force   bool   `help:"Force overwrite or delete data from datastore."`
+	Season  int    `arg:"" help:"Season of slate." required:""`
+	Week    int    `arg:"" help:"Week of slate." required:""`
+	Slate   string `arg:"" help:"Path to slate. Can be either a local path or a Google Storage URL starting with 'gs://'." required:""`
+	Layout  string `help:"Path to a JSON file describing the slate layout. Can be either a local path or a Google Storage URL starting with 'gs://'. If not given, the default layout is used."`
+	Migrate bool   `help:"Migrate picks made against the previous slate to the matching games in the new slate. Picks whose games' terms changed are migrated only if you confirm them, and locked picks are not migrated."`
+	Format  string `help:"Slate file format (xlsx, csv, or ods). If not given, the format in the layout is used, or the format is guessed from the file extension."`
+	Poll    string `help:"Name of the stored poll to compare slate ranks with. Set to an empty string to skip the comparison." default:"AP Top 25"`
+
+	editteams.ResolverFlags
 }
 
 func (a *parseSlateCmd) Run(g *globalCmd) error {
@@ -24,8 +35,99 @@ func (a *parseSlateCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	ctx.Actor, err = g.requireCommissioner(ctx, ctx.FirestoreClient, "parse slates")
+	if err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.Week = a.Week
 	ctx.Slate = a.Slate
+	ctx.Layout = a.Layout
+	ctx.Format = a.Format
+	ctx.Migrate = a.Migrate
+	ctx.Confirmer = slatediff.SurveyConfirmer{}
+	ctx.Poll = a.Poll
+	ctx.Resolver = a.Resolver()
 	return parseslate.ParseSlate(ctx)
 }
+
+type slateLayoutCmd struct{}
+
+func (a *slateLayoutCmd) Run(g *globalCmd) error {
+	return parseslate.WriteLayout(os.Stdout, parseslate.DefaultLayout())
+}
+
+type diffSlateCmd struct {
+	DryRun   bool   `help:"Print database writes to log and exit without writing."`
+	Season   int    `arg:"" help:"Season of slates." required:""`
+	Week     int    `arg:"" help:"Week of slates." required:""`
+	Old      string `help:"ID of the slate to compare against. If not given, the second most recently parsed slate is used."`
+	New      string `help:"ID of the slate to compare. If not given, the most recently parsed slate is used."`
+	Migrate  bool   `help:"Migrate stale picks to the matching games in the new slate. Picks whose games' terms changed are migrated only if you confirm them. Only commissioners can migrate picks."`
+	Override string `help:"Reason for migrating picks that are locked. Locked picks are not migrated without one."`
+}
+
+func (a *diffSlateCmd) Run(g *globalCmd) error {
+	ctx := slatediff.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Old = a.Old
+	ctx.New = a.New
+	ctx.Migrate = a.Migrate
+	if a.Migrate {
+		ctx.Actor, err = g.requireCommissioner(ctx, ctx.FirestoreClient, "migrate picks")
+		if err != nil {
+			return err
+		}
+	}
+	ctx.Confirmer = slatediff.SurveyConfirmer{}
+	ctx.Override = a.Override
+	ctx.Source = "b1gtool slate diff"
+	return slatediff.DiffSlates(ctx)
+}
+
+type generateSlateCmd struct {
+	DryRun            bool     `help:"Print the suggested slate and exit without writing it."`
+	Force             bool     `help:"Overwrite the output file if it exists."`
+	Season            int      `arg:"" help:"Season of slate." required:""`
+	Week              int      `arg:"" help:"Week of slate." required:""`
+	Output            string   `short:"o" help:"Path of the generated slate workbook. Defaults to 'slate-<season>-<week>.xlsx'." type:"path"`
+	Rankings          string   `help:"CSV file of 'rank,team' rows giving the week's poll. If not given, the most recent stored ranking of --poll is used." type:"existingfile"`
+	Poll              string   `help:"Name of the stored poll to use if --rankings is not given." default:"AP Top 25"`
+	Teams             []string `help:"Names of teams whose games are always put on the slate, ranked or not."`
+	Model             string   `help:"Short name of the model whose predicted spreads are used." default:"line"`
+	NoisySpreadMin    float64  `help:"Smallest predicted spread for which a noisy spread is suggested." default:"10"`
+	SuperdogMinSpread float64  `help:"Smallest predicted spread for which a game is suggested as a superdog." default:"7"`
+	Superdogs         int      `help:"Number of superdogs to suggest." default:"4"`
+}
+
+func (a *generateSlateCmd) Run(g *globalCmd) error {
+	ctx := generateslate.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	ctx.Force = a.Force
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.Output = a.Output
+	if ctx.Output == "" {
+		ctx.Output = fmt.Sprintf("slate-%d-%d.xlsx", a.Season, a.Week)
+	}
+	ctx.Rankings = a.Rankings
+	ctx.Poll = a.Poll
+	ctx.Teams = a.Teams
+	ctx.Model = a.Model
+	ctx.NoisySpreadMin = a.NoisySpreadMin
+	ctx.SuperdogMinSpread = a.SuperdogMinSpread
+	ctx.NSuperdogs = a.Superdogs
+	return generateslate.GenerateSlate(ctx)
+}
@@ -32,6 +32,9 @@ func (a *editTeamCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit teams"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.ID = a.ID
 	ctx.Team = firestore.Team{
@@ -61,3 +64,42 @@ func (a *lsTeamsCmd) Run(g *globalCmd) error {
 	ctx.Season = a.Season
 	return editteams.LsTeams(ctx)
 }
+
+type findTeamCmd struct {
+	Season int    `arg:"" help:"Season where team is defined." required:""`
+	Name   string `arg:"" help:"Name of team to find. If no team is known by the name, the teams with the most similar names are listed." required:""`
+}
+
+func (a *findTeamCmd) Run(g *globalCmd) error {
+	ctx := editteams.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Name = a.Name
+	return editteams.FindTeam(ctx)
+}
+
+type approveAliasesCmd struct {
+	DryRun bool   `help:"Print database writes to log and exit without writing."`
+	Season int    `arg:"" help:"Season where teams are defined." required:""`
+	File   string `arg:"" help:"Pending aliases file written by commands run with --non-interactive. Aliases marked approved are added to their teams and removed from the file." type:"existingfile" required:""`
+}
+
+func (a *approveAliasesCmd) Run(g *globalCmd) error {
+	ctx := editteams.NewContext(context.Background())
+	ctx.DryRun = a.DryRun
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	if _, err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit teams"); err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.AliasFile = a.File
+	return editteams.ApproveAliases(ctx)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// requireCommissioner returns an error unless the token given on the command line belongs to a commissioner.
+func (g *globalCmd) requireCommissioner(ctx context.Context, client *fs.Client, what string) error {
+	p, _, err := firestore.Authenticate(ctx, client, g.Token)
+	if err != nil {
+		return err
+	}
+	return p.CheckCommissioner(what)
+}
+
+// requireStreakPicksOf returns the picker the token given on the command line belongs to, or an error unless that picker may change the streak picks of every named streaker.
+// Overriding locked picks is reserved for commissioners.
+func (g *globalCmd) requireStreakPicksOf(ctx context.Context, client *fs.Client, streakers []string, override string) (firestore.Picker, error) {
+	p, _, err := firestore.Authenticate(ctx, client, g.Token)
+	if err != nil {
+		return p, err
+	}
+	if override != "" {
+		if err := p.CheckCommissioner("override locked picks"); err != nil {
+			return p, err
+		}
+	}
+	for _, s := range streakers {
+		if err := p.CheckPicksOf(s); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
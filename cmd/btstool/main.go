@@ -7,6 +7,7 @@ type globalCmd struct {
 	DryRun     bool   `help:"Print database writes to log and exit without writing." xor:"Force,DryRun"`
 	Force      bool   `help:"Force overwriting or deleting data in database." xor:"Force,DryRun"`
 	NoProgress bool   `help:"Do not report progress of long-running commands."`
+	Token      string `help:"Your API token (see the b1gtool pickers token command). Commands that change streaks check that it lets you make the change." env:"B1GPICKEM_TOKEN"`
 }
 
 var CLI struct {
@@ -35,7 +36,8 @@ var CLI struct {
 		// 	BruteForce bruteForceCmd `cmd:"" help:"Perform exhaustive search for the best choice over all possible streaks."`
 		Enumerate  enumerateCmd  `cmd:"" help:"Enumerate all possible streaks."`
 		Posteriors posteriorsCmd `cmd:"" help:"Compute posterior number of wins for a given set of teams."`
-		WhatIf     whatIfCmd     `cmd:"" help:"Compute what would happen between two teams."`
+		WhatIf     whatIfCmd     `cmd:"" help:"Compute what would happen between two teams, or between each pair of teams in a matchups file."`
+		Bracket    bracketCmd    `cmd:"" help:"Simulate a seeded 12-team playoff bracket."`
 	} `cmd:""`
 }
 
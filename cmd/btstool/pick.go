@@ -8,9 +8,10 @@ import (
 )
 
 type makePickCmd struct {
-	Season int               `arg:"" help:"Season to modify. If negative, the current season will be guessed based on today's date." required:""`
-	Week   int               `arg:"" help:"Week to modify. If negative, the current week will be guessed based on today's date." required:""`
-	Picks  map[string]string `arg:"" help:"Mapping of streaker Luke name to comma-separated team other names picked by the streaker for the week. An empty team name value will clear the streaker's pick for the week."`
+	Season   int               `arg:"" help:"Season to modify. If negative, the current season will be guessed based on today's date." required:""`
+	Week     int               `arg:"" help:"Week to modify. If negative, the current week will be guessed based on today's date." required:""`
+	Picks    map[string]string `arg:"" help:"Mapping of streaker Luke name to comma-separated team other names picked by the streaker for the week. An empty team name value will clear the streaker's pick for the week."`
+	Override string            `help:"Commissioner's reason for changing streak picks after they locked. The override is recorded in the week's lock overrides."`
 }
 
 func (a *makePickCmd) Run(g *globalCmd) error {
@@ -22,8 +23,17 @@ func (a *makePickCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	streakers := make([]string, 0, len(a.Picks))
+	for s := range a.Picks {
+		streakers = append(streakers, s)
+	}
+	ctx.Actor, err = g.requireStreakPicksOf(ctx, ctx.FirestoreClient, streakers, a.Override)
+	if err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.Week = a.Week
 	ctx.Picks = a.Picks
+	ctx.Override = a.Override
 	return btspick.MakePicks(ctx)
 }
@@ -21,6 +21,9 @@ func (a *setupWeeksCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requireCommissioner(ctx, ctx.FirestoreClient, "resolve streaks"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.WeekTypes = a.Types
 	return btsweeks.SetWeekTypes(ctx)
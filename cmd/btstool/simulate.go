@@ -2,12 +2,15 @@ package main
 
 import (
 	"context"
+	"fmt"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/bts/bracket"
 	"github.com/reallyasi9/b1gpickem/internal/bts/enumerate"
 	"github.com/reallyasi9/b1gpickem/internal/bts/posteriors"
 	"github.com/reallyasi9/b1gpickem/internal/bts/sa"
 	"github.com/reallyasi9/b1gpickem/internal/bts/whatif"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
 )
 
 type annealCmd struct {
@@ -71,6 +74,8 @@ type posteriorsCmd struct {
 	Seed         int64 `help:"Random seed. Negative values will use the system clock to seed the RNG." default:"-1"`
 	Iterations   int   `help:"Number of seasons to stimulate." short:"i" default:"10000"`
 	Championship bool  `help:"Pit the two highest-performing teams against each other in an extra championship game." short:"c"`
+
+	editteams.ResolverFlags
 }
 
 func (a *posteriorsCmd) Run(g *globalCmd) error {
@@ -88,18 +93,27 @@ func (a *posteriorsCmd) Run(g *globalCmd) error {
 	ctx.Seed = a.Seed
 	ctx.Iterations = a.Iterations
 	ctx.Championship = a.Championship
+	ctx.Resolver = a.Resolver()
 	return posteriors.Posteriors(ctx)
 }
 
 type whatIfCmd struct {
-	Season   int    `arg:"" help:"Season to simulate. If negative, the current season will be guessed based on today's date."`
-	Week     int    `arg:"" help:"Week to simulate. If negative, the current week will be guessed based on today's date."`
-	Team1    string `arg:"" help:"First team to simulate."`
-	Team2    string `arg:"" help:"Second team to simulate."`
-	Location string `help:"Location of game relative to first team (home, near, neutral, far, or away.)" short:"l" enum:"home,near,neutral,far,away" default:"home"`
+	Season     int     `arg:"" help:"Season to simulate. If negative, the current season will be guessed based on today's date."`
+	Week       int     `arg:"" help:"Week to simulate. If negative, the current week will be guessed based on today's date."`
+	Team1      string  `arg:"" optional:"" help:"First team to simulate."`
+	Team2      string  `arg:"" optional:"" help:"Second team to simulate."`
+	Location   string  `help:"Location of game relative to first team (home, near, neutral, far, or away.)" short:"l" enum:"home,near,neutral,far,away" default:"home"`
+	File       string  `help:"CSV file of hypothetical matchups to simulate instead of Team1 and Team2. Columns are team1, team2, location (relative to team1, default neutral), and optional week." short:"f" type:"existingfile"`
+	Model      string  `help:"Team points model used to make predictions." default:"linesag"`
+	Confidence float64 `help:"Confidence level of the spread interval reported when simulating a file of matchups." default:"0.95"`
+
+	editteams.ResolverFlags
 }
 
 func (a *whatIfCmd) Run(g *globalCmd) error {
+	if a.File == "" && (a.Team1 == "" || a.Team2 == "") {
+		return fmt.Errorf("either two teams or a matchups file must be specified")
+	}
 	ctx := whatif.NewContext(context.Background())
 	ctx.DryRun = g.DryRun
 	ctx.Force = g.Force
@@ -113,5 +127,35 @@ func (a *whatIfCmd) Run(g *globalCmd) error {
 	ctx.Team1 = a.Team1
 	ctx.Team2 = a.Team2
 	ctx.Location = a.Location
+	ctx.File = a.File
+	ctx.Model = a.Model
+	ctx.Confidence = a.Confidence
+	ctx.Resolver = a.Resolver()
 	return whatif.WhatIf(ctx)
 }
+
+type bracketCmd struct {
+	Season int    `arg:"" help:"Season to simulate. If negative, the current season will be guessed based on today's date."`
+	Week   int    `arg:"" help:"Week of team ratings to use. If negative, the current week will be guessed based on today's date."`
+	File   string `arg:"" help:"CSV file of seeds and team short names, one seed per line." type:"existingfile"`
+
+	Model      string `help:"Team points model used to make predictions." default:"linesag"`
+	Seed       int64  `help:"Random seed. Negative values will use the system clock to seed the RNG." default:"-1"`
+	Iterations int    `help:"Number of brackets to simulate." short:"i" default:"10000"`
+}
+
+func (a *bracketCmd) Run(g *globalCmd) error {
+	ctx := bracket.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.Week = a.Week
+	ctx.File = a.File
+	ctx.Model = a.Model
+	ctx.Seed = a.Seed
+	ctx.Iterations = a.Iterations
+	return bracket.SimulateBracket(ctx)
+}
@@ -21,6 +21,9 @@ func (a *activateStreakersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requireCommissioner(ctx, ctx.FirestoreClient, "resolve streaks"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.StreakerNames = a.Name
 	return btsstreakers.ActivateStreakers(ctx)
@@ -40,6 +43,9 @@ func (a *deactivateStreakersCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requireCommissioner(ctx, ctx.FirestoreClient, "resolve streaks"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.StreakerNames = a.Name
 	return btsstreakers.DeactivateStreakers(ctx)
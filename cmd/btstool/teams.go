@@ -22,6 +22,9 @@ func (a *addTeamsCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit teams"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.TeamNames = a.Name
 	ctx.Append = !a.DoNotKeep
@@ -42,6 +45,9 @@ func (a *rmTeamsCmd) Run(g *globalCmd) error {
 	if err != nil {
 		return err
 	}
+	if err := g.requireCommissioner(ctx, ctx.FirestoreClient, "edit teams"); err != nil {
+		return err
+	}
 	ctx.Season = a.Season
 	ctx.TeamNames = a.Name
 	return btsteams.RmTeams(ctx)
@@ -5,22 +5,31 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"sort"
+	"math/rand"
+	"time"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/alecthomas/kong"
 	"github.com/reallyasi9/b1gpickem/internal/bts"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/pickem"
 )
 
 type CLI struct {
 	ProjectID        string `help:"GCP project ID." env:"GCP_PROJECT" required:""`
+	Token            string `help:"Your API token (see the b1gtool pickers token command). Pickers can only make their own picks; commissioners can make anyone's and override locks." env:"B1GPICKEM_TOKEN"`
 	StraightUpModel  string `help:"Model to use for straight-up games. Default fallback is to use the model with the most straight-up wins to date, otherwise uses Sagarin scores." short:"s"`
 	NoisySpreadModel string `help:"Model to use for noisy-spread games. Default fallback is to use the straight-up model, otherwise the model with the smallest MAE to date." short:"n"`
 	SuperdogModel    string `help:"Model to use for superdog games. Default fallback is to use the noisy-spread model, otherwise the model with the smallest MAE to date." short:"d"`
 	Fallback         bool   `help:"Use fallback models when specified models are undefined."`
+	AutoSelect       bool   `help:"Use the models stored by 'b1gtool models select' for the week for game types without a specified model."`
+	SuperdogStrategy string `help:"How to choose the superdog: 'ev' picks the most expected points, 'week' picks the best chance of scoring the most points of all pickers this week." enum:"ev,week" default:"ev"`
+	Strategy         string `help:"How to choose all picks: 'expected' picks the most likely winners, 'week' and 'season' pick against the crowd where it improves the chance of finishing first for the week or season. Overrides --superdog-strategy unless 'expected'." enum:"expected,week,season" default:"expected"`
+	Iterations       int    `help:"Number of simulations used to estimate the chance of winning the week or season." default:"10000"`
+	Seed             int64  `help:"Random seed. Negative values will use the system clock to seed the RNG." default:"-1"`
 	DryRun           bool   `help:"Print intended writes to log and exit without updating the database."`
 	Force            bool   `help:"Force overwrite data in the database."`
+	Override         string `help:"Commissioner's reason for changing picks after they locked. The override is recorded with the changes."`
 	Season           int    `arg:"" help:"Season year." required:""`
 	Week             int    `arg:"" help:"Week number." required:""`
 	Picker           string `arg:"" help:"Picker who is making picks." required:""`
@@ -33,30 +42,49 @@ func main() {
 	ctx.FatalIfErrorf(err)
 }
 
-func (cli CLI) Run() error {
+func (cli *CLI) Run() error {
 	ctx := context.Background()
 	fsClient, err := fs.NewClient(ctx, cli.ProjectID)
 	if err != nil {
 		return fmt.Errorf("failed to create Firestore client: %w", err)
 	}
 
+	me, _, err := firestore.Authenticate(ctx, fsClient, cli.Token)
+	if err != nil {
+		return err
+	}
+	if err := me.CheckPicksOf(cli.Picker); err != nil {
+		return err
+	}
+	if cli.Override != "" {
+		if err := me.CheckCommissioner("override locked picks"); err != nil {
+			return err
+		}
+	}
+
 	_, pkRef, err := firestore.GetPickerByLukeName(ctx, fsClient, cli.Picker)
 	if err != nil {
 		return fmt.Errorf("failed to lookup picker '%s': %w", cli.Picker, err)
 	}
 
-	_, seasonRef, err := firestore.GetSeason(ctx, fsClient, cli.Season)
+	season, seasonRef, err := firestore.GetSeason(ctx, fsClient, cli.Season)
 	if err != nil {
 		return fmt.Errorf("failed to determine season from %d: %w", cli.Season, err)
 	}
 	log.Printf("Using season %s", seasonRef.ID)
 
-	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, cli.Week)
+	week, weekRef, err := firestore.GetWeek(ctx, seasonRef, cli.Week)
 	if err != nil {
 		return fmt.Errorf("failed to determine week from %d: %w", cli.Week, err)
 	}
 	log.Printf("Using week %s", weekRef.ID)
 
+	if cli.AutoSelect {
+		if err := cli.autoSelect(ctx, weekRef); err != nil {
+			return err
+		}
+	}
+
 	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
 	if err != nil {
 		return fmt.Errorf("failed to get all games from week %s: %w", weekRef.ID, err)
@@ -125,7 +153,10 @@ func (cli CLI) Run() error {
 	log.Printf("Built Sagarin fallback model %v", model)
 
 	picks := make([]*firestore.Pick, len(sgss))
-	dogs := make([]DogPick, 0)
+	slateGames := make([]pickem.Game, len(sgss))
+	slateRefs := make([]*fs.DocumentRef, len(sgss))
+	trueGames := make([]firestore.Game, len(sgss))
+	mine := make(pickem.Picks)
 	for i, ss := range sgss {
 		var sgame firestore.SlateGame
 		err = ss.DataTo(&sgame)
@@ -166,30 +197,45 @@ func (cli CLI) Run() error {
 			return fmt.Errorf("unable to make pick of slate game %s: %w", sgame, err)
 		}
 
+		slateGames[i] = newSlateGame(ss.Ref, sgame, game, pick)
+		slateRefs[i] = ss.Ref
+		trueGames[i] = game
 		if gt == superdog {
-			// reverse superdog picks
-			if pick.PickedTeam.ID == game.HomeTeam.ID {
-				pick.PickedTeam = game.AwayTeam
-			} else {
-				pick.PickedTeam = game.HomeTeam
-			}
-			pick.PredictedProbability = 1 - pick.PredictedProbability
-			dogs = append(dogs, DogPick{teamID: pick.PickedTeam.ID, points: sgame.Value, prob: pick.PredictedProbability})
+			// only the underdog can be picked in superdog games
+			dog := slateGames[i].Dog
+			pick.PickedTeam = sideTeam(game, dog)
+			pick.PredictedProbability = slateGames[i].P(dog)
 		}
+		mine[ss.Ref.ID] = pickSide(game, pick.PickedTeam)
 		picks[i] = pick
 	}
 
-	// Pick dog by unpicking undogs. Huh.
-	if len(dogs) > 0 {
-		sort.Sort(sort.Reverse(ByValue(dogs)))
-		unpickedDogs := make(map[string]struct{})
-		for _, dog := range dogs[1:] {
-			unpickedDogs[dog.teamID] = struct{}{}
+	seed := cli.Seed
+	if seed < 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	strategy := pickem.Strategy(cli.Strategy)
+	if strategy == pickem.Expected {
+		err = chooseSuperdog(ctx, *cli, season, pkRef, weekRef, picks, mine, slateRefs, trueGames, slateGames, rng)
+		if err != nil {
+			return err
 		}
-		for i, p := range picks {
-			if _, ok := unpickedDogs[p.PickedTeam.ID]; ok {
-				p.PickedTeam = nil
-				picks[i] = p
+	} else {
+		contest, err := newContest(ctx, season, seasonRef, pkRef, weekRef, cli.Week, slateRefs, trueGames, slateGames)
+		if err != nil {
+			return err
+		}
+		eval, err := contest.Choose(strategy, cli.Iterations, rng)
+		if err != nil {
+			return fmt.Errorf("failed to choose picks: %w", err)
+		}
+		printEvaluation(eval, strategy, slateGames, trueGames)
+		for i, ref := range slateRefs {
+			side := eval.Picks[ref.ID]
+			picks[i].PickedTeam = sideTeam(trueGames[i], side)
+			if side != pickem.None {
+				picks[i].PredictedProbability = slateGames[i].P(side)
 			}
 		}
 	}
@@ -209,36 +255,99 @@ func (cli CLI) Run() error {
 		}
 	}
 
+	// picks already made on this slate are overwritten in place
+	stored, storedRefs, err := firestore.GetPicks(ctx, weekRef, pkRef)
+	if err != nil {
+		return fmt.Errorf("failed to get stored picks for picker '%s': %w", cli.Picker, err)
+	}
+	storedBySlateGame := make(map[string]int)
+	for i, p := range stored {
+		if p.SlateGame != nil {
+			storedBySlateGame[p.SlateGame.Path] = i
+		}
+	}
+	picksCollection := weekRef.Collection(firestore.PICKS_COLLECTION)
+	pickRefs := make([]*fs.DocumentRef, len(picks))
+	changes := make([]firestore.PickChange, len(picks))
+	nUpdates := 0
+	for i, pick := range picks {
+		pick.Picker = pkRef
+		if j, ok := storedBySlateGame[pick.SlateGame.Path]; ok {
+			pickRefs[i] = storedRefs[j]
+			changes[i] = firestore.NewPickChange(&stored[j], *pick, storedRefs[j], "pickem4me", me)
+			nUpdates++
+			continue
+		}
+		pickRefs[i] = picksCollection.NewDoc()
+		changes[i] = firestore.NewPickChange(nil, *pick, pickRefs[i], "pickem4me", me)
+	}
+
+	locked, err := firestore.LockedPickChanges(ctx, season.PickLock, week, changes, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to check pick locks: %w", err)
+	}
+	if err := firestore.OverrideLocks(changes, locked, cli.Override); err != nil {
+		return err
+	}
+	lockedErrs := make([]firestore.PickLockedError, 0, len(locked)+1)
+	for i := range changes {
+		if l, ok := locked[i]; ok {
+			log.Printf("Overriding lock: %s", l)
+			lockedErrs = append(lockedErrs, l)
+		}
+	}
+	if sp != nil {
+		if t, ok := season.PickLock.StreakLocked(week, games, sp.PickedTeams, time.Now()); ok {
+			l := firestore.PickLockedError{What: fmt.Sprintf("%s streak pick", pkRef.ID), Locked: t}
+			if cli.Override == "" {
+				return firestore.PickLockedErrors{l}
+			}
+			log.Printf("Overriding lock: %s", l)
+			lockedErrs = append(lockedErrs, l)
+		}
+	}
+
 	// write what I have
 	if cli.DryRun {
 		log.Print("DRY RUN: would write the following to Firestore")
 		for _, p := range picks {
 			log.Printf("%s", p)
 		}
+		log.Printf("DRY RUN: would update %d previously-made picks", nUpdates)
 		log.Printf("Streak pick: %+v", sp)
 		return nil
 	}
 
-	picksCollection := weekRef.Collection(firestore.PICKS_COLLECTION)
+	if nUpdates > 0 && !cli.Force {
+		return fmt.Errorf("refusing to proceed when updating %d picks: add --force flag to force update", nUpdates)
+	}
+
 	streaksCollection := weekRef.Collection(firestore.STREAK_PICKS_COLLECTION)
 
 	err = fsClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
 
-		for _, pick := range picks {
-			pick.Picker = pkRef
-			pickRef := picksCollection.NewDoc()
-			if cli.Force {
-				err = t.Set(pickRef, pick)
+		for i, pick := range picks {
+			var err error
+			if changes[i].Created {
+				err = t.Create(pickRefs[i], pick)
 			} else {
-				err = t.Create(pickRef, pick)
+				err = t.Set(pickRefs[i], pick)
 			}
 			if err != nil {
 				return err
 			}
 		}
+		if err := firestore.RecordPickChanges(t, weekRef, changes); err != nil {
+			return err
+		}
+		if len(lockedErrs) > 0 {
+			if err := firestore.RecordLockOverride(t, weekRef, firestore.NewLockOverride(pkRef, lockedErrs, cli.Override, "pickem4me", me)); err != nil {
+				return err
+			}
+		}
 
 		if sp == nil {
-			return err
+			return nil
 		}
 		spDoc := streaksCollection.NewDoc()
 		if cli.Force {
@@ -257,6 +366,61 @@ func (cli CLI) Run() error {
 	return nil
 }
 
+// autoSelect fills in the models not specified on the command line with the week's model selection.
+func (cli *CLI) autoSelect(ctx context.Context, weekRef *fs.DocumentRef) error {
+	selection, selRef, err := firestore.GetModelSelection(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("failed to get model selection: %w\nHave you run `b1gtool models select` yet?", err)
+	}
+	log.Printf("Using model selection %s", selRef.Path)
+	models := map[firestore.GameType]*string{
+		firestore.StraightUpGame:  &cli.StraightUpModel,
+		firestore.NoisySpreadGame: &cli.NoisySpreadModel,
+		firestore.SuperdogGame:    &cli.SuperdogModel,
+	}
+	for _, t := range firestore.GameTypes {
+		model := models[t]
+		if *model != "" {
+			log.Printf("Using specified model %s for %s games", *model, t)
+			continue
+		}
+		c, ok := selection.Choice(t)
+		if !ok || c.Model == nil {
+			log.Printf("No model selected for %s games", t)
+			continue
+		}
+		*model = c.Model.ID
+		log.Printf("Selected model %s for %s games: %s", c.Model.ID, t, c.Reason)
+	}
+	return nil
+}
+
+// chooseSuperdog picks the superdog according to the superdog strategy, unpicking the others.
+func chooseSuperdog(ctx context.Context, cli CLI, season firestore.Season, me *fs.DocumentRef, weekRef *fs.DocumentRef, picks []*firestore.Pick, mine pickem.Picks, slateRefs []*fs.DocumentRef, trueGames []firestore.Game, slateGames []pickem.Game, rng *rand.Rand) error {
+	var others []pickem.Picks
+	var err error
+	if pickem.SuperdogStrategy(cli.SuperdogStrategy) == pickem.MaxWeekWin {
+		others, err = otherPickers(ctx, season, me, weekRef, slateRefs, trueGames, slateGames)
+		if err != nil {
+			return fmt.Errorf("failed to get other pickers' picks: %w", err)
+		}
+	}
+	options, err := pickem.EvaluateSuperdogs(slateGames, mine, others, pickem.SuperdogStrategy(cli.SuperdogStrategy), cli.Iterations, rng)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate superdogs: %w", err)
+	}
+	if len(options) > 0 {
+		printSuperdogOptions(options, pickem.SuperdogStrategy(cli.SuperdogStrategy), slateRefs, trueGames)
+		// Pick dog by unpicking undogs. Huh.
+		for i, ref := range slateRefs {
+			if slateGames[i].Superdog && ref.ID != options[0].Game {
+				picks[i].PickedTeam = nil
+			}
+		}
+	}
+	return nil
+}
+
 type gameType int
 
 const (
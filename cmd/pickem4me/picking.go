@@ -156,31 +156,3 @@ func (gp GamePredictions) Fallback(sagarinModel *bts.GaussianSpreadModel) (*fire
 
 	return p, nil
 }
-
-type DogPick struct {
-	teamID string
-	points int
-	prob   float64
-}
-
-type ByValue []DogPick
-
-// Len implements Sortable interface
-func (b ByValue) Len() int {
-	return len(b)
-}
-
-// Less implements Sortable interface
-func (b ByValue) Less(i, j int) bool {
-	vi := b[i].prob * float64(b[i].points)
-	vj := b[j].prob * float64(b[j].points)
-	if vi == vj {
-		return b[i].points < b[j].points
-	}
-	return vi < vj
-}
-
-// Swap implements Sortable interface
-func (b ByValue) Swap(i, j int) {
-	b[i], b[j] = b[j], b[i]
-}
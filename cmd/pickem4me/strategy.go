@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/pickem"
+)
+
+const (
+	// defaultChalk is the assumed rate at which pickers pick the favorite before they have made any picks.
+	defaultChalk = .8
+
+	// chalkPriorWeight is the number of picks at defaultChalk assumed for every picker.
+	chalkPriorWeight = 5
+)
+
+// newContest builds the contest for the week from the standings and tendencies of the season's pickers in the weeks before.
+func newContest(ctx context.Context, season firestore.Season, seasonRef *fs.DocumentRef, me *fs.DocumentRef, weekRef *fs.DocumentRef, week int, slateRefs []*fs.DocumentRef, slateGames []firestore.Game, games []pickem.Game) (pickem.Contest, error) {
+	contest := pickem.Contest{Games: games}
+
+	results, err := firestore.GetWeekResults(ctx, seasonRef, week)
+	if err != nil {
+		return contest, fmt.Errorf("failed to get results of previous weeks: %w", err)
+	}
+
+	weeks, _, err := firestore.GetWeeks(ctx, seasonRef)
+	if err != nil {
+		return contest, fmt.Errorf("failed to get weeks: %w", err)
+	}
+	for _, w := range weeks {
+		if w.Number > week {
+			contest.RemainingWeeks++
+		}
+	}
+
+	points := make(map[string]float64)
+	history := make([]pickem.PastGame, 0)
+	for _, r := range results {
+		for picker, score := range r.Scores {
+			points[picker] += float64(score)
+		}
+		for id, picks := range r.Picks {
+			history = append(history, pickem.PastGame{Favorite: r.Favorites[id], Picks: picks})
+		}
+	}
+	chalk := pickem.ChalkRates(history, defaultChalk, chalkPriorWeight)
+
+	// pooled variance of weekly scores about each picker's mean
+	var ss float64
+	var dof int
+	for _, ref := range season.Pickers {
+		mean := weeklyMean(points[ref.ID], len(results))
+		for _, r := range results {
+			d := float64(r.Scores[ref.ID]) - mean
+			ss += d * d
+		}
+		dof += len(results) - 1
+	}
+	if dof > 0 {
+		contest.WeeklyStdDev = math.Sqrt(ss / float64(dof))
+	}
+
+	stored, err := storedPicks(ctx, me, weekRef, slateRefs, slateGames, games)
+	if err != nil {
+		return contest, fmt.Errorf("failed to get other pickers' picks: %w", err)
+	}
+
+	for _, ref := range season.Pickers {
+		if ref.ID == me.ID {
+			contest.Points = points[ref.ID]
+			contest.WeeklyMean = weeklyMean(points[ref.ID], len(results))
+			continue
+		}
+		o := pickem.Opponent{
+			ID:         ref.ID,
+			Picks:      stored[ref.ID],
+			Chalk:      defaultChalk,
+			Points:     points[ref.ID],
+			WeeklyMean: weeklyMean(points[ref.ID], len(results)),
+		}
+		if c, ok := chalk[ref.ID]; ok {
+			o.Chalk = c
+		}
+		contest.Opponents = append(contest.Opponents, o)
+	}
+	return contest, nil
+}
+
+func weeklyMean(points float64, weeks int) float64 {
+	if weeks == 0 {
+		return 0
+	}
+	return points / float64(weeks)
+}
+
+func printEvaluation(eval pickem.Evaluation, strategy pickem.Strategy, games []pickem.Game, slateGames []firestore.Game) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Game", "Expected Pick", "Strategy Pick", "P(Correct)", "Value"})
+	likely := pickem.LikelyPicks(games)
+	for i, g := range games {
+		before, after := likely[g.ID], eval.Picks[g.ID]
+		if before == after {
+			continue
+		}
+		game := slateGames[i]
+		t.AppendRow(table.Row{
+			fmt.Sprintf("%s @ %s", game.AwayTeam.ID, game.HomeTeam.ID),
+			teamString(game, before),
+			teamString(game, after),
+			fmt.Sprintf("%0.3f", g.P(after)),
+			g.Value,
+		})
+	}
+	t.AppendFooter(table.Row{"", "", fmt.Sprintf("P(Win %s)", strategy), fmt.Sprintf("%0.3f -> %0.3f", eval.Baseline, eval.WinProbability), fmt.Sprintf("%0.2f points", eval.ExpectedPoints)})
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+func teamString(game firestore.Game, side pickem.Side) string {
+	if team := sideTeam(game, side); team != nil {
+		return team.ID
+	}
+	return "-"
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/pickem"
+)
+
+// newSlateGame converts a slate game and the model's pick of it into a game for evaluating picks.
+func newSlateGame(ref *fs.DocumentRef, sgame firestore.SlateGame, game firestore.Game, pick *firestore.Pick) pickem.Game {
+	pHome := pick.PredictedProbability
+	if pick.PickedTeam.ID != game.HomeTeam.ID {
+		pHome = 1 - pHome
+	}
+	g := pickem.Game{
+		ID:       ref.ID,
+		Value:    sgame.Value,
+		Superdog: sgame.Superdog,
+		PHome:    pHome,
+	}
+	if sgame.Superdog {
+		g.Dog = pickem.Home
+		if sgame.HomeFavored {
+			g.Dog = pickem.Away
+		}
+	}
+	return g
+}
+
+func sideTeam(game firestore.Game, side pickem.Side) *fs.DocumentRef {
+	switch side {
+	case pickem.Home:
+		return game.HomeTeam
+	case pickem.Away:
+		return game.AwayTeam
+	}
+	return nil
+}
+
+func pickSide(game firestore.Game, team *fs.DocumentRef) pickem.Side {
+	switch {
+	case team == nil:
+		return pickem.None
+	case team.ID == game.HomeTeam.ID:
+		return pickem.Home
+	case team.ID == game.AwayTeam.ID:
+		return pickem.Away
+	}
+	return pickem.None
+}
+
+// storedPicks returns the picks the season's other pickers have already made on the slate, keyed by picker ID.
+// Picks made against older slates are ignored.
+func storedPicks(ctx context.Context, me *fs.DocumentRef, weekRef *fs.DocumentRef, slateRefs []*fs.DocumentRef, slateGames []firestore.Game, games []pickem.Game) (map[string]pickem.Picks, error) {
+	weekPicks, _, err := firestore.GetWeekPicks(ctx, weekRef)
+	if err != nil {
+		return nil, err
+	}
+
+	slateIndex := make(map[string]int)
+	for i, ref := range slateRefs {
+		slateIndex[ref.Path] = i
+	}
+	stored := make(map[string]pickem.Picks)
+	for _, p := range weekPicks {
+		if p.Picker == nil || p.Picker.ID == me.ID || p.SlateGame == nil {
+			continue
+		}
+		i, ok := slateIndex[p.SlateGame.Path]
+		if !ok {
+			// picked against an older slate
+			continue
+		}
+		if _, ok := stored[p.Picker.ID]; !ok {
+			stored[p.Picker.ID] = make(pickem.Picks)
+		}
+		stored[p.Picker.ID][games[i].ID] = pickSide(slateGames[i], p.PickedTeam)
+	}
+	return stored, nil
+}
+
+// otherPickers returns the likely picks of the season's other pickers: the picks they have already made on the slate,
+// and the favorites (and the superdog with the most expected points) for the games they have not picked yet.
+func otherPickers(ctx context.Context, season firestore.Season, me *fs.DocumentRef, weekRef *fs.DocumentRef, slateRefs []*fs.DocumentRef, slateGames []firestore.Game, games []pickem.Game) ([]pickem.Picks, error) {
+	stored, err := storedPicks(ctx, me, weekRef, slateRefs, slateGames, games)
+	if err != nil {
+		return nil, err
+	}
+
+	others := make([]pickem.Picks, 0, len(season.Pickers))
+	for _, ref := range season.Pickers {
+		if ref.ID == me.ID {
+			continue
+		}
+		picks := pickem.LikelyPicks(games)
+		if s, ok := stored[ref.ID]; ok {
+			pickedDog := false
+			for _, g := range games {
+				if g.Superdog && s[g.ID] != pickem.None {
+					pickedDog = true
+				}
+			}
+			for _, g := range games {
+				side, ok := s[g.ID]
+				if g.Superdog && pickedDog {
+					picks[g.ID] = side
+				} else if ok && !g.Superdog {
+					picks[g.ID] = side
+				}
+			}
+		}
+		others = append(others, picks)
+	}
+	return others, nil
+}
+
+func printSuperdogOptions(options []pickem.SuperdogOption, strategy pickem.SuperdogStrategy, slateRefs []*fs.DocumentRef, slateGames []firestore.Game) {
+	gamesByID := make(map[string]firestore.Game)
+	for i, ref := range slateRefs {
+		gamesByID[ref.ID] = slateGames[i]
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{"", "Game", "Underdog", "Value", "P(Win)", "Exp. Points"}
+	if strategy == pickem.MaxWeekWin {
+		header = append(header, "P(Win Week)")
+	}
+	t.AppendHeader(header)
+	for i, o := range options {
+		game := gamesByID[o.Game]
+		picked := ""
+		if i == 0 {
+			picked = "*"
+		}
+		row := table.Row{
+			picked,
+			fmt.Sprintf("%s @ %s", game.AwayTeam.ID, game.HomeTeam.ID),
+			sideTeam(game, o.Dog).ID,
+			o.Value,
+			fmt.Sprintf("%0.3f", o.Probability),
+			fmt.Sprintf("%0.3f", o.ExpectedPoints),
+		}
+		if strategy == pickem.MaxWeekWin {
+			row = append(row, fmt.Sprintf("%0.3f", o.WinProbability))
+		}
+		t.AppendRow(row)
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -38,6 +38,8 @@ var CLI struct {
 	// } `cmd:""`
 
 	Simulate simulateCmd `cmd:"" help:"Simulate games to help pick your pony."`
+
+	WinTotals winTotalsCmd `cmd:"" help:"Compare season win total lines to simulated win distributions."`
 }
 
 func main() {
@@ -9,6 +9,7 @@ import (
 
 type simulateCmd struct {
 	Season int `arg:"" help:"Season to simulate. If negative, the current season will be guessed based on today's date."`
+	Week   int `arg:"" optional:"" help:"Week of team ratings used to simulate the games that remain to be played. If negative, the current week will be guessed based on today's date." default:"-1"`
 
 	Seed       int64 `help:"Random seed. Negative values will use the system clock to seed the RNG." default:"-1"`
 	Workers    int   `help:"Number of season workers per simulation." short:"n" default:"1"`
@@ -25,6 +26,7 @@ func (a *simulateCmd) Run(g *globalCmd) error {
 		return err
 	}
 	ctx.Season = a.Season
+	ctx.Week = a.Week
 	ctx.Seed = a.Seed
 	ctx.Workers = a.Workers
 	ctx.Iterations = a.Iterations
@@ -14,6 +14,7 @@ type addTeamsCmd struct {
 	Season    int      `arg:"" help:"Season to modify. If negative, the current season will be guessed based on today's date."`
 	TeamWin   []string `arg:"" help:"Teams and pre-season predicted wins to add to the competition. Add in OtherName:PreseasonWins format, with negative PreseasonWins for top 25 teams."`
 	DoNotKeep bool     `help:"Remove all teams from competition that are not supplied to this command."`
+	Poll      string   `help:"Name of a stored poll: teams ranked in its preseason ranking are added as top 25 ponies regardless of the sign of their wins."`
 }
 
 func parseTeamWin(s string) (string, float64, error) {
@@ -50,6 +51,7 @@ func (a *addTeamsCmd) Run(g *globalCmd) error {
 	}
 	ctx.TeamNameWins = teamWins
 	ctx.Append = !a.DoNotKeep
+	ctx.Poll = a.Poll
 	return pypteams.AddTeams(ctx)
 }
 
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/bts/wintotals"
+)
+
+type winTotalsCmd struct {
+	Season int    `arg:"" help:"Season to simulate. If negative, the current season will be guessed based on today's date."`
+	File   string `arg:"" help:"CSV file of win total lines. Columns are team short name, total, over price, and under price (American odds)." type:"existingfile"`
+
+	Week          int     `help:"Week of team ratings to use and from which to simulate the schedule. Wins in earlier weeks are added to the simulated wins. If negative, the first week of the season is used." default:"-1"`
+	Model         string  `help:"Team points model used to make predictions." default:"linesag"`
+	Iterations    int     `help:"Number of seasons to simulate." short:"i" default:"10000"`
+	KellyFraction float64 `help:"Fraction of the full Kelly stake to recommend." default:"0.25"`
+	Bankroll      float64 `help:"Bankroll used to convert Kelly fractions to stakes." default:"100"`
+}
+
+func (a *winTotalsCmd) Run(g *globalCmd) error {
+	ctx := wintotals.NewContext(context.Background())
+	var err error
+	ctx.FirestoreClient, err = fs.NewClient(ctx.Context, g.ProjectID)
+	if err != nil {
+		return err
+	}
+	ctx.Season = a.Season
+	ctx.File = a.File
+	ctx.Week = a.Week
+	ctx.Model = a.Model
+	ctx.Iterations = a.Iterations
+	ctx.KellyFraction = a.KellyFraction
+	ctx.Bankroll = a.Bankroll
+	return wintotals.WinTotals(ctx)
+}
@@ -0,0 +1,148 @@
+package bracket
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/reallyasi9/b1gpickem/internal/bts"
+)
+
+// Round is a round of the playoff bracket.
+type Round int
+
+const (
+	// FirstRound is played on the campus of the higher seed. The top four seeds have a bye.
+	FirstRound Round = iota
+	// Quarterfinal is played at a neutral site.
+	Quarterfinal
+	// Semifinal is played at a neutral site.
+	Semifinal
+	// Final is played at a neutral site.
+	Final
+	// Champion is not a game, but the team that wins the final reaches it.
+	Champion
+)
+
+// NRounds is the number of rounds a team can reach, including Champion.
+const NRounds = int(Champion) + 1
+
+// NTeams is the number of teams in the bracket.
+const NTeams = 12
+
+// NByes is the number of top seeds that skip the first round.
+const NByes = 4
+
+func (r Round) String() string {
+	switch r {
+	case FirstRound:
+		return "First Round"
+	case Quarterfinal:
+		return "Quarterfinal"
+	case Semifinal:
+		return "Semifinal"
+	case Final:
+		return "Final"
+	case Champion:
+		return "Champion"
+	}
+	return fmt.Sprintf("Round(%d)", int(r))
+}
+
+// Bracket is a 12-team College Football Playoff bracket.
+type Bracket struct {
+	// seeds are the teams in seed order: seeds[0] is the 1 seed.
+	seeds []bts.Team
+}
+
+// NewBracket makes a bracket from teams listed in seed order.
+func NewBracket(seeds []bts.Team) (*Bracket, error) {
+	if len(seeds) != NTeams {
+		return nil, fmt.Errorf("bracket requires %d teams, got %d", NTeams, len(seeds))
+	}
+	seen := make(map[bts.Team]struct{})
+	for _, t := range seeds {
+		if _, ok := seen[t]; ok {
+			return nil, fmt.Errorf("team %s seeded more than once", t)
+		}
+		seen[t] = struct{}{}
+	}
+	s := make([]bts.Team, NTeams)
+	copy(s, seeds)
+	return &Bracket{seeds: s}, nil
+}
+
+// Seed returns the team with the given seed (starting at 1).
+func (b *Bracket) Seed(seed int) bts.Team {
+	return b.seeds[seed-1]
+}
+
+// Play simulates the bracket once and returns the furthest round reached by each team.
+func (b *Bracket) Play(model bts.PredictionModel, rng *rand.Rand) map[bts.Team]Round {
+	reached := make(map[bts.Team]Round)
+	for _, t := range b.seeds {
+		reached[t] = FirstRound
+	}
+
+	play := func(t1, t2 bts.Team, loc bts.RelativeLocation, round Round) bts.Team {
+		p, _ := model.Predict(bts.NewGame(t1, t2, loc))
+		winner := t2
+		if rng.Float64() < p {
+			winner = t1
+		}
+		reached[winner] = round
+		return winner
+	}
+
+	// First round: 5 v 12, 6 v 11, 7 v 10, 8 v 9 on the higher seed's campus.
+	// Byes put the top four seeds straight into the quarterfinals.
+	quarterfinalists := make([]bts.Team, NTeams-NByes)
+	for i := 0; i < NByes; i++ {
+		high := b.Seed(i + 1)
+		reached[high] = Quarterfinal
+		home := b.Seed(NByes + i + 1)
+		away := b.Seed(NTeams - i)
+		quarterfinalists[i] = high
+		quarterfinalists[NByes+i] = play(home, away, bts.Home, Quarterfinal)
+	}
+
+	// Quarterfinals: 1 v 8/9, 2 v 7/10, 3 v 6/11, 4 v 5/12.
+	semifinalists := make([]bts.Team, NByes)
+	for i := 0; i < NByes; i++ {
+		semifinalists[i] = play(quarterfinalists[i], quarterfinalists[NTeams-NByes-1-i], bts.Neutral, Semifinal)
+	}
+
+	// Semifinals: the 1 and 4 quadrants meet, as do the 2 and 3 quadrants.
+	finalist1 := play(semifinalists[0], semifinalists[3], bts.Neutral, Final)
+	finalist2 := play(semifinalists[1], semifinalists[2], bts.Neutral, Final)
+
+	play(finalist1, finalist2, bts.Neutral, Champion)
+
+	return reached
+}
+
+// Probabilities are the probabilities that each team reaches each round, indexed by Round.
+type Probabilities map[bts.Team][NRounds]float64
+
+// Simulate plays the bracket `iterations` times and returns the probability that each team reaches each round.
+func (b *Bracket) Simulate(model bts.PredictionModel, iterations int, rng *rand.Rand) Probabilities {
+	counts := make(map[bts.Team][NRounds]int)
+	for itr := 0; itr < iterations; itr++ {
+		for team, round := range b.Play(model, rng) {
+			c := counts[team]
+			for r := FirstRound; r <= round; r++ {
+				c[r]++
+			}
+			counts[team] = c
+		}
+	}
+
+	probs := make(Probabilities)
+	for team, c := range counts {
+		var p [NRounds]float64
+		for r, n := range c {
+			p[r] = float64(n) / float64(iterations)
+		}
+		probs[team] = p
+	}
+	return probs
+}
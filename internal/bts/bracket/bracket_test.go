@@ -0,0 +1,108 @@
+package bracket
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/reallyasi9/b1gpickem/internal/bts"
+)
+
+// seedModel always picks the better (lower) seed to win, except that the home team always wins.
+type seedModel struct {
+	seeds map[bts.Team]int
+}
+
+func (m seedModel) Predict(g *bts.Game) (float64, float64) {
+	if g.LocationRelativeToTeam(0) == bts.Home {
+		return 1, 1
+	}
+	if m.seeds[g.Team(0)] < m.seeds[g.Team(1)] {
+		return 1, 1
+	}
+	return 0, -1
+}
+
+func (m seedModel) MostLikelyOutcome(g *bts.Game) (bts.Team, float64, float64) {
+	p, s := m.Predict(g)
+	if p > .5 {
+		return g.Team(0), p, s
+	}
+	return g.Team(1), 1 - p, -s
+}
+
+func (m seedModel) PredictNoisySpread(g *bts.Game, _ float64) (float64, float64) {
+	return m.Predict(g)
+}
+
+func (m seedModel) MostLikelyNoisySpreadOutcome(g *bts.Game, _ float64) (bts.Team, float64, float64) {
+	return m.MostLikelyOutcome(g)
+}
+
+func makeSeeds() ([]bts.Team, seedModel) {
+	teams := make([]bts.Team, NTeams)
+	model := seedModel{seeds: make(map[bts.Team]int)}
+	for i := range teams {
+		teams[i] = bts.Team(fmt.Sprintf("seed%02d", i+1))
+		model.seeds[teams[i]] = i + 1
+	}
+	return teams, model
+}
+
+func TestNewBracket(t *testing.T) {
+	teams, _ := makeSeeds()
+	if _, err := NewBracket(teams[:NTeams-1]); err == nil {
+		t.Error("expected error with too few teams")
+	}
+	dup := make([]bts.Team, NTeams)
+	copy(dup, teams)
+	dup[NTeams-1] = dup[0]
+	if _, err := NewBracket(dup); err == nil {
+		t.Error("expected error with duplicate teams")
+	}
+}
+
+func TestPlay(t *testing.T) {
+	teams, model := makeSeeds()
+	b, err := NewBracket(teams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reached := b.Play(model, rand.New(rand.NewSource(0)))
+
+	want := map[int]Round{
+		1: Champion, 2: Final, 3: Semifinal, 4: Semifinal,
+		5: Quarterfinal, 6: Quarterfinal, 7: Quarterfinal, 8: Quarterfinal,
+		9: FirstRound, 10: FirstRound, 11: FirstRound, 12: FirstRound,
+	}
+	for seed, round := range want {
+		if got := reached[b.Seed(seed)]; got != round {
+			t.Errorf("seed %d: expected %s, got %s", seed, round, got)
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	teams, model := makeSeeds()
+	b, err := NewBracket(teams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probs := b.Simulate(model, 100, rand.New(rand.NewSource(0)))
+
+	for seed := 1; seed <= NTeams; seed++ {
+		p := probs[b.Seed(seed)]
+		if p[FirstRound] != 1 {
+			t.Errorf("seed %d: expected to reach first round with probability 1, got %f", seed, p[FirstRound])
+		}
+	}
+	if p := probs[b.Seed(1)][Champion]; p != 1 {
+		t.Errorf("seed 1: expected to win the title with probability 1, got %f", p)
+	}
+	if p := probs[b.Seed(2)][Champion]; p != 0 {
+		t.Errorf("seed 2: expected to win the title with probability 0, got %f", p)
+	}
+	if p := probs[b.Seed(9)][Quarterfinal]; p != 0 {
+		t.Errorf("seed 9: expected to reach the quarterfinals with probability 0, got %f", p)
+	}
+}
@@ -0,0 +1,23 @@
+package bracket
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+	FirestoreClient *fs.Client
+
+	Season     int
+	Week       int
+	File       string
+	Model      string
+	Seed       int64
+	Iterations int
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
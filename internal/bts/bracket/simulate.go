@@ -0,0 +1,168 @@
+package bracket
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/bts"
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// SimulateBracket reads a seeded bracket from a file and simulates it using the team ratings from the given week.
+func SimulateBracket(ctx *Context) error {
+	log.Print("Simulating playoff bracket")
+
+	fs := ctx.FirestoreClient
+
+	// Get season
+	_, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: unable to get season: %w", err)
+	}
+
+	// Get week
+	_, weekRef, err := bpefs.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: unable to get week: %w", err)
+	}
+
+	// Build the probability model
+	modelName := ctx.Model
+	if modelName == "" {
+		modelName = "linesag"
+	}
+	model, err := bts.GetGaussianSpreadModel(ctx, fs, weekRef, modelName)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: unable to build model: %w", err)
+	}
+
+	// Get teams
+	teamsByName, err := bpefs.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: unable to retrieve team references: %w", err)
+	}
+
+	// Read the bracket
+	names, err := ReadSeeds(ctx.File)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: unable to read bracket from '%s': %w", ctx.File, err)
+	}
+	seeds := make([]bts.Team, len(names))
+	namesByTeam := make(map[bts.Team]string)
+	for i, name := range names {
+		ref, err := teamsByName.Resolve(name, bpefs.ShortName)
+		if err != nil {
+			return fmt.Errorf("SimulateBracket: seed %d: %w", i+1, err)
+		}
+		seeds[i] = bts.Team(ref.ID)
+		namesByTeam[seeds[i]] = name
+	}
+	bracket, err := NewBracket(seeds)
+	if err != nil {
+		return fmt.Errorf("SimulateBracket: %w", err)
+	}
+
+	// Simulate
+	sd := ctx.Seed
+	if sd < 0 {
+		sd = time.Now().UnixNano()
+	}
+	iterations := ctx.Iterations
+	if iterations <= 0 {
+		iterations = 10000
+	}
+	log.Printf("Simulating bracket %d times", iterations)
+	probs := bracket.Simulate(model, iterations, rand.New(rand.NewSource(sd)))
+
+	prettyPrint(bracket, probs, namesByTeam)
+
+	log.Print("Done")
+
+	return nil
+}
+
+// ReadSeeds reads a seeded bracket from a CSV file with columns seed and team (short name).
+// Blank lines, lines starting with '#', and a header line starting with "seed" are ignored.
+// The team names are returned in seed order.
+func ReadSeeds(fileName string) ([]string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, NTeams)
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "seed") {
+			continue
+		}
+		seed, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: unable to parse seed '%s': %w", i+1, record[0], err)
+		}
+		if seed < 1 || seed > NTeams {
+			return nil, fmt.Errorf("line %d: seed %d out of range [1, %d]", i+1, seed, NTeams)
+		}
+		if names[seed-1] != "" {
+			return nil, fmt.Errorf("line %d: seed %d assigned more than once", i+1, seed)
+		}
+		names[seed-1] = strings.TrimSpace(record[1])
+	}
+	for i, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("seed %d not assigned", i+1)
+		}
+	}
+	return names, nil
+}
+
+func prettyPrint(b *Bracket, probs Probabilities, namesByTeam map[bts.Team]string) {
+	type row struct {
+		seed int
+		team bts.Team
+	}
+	rows := make([]row, NTeams)
+	for i := range rows {
+		rows[i] = row{seed: i + 1, team: b.Seed(i + 1)}
+	}
+	// Most likely champions first, ties broken by seed.
+	sort.SliceStable(rows, func(i, j int) bool {
+		return probs[rows[i].team][Champion] > probs[rows[j].team][Champion]
+	})
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	header := table.Row{"Seed", "Team"}
+	for r := Quarterfinal; r <= Champion; r++ {
+		header = append(header, r.String())
+	}
+	t.AppendHeader(header)
+	for _, r := range rows {
+		p := probs[r.team]
+		tr := table.Row{r.seed, namesByTeam[r.team]}
+		for rd := Quarterfinal; rd <= Champion; rd++ {
+			tr = append(tr, fmt.Sprintf("%0.4f", p[rd]))
+		}
+		t.AppendRow(tr)
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -1,6 +1,9 @@
 package bts
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // RelativeLocation describes where a game is being played relative to one team's home field.
 type RelativeLocation int
@@ -22,19 +25,74 @@ const (
 	Away RelativeLocation = -2
 )
 
+// String implements the Stringer interface.
+func (l RelativeLocation) String() string {
+	switch l {
+	case Home:
+		return "home"
+	case Near:
+		return "near"
+	case Neutral:
+		return "neutral"
+	case Far:
+		return "far"
+	case Away:
+		return "away"
+	}
+	return fmt.Sprintf("RelativeLocation(%d)", int(l))
+}
+
+// ParseRelativeLocation parses a location name (home, near, neutral, far, or away) into a RelativeLocation.
+// An empty string is parsed as Neutral.
+func ParseRelativeLocation(s string) (RelativeLocation, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "home":
+		return Home, nil
+	case "near":
+		return Near, nil
+	case "neutral", "":
+		return Neutral, nil
+	case "far":
+		return Far, nil
+	case "away":
+		return Away, nil
+	}
+	return Neutral, fmt.Errorf("unrecognized location '%s'", s)
+}
+
 // Game represents a matchup between two teams.
 type Game struct {
 	team1    Team
 	team2    Team
 	location RelativeLocation
+	// advantage is the fraction of a home advantage held by team1 (positive) or team2 (negative).
+	advantage float64
 }
 
 // NULLGAME represents a game that doesn't exsit.  Go figure.
-var NULLGAME = Game{NONE, NONE, Neutral}
+var NULLGAME = Game{NONE, NONE, Neutral, 0}
 
 // NewGame makes a game between two teams.
+// The home advantage is graded by location: the full advantage at Home, half at Near, and none at Neutral.
 func NewGame(team1, team2 Team, locRelTeam1 RelativeLocation) *Game {
-	return &Game{team1: team1, team2: team2, location: locRelTeam1}
+	return &Game{team1: team1, team2: team2, location: locRelTeam1, advantage: float64(locRelTeam1) / float64(Home)}
+}
+
+// NewNeutralSiteGame makes a game between two teams at a neutral site, grading the location and home advantage by travel.
+func NewNeutralSiteGame(team1, team2 Team, travel Travel) *Game {
+	return &Game{team1: team1, team2: team2, location: travel.Location(), advantage: travel.Advantage()}
+}
+
+// Advantage returns the fraction of a home advantage held by the given team, from -1 (away) to 1 (home).
+func (g *Game) Advantage(t int) float64 {
+	switch t {
+	case 0:
+		return g.advantage
+	case 1:
+		return -g.advantage
+	default:
+		panic(fmt.Errorf("team %d is not a valid team", t))
+	}
 }
 
 // Team returns a given team.
@@ -65,6 +123,7 @@ func (g *Game) LocationRelativeToTeam(t int) RelativeLocation {
 func (g *Game) SwapTeams() {
 	g.team1, g.team2 = g.team2, g.team1
 	g.location = -g.location
+	g.advantage = -g.advantage
 }
 
 // EqualTo determines if to Game objects refer to the same actual matchup.
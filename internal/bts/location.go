@@ -0,0 +1,169 @@
+package bts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// EarthRadiusMiles is the mean radius of the Earth in miles.
+const EarthRadiusMiles = 3958.8
+
+// NearMiles is the greatest distance a team can travel to a neutral site and still be considered Near.
+const NearMiles = 300.
+
+// AdvantageScaleMiles is the difference in travel distance at which a neutral site advantage reaches its maximum.
+const AdvantageScaleMiles = 1500.
+
+// MaxNeutralAdvantage is the largest fraction of a team's home advantage that can be earned at a neutral site.
+const MaxNeutralAdvantage = .5
+
+// TimezoneAdvantage is the fraction of a team's home advantage earned for each time zone the opponent crosses beyond the team.
+const TimezoneAdvantage = .05
+
+// Travel describes how far each team in a game has to travel from their home venue to the game's venue.
+type Travel struct {
+	// Miles are the great-circle distances traveled by each team.
+	Miles [2]float64
+	// Timezones are the number of time zones crossed by each team.
+	Timezones [2]int
+}
+
+// Distance returns the great-circle distance in miles between two [latitude, longitude] pairs given in degrees.
+func Distance(latLon1, latLon2 []float64) float64 {
+	lat1 := latLon1[0] * math.Pi / 180
+	lat2 := latLon2[0] * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (latLon2[1] - latLon1[1]) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusMiles * math.Asin(math.Sqrt(a))
+}
+
+// TimezonesCrossed returns the number of whole hours between the UTC offsets of two IANA time zones at the given time.
+// Unknown time zones are treated as crossing no time zones.
+func TimezonesCrossed(tz1, tz2 string, t time.Time) int {
+	loc1, err := time.LoadLocation(tz1)
+	if err != nil || tz1 == "" {
+		return 0
+	}
+	loc2, err := time.LoadLocation(tz2)
+	if err != nil || tz2 == "" {
+		return 0
+	}
+	_, off1 := t.In(loc1).Zone()
+	_, off2 := t.In(loc2).Zone()
+	d := (off2 - off1) / 3600
+	if d < 0 {
+		d = -d
+	}
+	return d
+}
+
+// Advantage returns the fraction of a home advantage that the first team has over the second team in a game at a neutral site.
+// Positive values favor the first team, negative values favor the second team.
+// The advantage grows with the difference in miles traveled and time zones crossed, and never exceeds MaxNeutralAdvantage.
+func (t Travel) Advantage() float64 {
+	adv := MaxNeutralAdvantage * math.Tanh((t.Miles[1]-t.Miles[0])/AdvantageScaleMiles)
+	adv += TimezoneAdvantage * float64(t.Timezones[1]-t.Timezones[0])
+	return math.Max(-MaxNeutralAdvantage, math.Min(MaxNeutralAdvantage, adv))
+}
+
+// Location returns the location of a neutral site relative to the first team.
+// The site is Near the first team if the first team travels no more than NearMiles and less than half as far as the second team,
+// Far if the opposite is true, and Neutral otherwise.
+func (t Travel) Location() RelativeLocation {
+	switch {
+	case t.Miles[0] <= NearMiles && t.Miles[0] < t.Miles[1]/2:
+		return Near
+	case t.Miles[1] <= NearMiles && t.Miles[1] < t.Miles[0]/2:
+		return Far
+	}
+	return Neutral
+}
+
+// Locator looks up and caches teams' home venues and game venues to calculate travel to neutral site games.
+type Locator struct {
+	homeVenues map[string]*firestore.DocumentRef
+	venues     map[string]bpefs.Venue
+}
+
+// NewLocator makes an empty Locator.
+func NewLocator() *Locator {
+	return &Locator{
+		homeVenues: make(map[string]*firestore.DocumentRef),
+		venues:     make(map[string]bpefs.Venue),
+	}
+}
+
+// Travel calculates the travel of the home team (first) and the away team (second) to the venue of a game.
+// If any of the venues are unknown or are missing coordinates, returns false.
+func (l *Locator) Travel(ctx context.Context, game bpefs.Game) (Travel, bool, error) {
+	var travel Travel
+	if game.Venue == nil || game.HomeTeam == nil || game.AwayTeam == nil {
+		return travel, false, nil
+	}
+	site, err := l.venue(ctx, game.Venue)
+	if err != nil {
+		return travel, false, err
+	}
+	if len(site.LatLon) < 2 {
+		return travel, false, nil
+	}
+	for i, teamRef := range []*firestore.DocumentRef{game.HomeTeam, game.AwayTeam} {
+		homeRef, err := l.homeVenue(ctx, teamRef)
+		if err != nil {
+			return travel, false, err
+		}
+		if homeRef == nil {
+			return travel, false, nil
+		}
+		home, err := l.venue(ctx, homeRef)
+		if err != nil {
+			return travel, false, err
+		}
+		if len(home.LatLon) < 2 {
+			return travel, false, nil
+		}
+		travel.Miles[i] = Distance(home.LatLon, site.LatLon)
+		travel.Timezones[i] = TimezonesCrossed(home.Timezone, site.Timezone, game.StartTime)
+	}
+	return travel, true, nil
+}
+
+func (l *Locator) homeVenue(ctx context.Context, teamRef *firestore.DocumentRef) (*firestore.DocumentRef, error) {
+	if ref, ok := l.homeVenues[teamRef.Path]; ok {
+		return ref, nil
+	}
+	snap, err := teamRef.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get team %s: %w", teamRef.ID, err)
+	}
+	var team bpefs.Team
+	if err := snap.DataTo(&team); err != nil {
+		return nil, fmt.Errorf("unable to get team %s data: %w", teamRef.ID, err)
+	}
+	l.homeVenues[teamRef.Path] = team.Venue
+	return team.Venue, nil
+}
+
+func (l *Locator) venue(ctx context.Context, venueRef *firestore.DocumentRef) (bpefs.Venue, error) {
+	if v, ok := l.venues[venueRef.Path]; ok {
+		return v, nil
+	}
+	var v bpefs.Venue
+	snap, err := venueRef.Get(ctx)
+	if err != nil {
+		return v, fmt.Errorf("unable to get venue %s: %w", venueRef.ID, err)
+	}
+	if err := snap.DataTo(&v); err != nil {
+		return v, fmt.Errorf("unable to get venue %s data: %w", venueRef.ID, err)
+	}
+	l.venues[venueRef.Path] = v
+	return v, nil
+}
@@ -0,0 +1,96 @@
+package bts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   []float64
+		want   float64
+		within float64
+	}{
+		{"same", []float64{40, -88}, []float64{40, -88}, 0, 1e-9},
+		// Memorial Stadium (Champaign) to Ross-Ade Stadium (West Lafayette): about 75 miles.
+		{"champaign-lafayette", []float64{40.0992, -88.2360}, []float64{40.4347, -86.9182}, 74, 5},
+		// One degree of latitude is about 69 miles.
+		{"one degree", []float64{0, 0}, []float64{1, 0}, 69.1, .1},
+	}
+	for _, test := range tests {
+		if got := Distance(test.a, test.b); math.Abs(got-test.want) > test.within {
+			t.Errorf("%s: expected %f, got %f", test.name, test.want, got)
+		}
+	}
+}
+
+func TestTimezonesCrossed(t *testing.T) {
+	when := time.Date(2022, 9, 1, 12, 0, 0, 0, time.UTC)
+	if got := TimezonesCrossed("America/Chicago", "America/Los_Angeles", when); got != 2 {
+		t.Errorf("Chicago to Los Angeles: expected 2, got %d", got)
+	}
+	if got := TimezonesCrossed("America/New_York", "America/New_York", when); got != 0 {
+		t.Errorf("New York to New York: expected 0, got %d", got)
+	}
+	if got := TimezonesCrossed("", "America/New_York", when); got != 0 {
+		t.Errorf("unknown to New York: expected 0, got %d", got)
+	}
+}
+
+func TestTravel(t *testing.T) {
+	tests := []struct {
+		name     string
+		travel   Travel
+		location RelativeLocation
+		positive bool
+	}{
+		{"equal", Travel{Miles: [2]float64{500, 500}}, Neutral, false},
+		{"near", Travel{Miles: [2]float64{50, 1200}, Timezones: [2]int{0, 2}}, Near, true},
+		{"far", Travel{Miles: [2]float64{1200, 50}, Timezones: [2]int{2, 0}}, Far, false},
+		{"both distant", Travel{Miles: [2]float64{800, 2000}}, Neutral, true},
+	}
+	for _, test := range tests {
+		if got := test.travel.Location(); got != test.location {
+			t.Errorf("%s: expected location %s, got %s", test.name, test.location, got)
+		}
+		adv := test.travel.Advantage()
+		if math.Abs(adv) > MaxNeutralAdvantage {
+			t.Errorf("%s: advantage %f exceeds maximum %f", test.name, adv, MaxNeutralAdvantage)
+		}
+		if test.positive != (adv > 0) {
+			t.Errorf("%s: expected positive advantage %t, got %f", test.name, test.positive, adv)
+		}
+	}
+}
+
+func TestSpreadAdvantage(t *testing.T) {
+	ratings := map[string]bpefs.ModelTeamPoints{
+		"a": {Points: 10, HomeAdvantage: 4},
+		"b": {Points: 10, HomeAdvantage: 2},
+	}
+	m := NewGaussianSpreadModel(ratings, bpefs.ModelPerformance{StdDev: 1})
+	tests := []struct {
+		location RelativeLocation
+		want     float64
+	}{
+		{Home, 4},
+		{Near, 2},
+		{Neutral, 0},
+		{Far, -1},
+		{Away, -2},
+	}
+	for _, test := range tests {
+		g := NewGame("a", "b", test.location)
+		if got := m.spread(g); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("%s: expected spread %f, got %f", test.location, test.want, got)
+		}
+		g.SwapTeams()
+		if got := m.spread(g); math.Abs(got+test.want) > 1e-9 {
+			t.Errorf("%s swapped: expected spread %f, got %f", test.location, -test.want, got)
+		}
+	}
+}
@@ -1,10 +1,13 @@
 package bts
 
 import (
+	"context"
 	"fmt"
 
+	"cloud.google.com/go/firestore"
 	"github.com/atgjack/prob"
 	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+	"gonum.org/v1/gonum/stat/distuv"
 )
 
 // PredictionModel describes an object that can predict the probability a given team will defeat another team, or the point spread if those teams were to play.
@@ -27,6 +30,43 @@ func NewGaussianSpreadModel(ratings map[string]bpefs.ModelTeamPoints, perf bpefs
 	return &GaussianSpreadModel{ratings: ratings, dist: prob.Normal{Mu: perf.Bias, Sigma: perf.StdDev}}
 }
 
+// GetGaussianSpreadModel builds a GaussianSpreadModel from the team points and the most recent performance of the given model in the given week.
+// Only models that predict team points directly (the Sagarin family of models, e.g. "linesag") can be used.
+func GetGaussianSpreadModel(ctx context.Context, client *firestore.Client, week *firestore.DocumentRef, modelID string) (*GaussianSpreadModel, error) {
+	// I can cheat because I know where the team points are stored.
+	pointsRef := week.Collection("team-points").Doc("sagarin")
+	snaps, err := pointsRef.Collection(modelID).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get team points for model '%s': %w", modelID, err)
+	}
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("no team points found for model '%s' in week %s", modelID, week.ID)
+	}
+	ratings := make(map[string]bpefs.ModelTeamPoints)
+	for _, s := range snaps {
+		var points bpefs.ModelTeamPoints
+		if err := s.DataTo(&points); err != nil {
+			return nil, fmt.Errorf("unable to get team points for model '%s': %w", modelID, err)
+		}
+		// Sagarin has one nil team representing a non-recorded team. Don't keep that one.
+		if points.Team == nil {
+			continue
+		}
+		ratings[points.Team.ID] = points
+	}
+
+	performances, _, err := bpefs.GetMostRecentModelPerformances(ctx, client, week)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get model performances: %w", err)
+	}
+	for _, perf := range performances {
+		if perf.Model.ID == modelID {
+			return NewGaussianSpreadModel(ratings, perf), nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find most recent performance of model '%s' in week %s", modelID, week.ID)
+}
+
 // Predict returns the probability and spread for team1.
 func (m GaussianSpreadModel) Predict(game *Game) (float64, float64) {
 	if game.Team(0) == BYE || game.Team(1) == BYE {
@@ -56,6 +96,18 @@ func (m GaussianSpreadModel) PredictNoisySpread(game *Game, noisySpread float64)
 	return prob, spread
 }
 
+// PredictInterval returns the central interval of team1's scoring margin at the given confidence level (between 0 and 1).
+func (m GaussianSpreadModel) PredictInterval(game *Game, confidence float64) (lo float64, hi float64) {
+	if game.Team(0) == BYE || game.Team(1) == BYE || game.Team(0) == NONE || game.Team(1) == NONE {
+		return 0., 0.
+	}
+	// The model predicts team1 wins when the error drawn from dist is less than the spread,
+	// so the margin is distributed with mean (spread - bias) and the same standard deviation.
+	dist := distuv.Normal{Mu: m.spread(game) - m.dist.Mu, Sigma: m.dist.Sigma}
+	tail := (1 - confidence) / 2
+	return dist.Quantile(tail), dist.Quantile(1 - tail)
+}
+
 // MostLikelyOutcome returns the most likely team to win a given game, the probability of win, and the predicted spread.
 func (m GaussianSpreadModel) MostLikelyOutcome(game *Game) (Team, float64, float64) {
 	if game.Team(0) == BYE || game.Team(1) == BYE {
@@ -88,16 +140,13 @@ func (m GaussianSpreadModel) MostLikelyNoisySpreadOutcome(game *Game, noisySprea
 
 func (m GaussianSpreadModel) spread(game *Game) float64 {
 	diff := m.ratings[string(game.Team(0))].Points - m.ratings[string(game.Team(1))].Points
+	// Each team's home advantage is scaled by how much of it the team holds at the game's location.
+	adv := game.Advantage(0)
 	homeAdv := 0.
-	switch game.LocationRelativeToTeam(0) {
-	case Home:
-		homeAdv = m.ratings[string(game.Team(0))].HomeAdvantage
-	case Near:
-		homeAdv = m.ratings[string(game.Team(0))].HomeAdvantage / 2.
-	case Far:
-		homeAdv = -m.ratings[string(game.Team(1))].HomeAdvantage / .2
-	case Away:
-		homeAdv = -m.ratings[string(game.Team(1))].HomeAdvantage
+	if adv > 0 {
+		homeAdv = adv * m.ratings[string(game.Team(0))].HomeAdvantage
+	} else if adv < 0 {
+		homeAdv = adv * m.ratings[string(game.Team(1))].HomeAdvantage
 	}
 	diff += homeAdv
 	return diff
@@ -110,36 +159,33 @@ func (m GaussianSpreadModel) String() string {
 // OracleModel represents a Model that knows who won each matchup, so always returns a probability of win of either 1 or 0,
 // and a spread equal to the scoring margin in the game.
 type OracleModel struct {
-	results map[Game]float64
+	results map[matchup]float64
+}
+
+// matchup is the key of an OracleModel result.
+// Only the teams are used so that a game looks up the same result no matter how its location was graded.
+type matchup struct {
+	team1 Team
+	team2 Team
+}
+
+func matchupOf(g *Game) matchup {
+	return matchup{team1: g.team1, team2: g.team2}
 }
 
 // NewOracleModel makes a model.
 func NewOracleModel(results []bpefs.Game) *OracleModel {
-	out := make(map[Game]float64)
+	out := make(map[matchup]float64)
 	for _, g := range results {
 		// Games that are not completed do not get added to the model.
 		if (g.HomePoints == nil) || (g.AwayPoints == nil) {
 			continue
 		}
 
-		ht := g.HomeTeam.ID
-		at := g.AwayTeam.ID
-		hl := Home
-		al := Away
-		if g.NeutralSite {
-			hl = Neutral
-			al = Neutral
-		}
-		hg := Game{
-			team1:    Team(ht),
-			team2:    Team(at),
-			location: hl,
-		}
-		ag := Game{
-			team1:    Team(at),
-			team2:    Team(ht),
-			location: al,
-		}
+		ht := Team(g.HomeTeam.ID)
+		at := Team(g.AwayTeam.ID)
+		hg := matchup{team1: ht, team2: at}
+		ag := matchup{team1: at, team2: ht}
 
 		out[hg] = float64(*g.HomePoints - *g.AwayPoints)
 		out[ag] = float64(*g.AwayPoints - *g.HomePoints)
@@ -159,7 +205,7 @@ func (m OracleModel) MostLikelyOutcome(g *Game) (team Team, prob float64, spread
 
 	var ok bool
 	team = g.Team(0)
-	spread, ok = m.results[*g]
+	spread, ok = m.results[matchupOf(g)]
 	if !ok {
 		return
 	}
@@ -184,7 +230,7 @@ func (m OracleModel) MostLikelyNoisySpreadOutcome(g *Game, noisySpread float64)
 
 	var ok bool
 	team = g.Team(0)
-	spread, ok = m.results[*g]
+	spread, ok = m.results[matchupOf(g)]
 	if !ok {
 		return
 	}
@@ -206,7 +252,7 @@ func (m OracleModel) Predict(g *Game) (prob float64, spread float64) {
 	}
 
 	var ok bool
-	spread, ok = m.results[*g]
+	spread, ok = m.results[matchupOf(g)]
 	if !ok {
 		return
 	}
@@ -226,7 +272,7 @@ func (m OracleModel) PredictNoisySpread(g *Game, noisySpread float64) (prob floa
 	}
 
 	var ok bool
-	spread, ok = m.results[*g]
+	spread, ok = m.results[matchupOf(g)]
 	if !ok {
 		return
 	}
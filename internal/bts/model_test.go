@@ -0,0 +1,58 @@
+package bts
+
+import (
+	"math"
+	"testing"
+
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func TestPredictInterval(t *testing.T) {
+	ratings := map[string]bpefs.ModelTeamPoints{
+		"a": {Points: 17, HomeAdvantage: 3},
+		"b": {Points: 10, HomeAdvantage: 3},
+	}
+	tests := []struct {
+		name       string
+		perf       bpefs.ModelPerformance
+		location   RelativeLocation
+		confidence float64
+		lo, hi     float64
+	}{
+		{"neutral 95%", bpefs.ModelPerformance{StdDev: 10}, Neutral, .95, 7 - 19.600, 7 + 19.600},
+		{"home 68%", bpefs.ModelPerformance{StdDev: 10}, Home, .6827, 10 - 10, 10 + 10},
+		{"biased", bpefs.ModelPerformance{Bias: 2, StdDev: 10}, Neutral, .95, 5 - 19.600, 5 + 19.600},
+		{"zero confidence", bpefs.ModelPerformance{StdDev: 10}, Neutral, 0, 7, 7},
+	}
+	for _, test := range tests {
+		m := NewGaussianSpreadModel(ratings, test.perf)
+		g := NewGame("a", "b", test.location)
+		lo, hi := m.PredictInterval(g, test.confidence)
+		if math.Abs(lo-test.lo) > 1e-2 || math.Abs(hi-test.hi) > 1e-2 {
+			t.Errorf("%s: expected [%f, %f], got [%f, %f]", test.name, test.lo, test.hi, lo, hi)
+		}
+		if lo > hi {
+			t.Errorf("%s: lower bound %f above upper bound %f", test.name, lo, hi)
+		}
+
+		// Swapping the teams mirrors the interval, less twice the bias.
+		g.SwapTeams()
+		slo, shi := m.PredictInterval(g, test.confidence)
+		if math.Abs(slo+hi+2*test.perf.Bias) > 1e-9 || math.Abs(shi+lo+2*test.perf.Bias) > 1e-9 {
+			t.Errorf("%s swapped: expected [%f, %f], got [%f, %f]", test.name, -hi-2*test.perf.Bias, -lo-2*test.perf.Bias, slo, shi)
+		}
+	}
+
+	m := NewGaussianSpreadModel(ratings, bpefs.ModelPerformance{StdDev: 10})
+	narrow, wide := NewGame("a", "b", Neutral), NewGame("a", "b", Neutral)
+	nlo, nhi := m.PredictInterval(narrow, .5)
+	wlo, whi := m.PredictInterval(wide, .99)
+	if !(wlo < nlo && nhi < whi) {
+		t.Errorf("expected the 99%% interval [%f, %f] to contain the 50%% interval [%f, %f]", wlo, whi, nlo, nhi)
+	}
+	for _, g := range []*Game{NewGame(BYE, "a", Neutral), NewGame("a", NONE, Neutral)} {
+		if lo, hi := m.PredictInterval(g, .95); lo != 0 || hi != 0 {
+			t.Errorf("%v: expected empty interval, got [%f, %f]", g, lo, hi)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
 )
 
 type Context struct {
@@ -19,6 +20,9 @@ type Context struct {
 	Seed         int64
 	Iterations   int
 	Championship bool
+
+	// Resolver resolves team names that are missing or duplicated. If nil, the user is asked on the terminal.
+	Resolver editteams.NameResolver
 }
 
 func NewContext(ctx context.Context) *Context {
@@ -5,7 +5,6 @@ import (
 	"log"
 	"math/rand"
 	"sort"
-	"strings"
 	"sync"
 
 	"cloud.google.com/go/firestore"
@@ -22,7 +21,7 @@ func Posteriors(ctx *Context) error {
 	fs := ctx.FirestoreClient
 
 	// Get season
-	_, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
+	season, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
 	if err != nil {
 		return fmt.Errorf("Posteriors: unable to get season: %w", err)
 	}
@@ -87,79 +86,28 @@ func Posteriors(ctx *Context) error {
 		return fmt.Errorf("Posteriors: unable to retrieve team references: %w", err)
 	}
 
-	// Filter teams
-	// Get teams by short name first
-	var teamsByShortName bpefs.TeamRefsByName
-	var err2 *bpefs.DuplicateTeamNameError
-	for {
-		teamsByShortName, err2 = bpefs.NewTeamRefsByShortName(teams, teamRefs)
-		if err2 == nil {
-			break
-		}
-
-		updateNames, err := editteams.SurveyReplaceName(teams, teamRefs, err2.Name, err2.Teams, err2.Refs, bpefs.ShortName)
-		if err != nil {
-			panic(err)
-		}
-
-		for ref, t := range updateNames {
-			fmt.Printf("Updating %s to change %s (names now [%s])\n", ref.ID, err2.Name, strings.Join(t.ShortNames, ", "))
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	// Filter teams by short name
+	editContext := &editteams.Context{
+		Context:         ctx.Context,
+		Force:           ctx.Force,
+		DryRun:          ctx.DryRun,
+		FirestoreClient: ctx.FirestoreClient,
+		Season:          season.Year,
+	}
+	teamsByName, err := editteams.NewTeamResolver(editContext, ctx.Resolver, teams, teamRefs)
+	if err != nil {
+		return fmt.Errorf("Posteriors: unable to make team resolver: %w", err)
 	}
 
-	// Filter here
 	posteriorTeams := []*firestore.DocumentRef{}
 	teamNamesByID := make(map[string]string)
-TeamNameLoop:
 	for _, teamName := range ctx.Teams {
-	TeamLookupLoop:
-		for {
-			if ref, ok := teamsByShortName[teamName]; ok {
-				posteriorTeams = append(posteriorTeams, ref)
-				teamNamesByID[ref.ID] = teamName
-				continue TeamNameLoop
-			} else {
-				team, ref, err := editteams.SurveyAddName(teams, teamRefs, teamName, bpefs.ShortName)
-				if err != nil {
-					panic(err)
-				}
-
-				fmt.Printf("Updating %s to add short name %s (names now [%s])\n", ref.ID, teamName, strings.Join(team.ShortNames, ", "))
-
-				editContext := &editteams.Context{
-					Context:         ctx.Context,
-					Force:           ctx.Force,
-					DryRun:          ctx.DryRun,
-					FirestoreClient: ctx.FirestoreClient,
-					ID:              ref.ID,
-					Team:            team,
-					Season:          ctx.Season,
-					Append:          false,
-				}
-				err = editteams.EditTeam(editContext)
-				if err != nil {
-					panic(err)
-				}
-
-				teamsByShortName[teamName] = ref
-				continue TeamLookupLoop
-			}
+		ref, err := editteams.ResolveName(editContext, ctx.Resolver, teamsByName, teamName, bpefs.ShortName)
+		if err != nil {
+			return fmt.Errorf("Posteriors: %w", err)
 		}
+		posteriorTeams = append(posteriorTeams, ref)
+		teamNamesByID[ref.ID] = teamName
 	}
 
 	// Get schedule from most recent season
@@ -195,6 +143,28 @@ TeamNameLoop:
 	return nil
 }
 
+// WinHistograms simulates the given schedule `seasons` times and returns, for each team in the schedule, the number of simulated seasons in which the team won a given number of games.
+// The histogram for each team is indexed by number of wins.
+func WinHistograms(schedule *bts.Schedule, model bts.PredictionModel, seasons int, playChampionship bool) map[bts.Team][]int {
+	tl := schedule.TeamList()
+	// One extra slot for zero wins, and one more for the championship game.
+	maxWins := schedule.NumWeeks() + 1
+	if playChampionship {
+		maxWins++
+	}
+	hists := make(map[bts.Team][]int)
+	for _, t := range tl {
+		hists[t] = make([]int, maxWins)
+	}
+
+	for result := range simulate(schedule, model, seasons, playChampionship) {
+		for _, t := range tl {
+			hists[t][result[t]]++
+		}
+	}
+	return hists
+}
+
 func simulate(schedule *bts.Schedule, model bts.PredictionModel, seasons int, playChampionship bool) <-chan map[bts.Team]int {
 	out := make(chan map[bts.Team]int, 100)
 
@@ -13,7 +13,9 @@ type Context struct {
 	// Force  bool
 	// DryRun bool
 
-	Season     int
+	Season int
+	// Week is the week of team ratings used to simulate the remaining games. Games that have already been played are not simulated.
+	Week       int
 	Seed       int64
 	Workers    int
 	Iterations int
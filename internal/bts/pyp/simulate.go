@@ -1,15 +1,20 @@
 package pyp
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
+	"os"
 
 	"sort"
 	"time"
 
 	"cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/reallyasi9/b1gpickem/internal/bts"
+	"gonum.org/v1/gonum/stat"
 
 	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
 )
@@ -40,12 +45,12 @@ func Simulate(ctx *Context) error {
 	}
 	log.Printf("season discovered: %s", seasonRef.ID)
 
-	// Get the most recent week
-	week, weekRef, err := bpefs.GetFirstWeek(ctx, seasonRef)
+	// Get the week of ratings to use
+	week, weekRef, err := bpefs.GetWeek(ctx, seasonRef, ctx.Week)
 	if err != nil {
 		return fmt.Errorf("Simulate: unable to get week: %v", err)
 	}
-	log.Printf("first week discovered: %s", weekRef.ID)
+	log.Printf("week discovered: %s", weekRef.ID)
 
 	// Get most recent Sagarin Ratings proper
 	// I can cheat because I know this already.
@@ -92,18 +97,17 @@ func Simulate(ctx *Context) error {
 	model := bts.NewGaussianSpreadModel(sagarinRatings, sagPerf)
 	log.Printf("Built model %v", model)
 
-	// Get schedule from most recent season
 	pypTeamRefs := make([]*firestore.DocumentRef, 0, len(season.PonyTeams))
 	for id := range season.PonyTeams {
 		pypTeamRefs = append(pypTeamRefs, seasonRef.Collection(bpefs.TEAMS_COLLECTION).Doc(id))
 	}
 
-	// FIXME: this takes forever. Why?
-	schedule, err := bts.MakeSchedule(ctx, fs, seasonRef, week.Number, pypTeamRefs)
+	// Split the season into games already played and games left to play
+	records, games, err := splitSeason(ctx, seasonRef, pypTeamRefs, time.Now())
 	if err != nil {
-		return fmt.Errorf("Simulate: unable to make schedule: %w", err)
+		return fmt.Errorf("Simulate: unable to split season into completed and remaining games: %w", err)
 	}
-	log.Printf("Schedule built:\n%v", schedule)
+	log.Printf("Found %d undecided games for ratings week %d", len(games), week.Number)
 
 	// Get names for human readability later
 	teamObjs, err := bpefs.GetAll[bpefs.Team](ctx, fs, pypTeamRefs)
@@ -115,26 +119,16 @@ func Simulate(ctx *Context) error {
 		teamIDLookup[teamRef.ID] = teamObjs[i].School
 	}
 
-	games := schedule.UniqueGames()
-	log.Printf("Found %d unique games", len(games))
-
 	spreads := predictSpreads(games, model)
 	log.Printf("Made predictions\n%v", spreads)
 
 	// Here we go.
 	log.Println("Starting MC")
 
-	var nGamesPerSeason int
-	for _, week := range schedule {
-		if len(week) > nGamesPerSeason {
-			nGamesPerSeason = len(week)
-		}
-	}
-	// output: histogram of wins per team for all simulations, runs from 0 to the number of games per season.
+	// output: histogram of remaining wins per team for all simulations, runs from 0 to the number of undecided games.
 	winHists := make(map[string][]int)
 	for team := range season.PonyTeams {
-		// Seasons always include bye weeks, but just in case some team plays no byes, we need an extra value in the slice to account for that.
-		winHists[team] = make([]int, nGamesPerSeason+1)
+		winHists[team] = make([]int, records[team].Undecided()+1)
 	}
 
 	// Loop through games and draw a random outcome for each game
@@ -158,7 +152,9 @@ func Simulate(ctx *Context) error {
 		}
 	}
 
-	log.Printf("Predicted histograms:\n%v", winHists)
+	log.Printf("Predicted histograms of remaining wins:\n%v", winHists)
+
+	printStatus(season.PonyTeams, records, winHists, teamIDLookup, ctx.Iterations)
 
 	// Calculate expected points and total upside risk, split by B1G and non-B1G
 	expectedPoints := make(map[string]float64)
@@ -167,8 +163,9 @@ func Simulate(ctx *Context) error {
 	b1gUpsideRisk := make(map[string]float64)
 	for team, pred := range season.PonyTeams {
 		hist := winHists[team]
-		for nwins, nseasons := range hist {
+		for remainingWins, nseasons := range hist {
 			p := float64(nseasons) / float64(ctx.Iterations)
+			nwins := records[team].Wins + remainingWins
 			if pred < 0 {
 				pointsGained := -pred - float64(nwins)
 				expectedPoints[team] += p * pointsGained
@@ -195,6 +192,7 @@ func Simulate(ctx *Context) error {
 	}
 
 	// Print results of simulation
+	fmt.Println()
 	fmt.Println("Highest Expected Points (B1G):")
 	sort.Sort(sort.Reverse(ByExpectedPoints(b1gRisks)))
 	for _, risk := range b1gRisks {
@@ -257,3 +255,186 @@ func predictSpreads(games []*bts.Game, model bts.PredictionModel) []float64 {
 	}
 	return out
 }
+
+// Record is a team's record in completed games and the number of games it has left to play.
+type Record struct {
+	Wins   int
+	Losses int
+	// Remaining is the number of games that have not yet kicked off.
+	Remaining int
+	// Unscored is the number of games that have kicked off but have no final score yet.
+	Unscored int
+}
+
+// Undecided returns the number of games whose outcome is not yet known, which are the games that are simulated.
+func (r Record) Undecided() int {
+	return r.Remaining + r.Unscored
+}
+
+// tally adds a game to the records of the teams in it that are being tracked.
+// Games without final scores are counted as remaining if they have not kicked off by now, or as unscored if they have, and tally returns true for them.
+func tally(records map[string]Record, game bpefs.Game, now time.Time) bool {
+	homeRecord, homeOK := records[game.HomeTeam.ID]
+	awayRecord, awayOK := records[game.AwayTeam.ID]
+	if !homeOK && !awayOK {
+		return false
+	}
+
+	undecided := game.HomePoints == nil || game.AwayPoints == nil
+	switch {
+	case undecided && (game.StartTimeTBD || game.StartTime.After(now)):
+		homeRecord.Remaining++
+		awayRecord.Remaining++
+	case undecided:
+		homeRecord.Unscored++
+		awayRecord.Unscored++
+	case *game.HomePoints > *game.AwayPoints:
+		homeRecord.Wins++
+		awayRecord.Losses++
+	default:
+		// Ties are not possible in college football, so a non-win is a loss.
+		homeRecord.Losses++
+		awayRecord.Wins++
+	}
+
+	if homeOK {
+		records[game.HomeTeam.ID] = homeRecord
+	}
+	if awayOK {
+		records[game.AwayTeam.ID] = awayRecord
+	}
+	return undecided
+}
+
+// splitSeason reads every game of the season involving the given teams.
+// Games with final scores are tallied in the returned records, keyed by team ID, and the games without final scores are returned for simulation.
+func splitSeason(ctx context.Context, season *firestore.DocumentRef, teams []*firestore.DocumentRef, now time.Time) (map[string]Record, []*bts.Game, error) {
+	_, weekRefs, err := bpefs.GetWeeks(ctx, season)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make(map[string]Record)
+	for _, team := range teams {
+		records[team.ID] = Record{}
+	}
+
+	locator := bts.NewLocator()
+	var undecided []*bts.Game
+	for _, weekRef := range weekRefs {
+		games, _, err := bpefs.GetGames(ctx, weekRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, game := range games {
+			if !tally(records, game, now) {
+				continue
+			}
+			g, err := bts.MakeGame(ctx, locator, game)
+			if err != nil {
+				return nil, nil, err
+			}
+			undecided = append(undecided, g)
+		}
+	}
+
+	return records, undecided, nil
+}
+
+// RecordsBefore tallies the records of the given teams, keyed by team ID, in the games of the season's weeks before the given week number.
+// Games in those weeks without final scores are counted as unscored or remaining, but are not simulated.
+func RecordsBefore(ctx context.Context, season *firestore.DocumentRef, teams []*firestore.DocumentRef, week int, now time.Time) (map[string]Record, error) {
+	weeks, weekRefs, err := bpefs.GetWeeks(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make(map[string]Record)
+	for _, team := range teams {
+		records[team.ID] = Record{}
+	}
+
+	for i, weekRef := range weekRefs {
+		if weeks[i].Number >= week {
+			continue
+		}
+		games, _, err := bpefs.GetGames(ctx, weekRef)
+		if err != nil {
+			return nil, err
+		}
+		for _, game := range games {
+			tally(records, game, now)
+		}
+	}
+
+	return records, nil
+}
+
+// printStatus prints each pony's current record and points along with the distribution of remaining wins and expected final points.
+// Points for B1G ponies are wins over the prediction, so they can only go up as the season progresses.
+// Points for top 25 ponies (negative predictions) are losses against the prediction, so they can only go down.
+func printStatus(ponies map[string]float64, records map[string]Record, winHists map[string][]int, names map[string]string, iterations int) {
+	teams := make([]string, 0, len(ponies))
+	for team := range ponies {
+		teams = append(teams, team)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		bi, bj := ponies[teams[i]] >= 0, ponies[teams[j]] >= 0
+		if bi != bj {
+			return bi
+		}
+		return names[teams[i]] < names[teams[j]]
+	})
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Team", "Pred. Wins", "W", "L", "Left", "Unscored", "Current Points", "Exp. Remaining Wins", "Remaining Wins [min q25 med q75 max]", "Exp. Final Points"})
+	for _, team := range teams {
+		pred := ponies[team]
+		rec := records[team]
+		hist := winHists[team]
+
+		var current float64
+		if pred < 0 {
+			current = -pred - float64(rec.Wins)
+		} else {
+			current = float64(rec.Wins) - pred
+		}
+
+		remaining := make([]float64, 0, iterations)
+		for nwins, nseasons := range hist {
+			for i := 0; i < nseasons; i++ {
+				remaining = append(remaining, float64(nwins))
+			}
+		}
+		var mean, min, q25, median, q75, max float64
+		if len(remaining) > 0 {
+			mean = stat.Mean(remaining, nil)
+			min = remaining[0]
+			q25 = stat.Quantile(0.25, stat.Empirical, remaining, nil)
+			median = stat.Quantile(0.5, stat.Empirical, remaining, nil)
+			q75 = stat.Quantile(0.75, stat.Empirical, remaining, nil)
+			max = remaining[len(remaining)-1]
+		}
+		final := current + mean
+		if pred < 0 {
+			final = current - mean
+		}
+
+		t.AppendRow(table.Row{
+			names[team],
+			fmt.Sprintf("%0.1f", math.Abs(pred)),
+			rec.Wins,
+			rec.Losses,
+			rec.Remaining,
+			rec.Unscored,
+			fmt.Sprintf("%0.1f", current),
+			fmt.Sprintf("%0.2f", mean),
+			fmt.Sprintf("[%0.0f %0.0f %0.0f %0.0f %0.0f]", min, q25, median, q75, max),
+			fmt.Sprintf("%0.2f", final),
+		})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -0,0 +1,55 @@
+package pyp
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func TestTally(t *testing.T) {
+	now := time.Date(2023, 10, 7, 12, 0, 0, 0, time.UTC)
+	team := func(id string) *firestore.DocumentRef { return &firestore.DocumentRef{ID: id} }
+	points := func(p int) *int { return &p }
+	game := func(home, away string, start time.Time, homePoints, awayPoints *int) bpefs.Game {
+		return bpefs.Game{HomeTeam: team(home), AwayTeam: team(away), StartTime: start, HomePoints: homePoints, AwayPoints: awayPoints}
+	}
+
+	records := map[string]Record{"iowa": {}, "purdue": {}}
+	games := []struct {
+		game      bpefs.Game
+		undecided bool
+	}{
+		{game("iowa", "purdue", now.Add(-14*24*time.Hour), points(20), points(10)), false},
+		{game("ohio", "iowa", now.Add(-7*24*time.Hour), points(30), points(3)), false},
+		// kicked off, but not scored yet
+		{game("purdue", "ohio", now.Add(-7*24*time.Hour), nil, nil), true},
+		{game("iowa", "ohio", now.Add(-time.Hour), nil, nil), true},
+		{game("purdue", "iowa", now.Add(7*24*time.Hour), nil, nil), true},
+		{bpefs.Game{HomeTeam: team("iowa"), AwayTeam: team("ohio"), StartTime: now.Add(-time.Hour), StartTimeTBD: true}, true},
+		// not tracked
+		{game("ohio", "michigan", now.Add(-7*24*time.Hour), nil, nil), false},
+	}
+	for i, g := range games {
+		if got := tally(records, g.game, now); got != g.undecided {
+			t.Errorf("game %d: expected undecided %t, got %t", i, g.undecided, got)
+		}
+	}
+
+	want := map[string]Record{
+		"iowa":   {Wins: 1, Losses: 1, Remaining: 2, Unscored: 1},
+		"purdue": {Wins: 0, Losses: 1, Remaining: 1, Unscored: 1},
+	}
+	for id, w := range want {
+		if records[id] != w {
+			t.Errorf("%s: expected %+v, got %+v", id, w, records[id])
+		}
+	}
+	if len(records) != 2 {
+		t.Errorf("expected only tracked teams to have records, got %v", records)
+	}
+	if n := records["iowa"].Undecided(); n != 3 {
+		t.Errorf("expected iowa to have 3 undecided games, got %d", n)
+	}
+}
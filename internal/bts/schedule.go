@@ -16,6 +16,7 @@ type Schedule map[Team][]*Game
 // MakeSchedule builds a schedule from the games in Firestore.
 // The schedule will only include games from the given `week` onward (inclusive), and only for the given `teams`.
 // If a `team` does not have a game in a given week, a BYE will be inserted.
+// Neutral site games are graded by how far each team travels from its home venue.
 func MakeSchedule(ctx context.Context, season *firestore.DocumentRef, week int, teams []*firestore.DocumentRef) (schedule Schedule, err error) {
 	weeks, err := season.Collection(bpefs.WEEKS_COLLECTION).Where("number", ">=", week).OrderBy("number", firestore.Asc).Documents(ctx).GetAll()
 	if err != nil {
@@ -35,6 +36,7 @@ func MakeSchedule(ctx context.Context, season *firestore.DocumentRef, week int,
 		teamLookup[team.ID] = t
 	}
 
+	locator := NewLocator()
 	for iwk, weekSnap := range weeks {
 		// Search through games in each week for a matching team.
 		games, _, e := bpefs.GetGames(ctx, weekSnap.Ref)
@@ -44,24 +46,25 @@ func MakeSchedule(ctx context.Context, season *firestore.DocumentRef, week int,
 		}
 
 		for _, game := range games {
-			var g *Game
-			if t, ok := teamLookup[game.HomeTeam.ID]; ok {
-				var loc RelativeLocation
-				if !game.NeutralSite {
-					loc = Home
-				}
-				g = NewGame(t, Team(game.AwayTeam.ID), loc)
-				schedule[t][iwk] = g
+			ht, homeOK := teamLookup[game.HomeTeam.ID]
+			at, awayOK := teamLookup[game.AwayTeam.ID]
+			if !homeOK && !awayOK {
+				continue
+			}
+			g, e := MakeGame(ctx, locator, game)
+			if e != nil {
+				err = e
+				return
+			}
+			// Both teams share the same game if both are scheduled. Otherwise, the scheduled team is team1.
+			if homeOK {
+				schedule[ht][iwk] = g
 			}
-			if t, ok := teamLookup[game.AwayTeam.ID]; ok {
-				if g == nil {
-					var loc RelativeLocation
-					if !game.NeutralSite {
-						loc = Away
-					}
-					g = NewGame(t, Team(game.HomeTeam.ID), loc)
+			if awayOK {
+				if !homeOK {
+					g.SwapTeams()
 				}
-				schedule[t][iwk] = g
+				schedule[at][iwk] = g
 			}
 		}
 	}
@@ -69,6 +72,24 @@ func MakeSchedule(ctx context.Context, season *firestore.DocumentRef, week int,
 	return
 }
 
+// MakeGame converts a game from Firestore into a Game with the home team as team1.
+// Neutral site games are graded by the travel of each team to the venue. If travel cannot be calculated, the site is treated as truly neutral.
+func MakeGame(ctx context.Context, locator *Locator, game bpefs.Game) (*Game, error) {
+	home := Team(game.HomeTeam.ID)
+	away := Team(game.AwayTeam.ID)
+	if !game.NeutralSite {
+		return NewGame(home, away, Home), nil
+	}
+	travel, ok, err := locator.Travel(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return NewGame(home, away, Neutral), nil
+	}
+	return NewNeutralSiteGame(home, away, travel), nil
+}
+
 type weekTeam struct {
 	week int
 	team Team
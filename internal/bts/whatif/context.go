@@ -4,6 +4,7 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
 )
 
 type Context struct {
@@ -18,6 +19,16 @@ type Context struct {
 	Team1    string
 	Team2    string
 	Location string
+
+	// File is a CSV file of hypothetical matchups to evaluate instead of Team1 and Team2.
+	File string
+	// Model is the short name of the team points model used to make predictions.
+	Model string
+	// Confidence is the confidence level of the reported spread interval.
+	Confidence float64
+
+	// Resolver resolves team names that are missing or duplicated. If nil, the user is asked on the terminal.
+	Resolver editteams.NameResolver
 }
 
 func NewContext(ctx context.Context) *Context {
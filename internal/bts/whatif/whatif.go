@@ -1,8 +1,11 @@
 package whatif
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
 
 	"cloud.google.com/go/firestore"
@@ -18,7 +21,7 @@ func WhatIf(ctx *Context) error {
 	fs := ctx.FirestoreClient
 
 	// Get season
-	_, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
+	season, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
 	if err != nil {
 		return fmt.Errorf("WhatIf: unable to get season: %w", err)
 	}
@@ -30,47 +33,15 @@ func WhatIf(ctx *Context) error {
 		return fmt.Errorf("WhatIf: unable to get week: %v", err)
 	}
 
-	// Get most recent Sagarin Ratings proper
-	// I can cheat because I know this already.
-	sagPointsRef := weekRef.Collection("team-points").Doc("sagarin")
-	sagSnaps, err := sagPointsRef.Collection("linesag").Documents(ctx).GetAll()
-	if err != nil {
-		return fmt.Errorf("WhatIf: unable to get sagarin ratings: %v", err)
-	}
-	sagarinRatings := make(map[string]bpefs.ModelTeamPoints)
-	for _, s := range sagSnaps {
-		var sag bpefs.ModelTeamPoints
-		err = s.DataTo(&sag)
-		if err != nil {
-			return fmt.Errorf("WhatIf: unable to get sagarin rating: %v", err)
-		}
-		// Sagarin has one nil team representing a non-recorded team. Don't keep that one.
-		if sag.Team == nil {
-			continue
-		}
-		sagarinRatings[sag.Team.ID] = sag
+	// Build the probability model
+	modelName := ctx.Model
+	if modelName == "" {
+		modelName = "linesag"
 	}
-
-	// Get most recent performances for sagarin
-	performances, performanceRefs, err := bpefs.GetMostRecentModelPerformances(ctx, fs, weekRef)
+	model, err := bts.GetGaussianSpreadModel(ctx, fs, weekRef, modelName)
 	if err != nil {
-		return fmt.Errorf("WhatIf: unable to get model performances: %v", err)
-	}
-	var sagPerf bpefs.ModelPerformance
-	var sagPerfRef *firestore.DocumentRef
-	for i, perf := range performances {
-		if perf.Model.ID == "linesag" {
-			sagPerf = perf
-			sagPerfRef = performanceRefs[i]
-			break
-		}
+		return fmt.Errorf("WhatIf: unable to build model: %w", err)
 	}
-	if sagPerfRef == nil {
-		return fmt.Errorf("WhatIf: unable to find most recent Sagarin performance for the week")
-	}
-
-	// Build the probability model
-	model := bts.NewGaussianSpreadModel(sagarinRatings, sagPerf)
 
 	// Get teams
 	teams, teamRefs, err := bpefs.GetTeams(ctx, seasonRef)
@@ -78,97 +49,41 @@ func WhatIf(ctx *Context) error {
 		return fmt.Errorf("WhatIf: unable to retrieve team references: %w", err)
 	}
 
-	// Filter teams
-	// Get teams by short name first
-	var teamsByShortName bpefs.TeamRefsByName
-	var err2 *bpefs.DuplicateTeamNameError
-	for {
-		teamsByShortName, err2 = bpefs.NewTeamRefsByShortName(teams, teamRefs)
-		if err2 == nil {
-			break
-		}
-
-		updateNames, err := editteams.SurveyReplaceName(teams, teamRefs, err2.Name, err2.Teams, err2.Refs, bpefs.ShortName)
-		if err != nil {
-			panic(err)
-		}
+	// Get teams by short name
+	editContext := &editteams.Context{
+		Context:         ctx.Context,
+		Force:           ctx.Force,
+		DryRun:          ctx.DryRun,
+		FirestoreClient: ctx.FirestoreClient,
+		Season:          season.Year,
+	}
+	teamsByName, err := editteams.NewTeamResolver(editContext, ctx.Resolver, teams, teamRefs)
+	if err != nil {
+		return fmt.Errorf("WhatIf: unable to make team resolver: %w", err)
+	}
 
-		for ref, t := range updateNames {
-			fmt.Printf("Updating %s to change %s (names now [%s])\n", ref.ID, err2.Name, strings.Join(t.ShortNames, ", "))
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	lookup := func(teamName string) (*firestore.DocumentRef, error) {
+		return editteams.ResolveName(editContext, ctx.Resolver, teamsByName, teamName, bpefs.ShortName)
 	}
 
-	// Filter here
-	whatIfTeams := []*firestore.DocumentRef{}
-	contextTeams := []string{ctx.Team1, ctx.Team2}
-TeamNameLoop:
-	for _, teamName := range contextTeams {
-	TeamLookupLoop:
-		for {
-			if ref, ok := teamsByShortName[teamName]; ok {
-				whatIfTeams = append(whatIfTeams, ref)
-				continue TeamNameLoop
-			} else {
-				team, ref, err := editteams.SurveyAddName(teams, teamRefs, teamName, bpefs.ShortName)
-				if err != nil {
-					panic(err)
-				}
-
-				fmt.Printf("Updating %s to add short name %s (names now [%s])\n", ref.ID, teamName, strings.Join(team.ShortNames, ", "))
-
-				editContext := &editteams.Context{
-					Context:         ctx.Context,
-					Force:           ctx.Force,
-					DryRun:          ctx.DryRun,
-					FirestoreClient: ctx.FirestoreClient,
-					ID:              ref.ID,
-					Team:            team,
-					Season:          ctx.Season,
-					Append:          false,
-				}
-				err = editteams.EditTeam(editContext)
-				if err != nil {
-					panic(err)
-				}
-
-				teamsByShortName[teamName] = ref
-				continue TeamLookupLoop
-			}
-		}
+	if ctx.File != "" {
+		return whatIfBatch(ctx, seasonRef, model, lookup)
 	}
 
 	// Build game
-	var location bts.RelativeLocation
-	switch strings.ToLower(ctx.Location) {
-	case "home":
-		location = bts.Home
-	case "away":
-		location = bts.Away
-	case "near":
-		location = bts.Near
-	case "far":
-		location = bts.Far
-	case "neutral":
-		fallthrough
-	default:
-		location = bts.Neutral
-	}
-	game := bts.NewGame(bts.Team(whatIfTeams[0].ID), bts.Team(whatIfTeams[1].ID), location)
+	location, err := bts.ParseRelativeLocation(ctx.Location)
+	if err != nil {
+		return fmt.Errorf("WhatIf: %w", err)
+	}
+	team1, err := lookup(ctx.Team1)
+	if err != nil {
+		return fmt.Errorf("WhatIf: %w", err)
+	}
+	team2, err := lookup(ctx.Team2)
+	if err != nil {
+		return fmt.Errorf("WhatIf: %w", err)
+	}
+	game := bts.NewGame(bts.Team(team1.ID), bts.Team(team2.ID), location)
 
 	// Simulate
 	p, s := model.Predict(game)
@@ -178,6 +93,7 @@ TeamNameLoop:
 	} else {
 		winner = ctx.Team2
 		p = 1 - p
+		s = -s
 	}
 	fmt.Printf("In a match up between %s (%s) and %s in week %d of season %d,\n%s wins by %0.2f points (%0.3f probability of win)\n", ctx.Team1, ctx.Location, ctx.Team2, ctx.Week, ctx.Season, winner, s, p)
 
@@ -185,3 +101,153 @@ TeamNameLoop:
 
 	return nil
 }
+
+// Matchup is a hypothetical game read from a matchups file.
+type Matchup struct {
+	Team1    string
+	Team2    string
+	Location bts.RelativeLocation
+	// Week is the week whose model ratings are used to evaluate the matchup. Negative values use the default week.
+	Week int
+}
+
+// ReadMatchups reads hypothetical matchups from a CSV file with columns team1, team2, location, and (optionally) week.
+// Team names are short names. Locations are relative to team1, and an empty location is treated as a neutral site.
+// Blank lines, lines starting with '#', and a header line starting with "team1" are ignored.
+func ReadMatchups(fileName string) ([]Matchup, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	matchups := make([]Matchup, 0, len(records))
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "team1") {
+			continue
+		}
+		if len(record) < 2 || len(record) > 4 {
+			return nil, fmt.Errorf("line %d: expected 2 to 4 fields, got %d", i+1, len(record))
+		}
+		m := Matchup{
+			Team1: strings.TrimSpace(record[0]),
+			Team2: strings.TrimSpace(record[1]),
+			Week:  -1,
+		}
+		if len(record) > 2 {
+			if m.Location, err = bts.ParseRelativeLocation(record[2]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+		if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+			if _, err := fmt.Sscanf(strings.TrimSpace(record[3]), "%d", &m.Week); err != nil {
+				return nil, fmt.Errorf("line %d: unable to parse week '%s': %w", i+1, record[3], err)
+			}
+		}
+		matchups = append(matchups, m)
+	}
+	return matchups, nil
+}
+
+func whatIfBatch(ctx *Context, seasonRef *firestore.DocumentRef, defaultModel *bts.GaussianSpreadModel, lookup func(string) (*firestore.DocumentRef, error)) error {
+	matchups, err := ReadMatchups(ctx.File)
+	if err != nil {
+		return fmt.Errorf("WhatIf: unable to read matchups from '%s': %w", ctx.File, err)
+	}
+	log.Printf("Read %d matchups from %s", len(matchups), ctx.File)
+
+	modelName := ctx.Model
+	if modelName == "" {
+		modelName = "linesag"
+	}
+	confidence := ctx.Confidence
+	if confidence <= 0 || confidence >= 1 {
+		confidence = .95
+	}
+
+	// Different weeks have different ratings, so keep one model per week.
+	models := map[int]*bts.GaussianSpreadModel{-1: defaultModel}
+	modelFor := func(week int) (*bts.GaussianSpreadModel, error) {
+		if model, ok := models[week]; ok {
+			return model, nil
+		}
+		_, weekRef, err := bpefs.GetWeek(ctx, seasonRef, week)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get week %d: %w", week, err)
+		}
+		model, err := bts.GetGaussianSpreadModel(ctx, ctx.FirestoreClient, weekRef, modelName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build model for week %d: %w", week, err)
+		}
+		models[week] = model
+		return model, nil
+	}
+	lookupTeam := func(name string) (bts.Team, error) {
+		ref, err := lookup(name)
+		if err != nil {
+			return bts.NONE, err
+		}
+		return bts.Team(ref.ID), nil
+	}
+
+	if err := writeMatchups(os.Stdout, matchups, ctx.Week, confidence, modelFor, lookupTeam); err != nil {
+		return fmt.Errorf("WhatIf: %w", err)
+	}
+
+	log.Printf("Done")
+
+	return nil
+}
+
+// writeMatchups evaluates matchups and writes the win probability, spread, and central spread interval at the given confidence of each as CSV.
+// Matchups without a week of their own are reported as defaultWeek.
+func writeMatchups(out io.Writer, matchups []Matchup, defaultWeek int, confidence float64, modelFor func(week int) (*bts.GaussianSpreadModel, error), lookup func(name string) (bts.Team, error)) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"team1", "team2", "location", "week", "probability", "spread", fmt.Sprintf("lower%0.0f", confidence*100), fmt.Sprintf("upper%0.0f", confidence*100)})
+	for _, m := range matchups {
+		model, err := modelFor(m.Week)
+		if err != nil {
+			return err
+		}
+		team1, err := lookup(m.Team1)
+		if err != nil {
+			return err
+		}
+		team2, err := lookup(m.Team2)
+		if err != nil {
+			return err
+		}
+		game := bts.NewGame(team1, team2, m.Location)
+		p, s := model.Predict(game)
+		lo, hi := model.PredictInterval(game, confidence)
+
+		week := defaultWeek
+		if m.Week >= 0 {
+			week = m.Week
+		}
+		w.Write([]string{
+			m.Team1,
+			m.Team2,
+			m.Location.String(),
+			fmt.Sprintf("%d", week),
+			fmt.Sprintf("%0.3f", p),
+			fmt.Sprintf("%0.2f", s),
+			fmt.Sprintf("%0.2f", lo),
+			fmt.Sprintf("%0.2f", hi),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("unable to write results: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package whatif
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reallyasi9/b1gpickem/internal/bts"
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func TestReadMatchups(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Matchup
+		wantErr bool
+	}{
+		{
+			name:  "header and comments",
+			input: "team1,team2,location,week\n# a comment\n\nIowa, Purdue, home, 3\n",
+			want:  []Matchup{{Team1: "Iowa", Team2: "Purdue", Location: bts.Home, Week: 3}},
+		},
+		{
+			name:  "neutral site",
+			input: "Iowa,Purdue\nIowa,Purdue,\nIowa,Purdue,neutral,\n",
+			want: []Matchup{
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Neutral, Week: -1},
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Neutral, Week: -1},
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Neutral, Week: -1},
+			},
+		},
+		{
+			name:  "relative locations",
+			input: "Iowa,Purdue,near\nIowa,Purdue,FAR\nIowa,Purdue,away\n",
+			want: []Matchup{
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Near, Week: -1},
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Far, Week: -1},
+				{Team1: "Iowa", Team2: "Purdue", Location: bts.Away, Week: -1},
+			},
+		},
+		{name: "one field", input: "Iowa\n", wantErr: true},
+		{name: "too many fields", input: "Iowa,Purdue,home,3,extra\n", wantErr: true},
+		{name: "bad location", input: "Iowa,Purdue,moon\n", wantErr: true},
+		{name: "bad week", input: "Iowa,Purdue,home,three\n", wantErr: true},
+	}
+	for _, test := range tests {
+		file := filepath.Join(t.TempDir(), "matchups.csv")
+		if err := os.WriteFile(file, []byte(test.input), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadMatchups(file)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %v", test.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+
+	if _, err := ReadMatchups(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("expected a missing file to fail")
+	}
+}
+
+func TestWriteMatchups(t *testing.T) {
+	ratings := map[string]bpefs.ModelTeamPoints{
+		"iowa":   {Points: 20, HomeAdvantage: 4},
+		"purdue": {Points: 10, HomeAdvantage: 2},
+	}
+	models := map[int]*bts.GaussianSpreadModel{
+		-1: bts.NewGaussianSpreadModel(ratings, bpefs.ModelPerformance{StdDev: 10}),
+	}
+	modelFor := func(week int) (*bts.GaussianSpreadModel, error) {
+		if m, ok := models[week]; ok {
+			return m, nil
+		}
+		return nil, fmt.Errorf("no model for week %d", week)
+	}
+	teams := map[string]bts.Team{"Iowa": "iowa", "Purdue": "purdue"}
+	lookup := func(name string) (bts.Team, error) {
+		if team, ok := teams[name]; ok {
+			return team, nil
+		}
+		return bts.NONE, fmt.Errorf("unknown team '%s'", name)
+	}
+
+	matchups := []Matchup{
+		{Team1: "Iowa", Team2: "Purdue", Location: bts.Neutral, Week: -1},
+		{Team1: "Purdue", Team2: "Iowa", Location: bts.Home, Week: -1},
+	}
+	var buf bytes.Buffer
+	if err := writeMatchups(&buf, matchups, 5, .95, modelFor, lookup); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"team1,team2,location,week,probability,spread,lower95,upper95",
+		"Iowa,Purdue,neutral,5,0.841,10.00,-9.60,29.60",
+		"Purdue,Iowa,home,5,0.212,-8.00,-27.60,11.60",
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if err := writeMatchups(&buf, []Matchup{{Team1: "Iowa", Team2: "Purdue", Week: 7}}, 5, .95, modelFor, lookup); err == nil {
+		t.Error("expected a week without a model to fail")
+	}
+	if err := writeMatchups(&buf, []Matchup{{Team1: "Iowa", Team2: "Ohio", Week: -1}}, 5, .95, modelFor, lookup); err == nil {
+		t.Error("expected an unknown team to fail")
+	}
+}
@@ -0,0 +1,29 @@
+package wintotals
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+	FirestoreClient *fs.Client
+
+	Season int
+	// Week is the week whose ratings are used and from which the schedule is simulated. Wins in earlier weeks are added to the simulated wins. Negative values use the first week of the season.
+	Week int
+	// File is a CSV file of win total lines and prices.
+	File string
+	// Model is the short name of the team points model used to make predictions.
+	Model      string
+	Iterations int
+	// KellyFraction scales the full Kelly stake. Most bettors use a fraction of Kelly to reduce variance.
+	KellyFraction float64
+	// Bankroll converts Kelly fractions into stakes.
+	Bankroll float64
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
@@ -0,0 +1,41 @@
+package wintotals
+
+import "math"
+
+// DecimalOdds converts American odds (e.g., -110 or +150) to decimal odds, the total amount returned per unit staked on a win.
+func DecimalOdds(american float64) float64 {
+	if american < 0 {
+		return 1 - 100/american
+	}
+	return 1 + american/100
+}
+
+// ImpliedProbability is the break-even win probability of a bet at the given American odds, including the bookmaker's margin.
+func ImpliedProbability(american float64) float64 {
+	return 1 / DecimalOdds(american)
+}
+
+// NoVigProbabilities removes the bookmaker's margin from a two-way market, returning the fair probabilities of each side.
+func NoVigProbabilities(american1, american2 float64) (float64, float64) {
+	p1 := ImpliedProbability(american1)
+	p2 := ImpliedProbability(american2)
+	return p1 / (p1 + p2), p2 / (p1 + p2)
+}
+
+// ExpectedValue is the expected profit per unit staked on a bet at the given American odds
+// that wins with probability pWin and loses with probability pLose. Any remaining probability is a push, which returns the stake.
+func ExpectedValue(pWin, pLose, american float64) float64 {
+	return pWin*(DecimalOdds(american)-1) - pLose
+}
+
+// Kelly is the fraction of a bankroll that maximizes the expected log growth of the bankroll
+// when staked on a bet at the given American odds that wins with probability pWin and loses with probability pLose.
+// Any remaining probability is a push. Bets with no edge return zero.
+func Kelly(pWin, pLose, american float64) float64 {
+	b := DecimalOdds(american) - 1
+	if b <= 0 || pWin+pLose <= 0 {
+		return 0
+	}
+	f := (b*pWin - pLose) / (b * (pWin + pLose))
+	return math.Max(f, 0)
+}
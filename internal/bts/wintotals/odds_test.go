@@ -0,0 +1,83 @@
+package wintotals
+
+import (
+	"math"
+	"testing"
+)
+
+const tol = 1e-9
+
+func TestDecimalOdds(t *testing.T) {
+	tests := []struct {
+		american float64
+		want     float64
+	}{
+		{-110, 1 + 100./110.},
+		{-200, 1.5},
+		{100, 2},
+		{150, 2.5},
+	}
+	for _, test := range tests {
+		if got := DecimalOdds(test.american); math.Abs(got-test.want) > tol {
+			t.Errorf("DecimalOdds(%f): expected %f, got %f", test.american, test.want, got)
+		}
+	}
+}
+
+func TestNoVigProbabilities(t *testing.T) {
+	p1, p2 := NoVigProbabilities(-110, -110)
+	if math.Abs(p1-.5) > tol || math.Abs(p2-.5) > tol {
+		t.Errorf("NoVigProbabilities(-110, -110): expected 0.5, 0.5, got %f, %f", p1, p2)
+	}
+	p1, p2 = NoVigProbabilities(-200, 170)
+	if math.Abs(p1+p2-1) > tol || p1 <= p2 {
+		t.Errorf("NoVigProbabilities(-200, 170): expected favorite to be more likely and probabilities to sum to 1, got %f, %f", p1, p2)
+	}
+}
+
+func TestKelly(t *testing.T) {
+	tests := []struct {
+		pWin, pLose, american float64
+		want                  float64
+	}{
+		// Even money, 60% to win: 0.2 of bankroll.
+		{.6, .4, 100, .2},
+		// No edge.
+		{.5, .5, 100, 0},
+		// Negative edge never bets.
+		{.4, .6, 100, 0},
+		// Pushes are removed before sizing: 0.6 of non-push outcomes are wins.
+		{.48, .32, 100, .2},
+		// +200, 40% to win: (2*.4 - .6)/2 = 0.1.
+		{.4, .6, 200, .1},
+	}
+	for _, test := range tests {
+		if got := Kelly(test.pWin, test.pLose, test.american); math.Abs(got-test.want) > tol {
+			t.Errorf("Kelly(%f, %f, %f): expected %f, got %f", test.pWin, test.pLose, test.american, test.want, got)
+		}
+	}
+}
+
+func TestExpectedValue(t *testing.T) {
+	if got := ExpectedValue(.5, .5, -110); got >= 0 {
+		t.Errorf("ExpectedValue(.5, .5, -110): expected negative value, got %f", got)
+	}
+	if got := ExpectedValue(.6, .4, 100); math.Abs(got-.2) > tol {
+		t.Errorf("ExpectedValue(.6, .4, 100): expected 0.2, got %f", got)
+	}
+}
+
+func TestAddWins(t *testing.T) {
+	// 3 games left and 4 wins banked: a line of 6.5 needs 3 more wins.
+	hist := AddWins([]int{1, 1, 1, 1}, 4)
+	if len(hist) != 8 || hist[0] != 0 || hist[3] != 0 || hist[4] != 1 || hist[7] != 1 {
+		t.Errorf("AddWins: expected wins shifted by 4, got %v", hist)
+	}
+	e := Evaluate(Line{Team: "a", Total: 6.5, OverPrice: -110, UnderPrice: -110}, hist)
+	if math.Abs(e.POver-.25) > tol || math.Abs(e.MeanWins-5.5) > tol {
+		t.Errorf("Evaluate: expected P(over) 0.25 and 5.5 mean wins, got %f and %f", e.POver, e.MeanWins)
+	}
+	if got := AddWins([]int{1, 2}, 0); len(got) != 2 {
+		t.Errorf("AddWins: expected no shift, got %v", got)
+	}
+}
@@ -0,0 +1,301 @@
+package wintotals
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/bts"
+	"github.com/reallyasi9/b1gpickem/internal/bts/posteriors"
+	"github.com/reallyasi9/b1gpickem/internal/bts/pyp"
+
+	bpefs "github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Line is a market season win total line for a team.
+type Line struct {
+	// Team is the short name of the team.
+	Team string
+	// Total is the number of wins. Half-win totals cannot push.
+	Total float64
+	// OverPrice is the American odds of the over.
+	OverPrice float64
+	// UnderPrice is the American odds of the under.
+	UnderPrice float64
+}
+
+// Evaluation compares a Line to a simulated win distribution.
+type Evaluation struct {
+	Line
+
+	MeanWins float64
+	POver    float64
+	PUnder   float64
+	PPush    float64
+
+	// FairOver and FairUnder are the market probabilities of the over and under with the bookmaker's margin removed.
+	FairOver  float64
+	FairUnder float64
+
+	// OverEV and UnderEV are expected profits per unit staked.
+	OverEV  float64
+	UnderEV float64
+
+	// OverKelly and UnderKelly are full Kelly fractions of the bankroll.
+	OverKelly  float64
+	UnderKelly float64
+}
+
+// Evaluate compares a line to a histogram of simulated wins indexed by number of wins.
+func Evaluate(line Line, hist []int) Evaluation {
+	e := Evaluation{Line: line}
+	total := 0
+	for _, n := range hist {
+		total += n
+	}
+	if total == 0 {
+		return e
+	}
+	for wins, n := range hist {
+		p := float64(n) / float64(total)
+		e.MeanWins += float64(wins) * p
+		switch {
+		case float64(wins) > line.Total:
+			e.POver += p
+		case float64(wins) < line.Total:
+			e.PUnder += p
+		default:
+			e.PPush += p
+		}
+	}
+	e.FairOver, e.FairUnder = NoVigProbabilities(line.OverPrice, line.UnderPrice)
+	e.OverEV = ExpectedValue(e.POver, e.PUnder, line.OverPrice)
+	e.UnderEV = ExpectedValue(e.PUnder, e.POver, line.UnderPrice)
+	e.OverKelly = Kelly(e.POver, e.PUnder, line.OverPrice)
+	e.UnderKelly = Kelly(e.PUnder, e.POver, line.UnderPrice)
+	return e
+}
+
+// AddWins shifts a histogram of simulated wins indexed by number of wins by wins already banked.
+func AddWins(hist []int, wins int) []int {
+	if wins <= 0 {
+		return hist
+	}
+	shifted := make([]int, len(hist)+wins)
+	copy(shifted[wins:], hist)
+	return shifted
+}
+
+// Recommendation returns the side of the line with positive expected value ("over", "under", or "pass") and its full Kelly fraction.
+func (e Evaluation) Recommendation() (string, float64) {
+	if e.OverEV > 0 && e.OverEV >= e.UnderEV {
+		return "over", e.OverKelly
+	}
+	if e.UnderEV > 0 {
+		return "under", e.UnderKelly
+	}
+	return "pass", 0
+}
+
+// WinTotals evaluates market season win total lines against simulated season win distributions.
+func WinTotals(ctx *Context) error {
+	log.Print("Evaluating season win totals")
+
+	fs := ctx.FirestoreClient
+
+	// Get season
+	season, seasonRef, err := bpefs.GetSeason(ctx, fs, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to get season: %w", err)
+	}
+	log.Printf("season discovered: %s", seasonRef.ID)
+
+	// Get week
+	var week bpefs.Week
+	var weekRef *firestore.DocumentRef
+	if ctx.Week < 0 {
+		week, weekRef, err = bpefs.GetFirstWeek(ctx, seasonRef)
+	} else {
+		week, weekRef, err = bpefs.GetWeek(ctx, seasonRef, ctx.Week)
+	}
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to get week: %w", err)
+	}
+	log.Printf("week discovered: %s", weekRef.ID)
+
+	// Build the probability model
+	modelName := ctx.Model
+	if modelName == "" {
+		modelName = "linesag"
+	}
+	model, err := bts.GetGaussianSpreadModel(ctx, fs, weekRef, modelName)
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to build model: %w", err)
+	}
+
+	// Read lines
+	lines, err := ReadLines(ctx.File)
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to read lines from '%s': %w", ctx.File, err)
+	}
+	log.Printf("Read %d lines from %s", len(lines), ctx.File)
+
+	// Get teams
+	teamsByName, err := bpefs.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to retrieve team references: %w", err)
+	}
+	lineRefs := make([]*firestore.DocumentRef, len(lines))
+	for i, line := range lines {
+		ref, err := teamsByName.Resolve(line.Team, bpefs.ShortName)
+		if err != nil {
+			return fmt.Errorf("WinTotals: %w", err)
+		}
+		lineRefs[i] = ref
+	}
+
+	// Simulate
+	log.Printf("Building schedule for %d teams", len(lineRefs))
+	schedule, err := bts.MakeSchedule(ctx, seasonRef, week.Number, lineRefs)
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to make schedule: %w", err)
+	}
+	iterations := ctx.Iterations
+	if iterations <= 0 {
+		iterations = 10000
+	}
+	log.Printf("Simulating %d seasons", iterations)
+	hists := posteriors.WinHistograms(&schedule, model, iterations, false)
+
+	// Lines are for the full season, so add the wins banked before the simulated weeks.
+	records, err := pyp.RecordsBefore(ctx, seasonRef, lineRefs, week.Number, time.Now())
+	if err != nil {
+		return fmt.Errorf("WinTotals: unable to tally records before week %d: %w", week.Number, err)
+	}
+
+	evals := make([]Evaluation, len(lines))
+	ponyWins := make([]float64, len(lines))
+	for i, line := range lines {
+		record := records[lineRefs[i].ID]
+		if n := record.Undecided(); n > 0 {
+			log.Printf("WARNING: %s has %d game(s) without a final score before week %d that are neither counted nor simulated", line.Team, n, week.Number)
+		}
+		evals[i] = Evaluate(line, AddWins(hists[bts.Team(lineRefs[i].ID)], record.Wins))
+		// Negative pony wins mark top 25 teams, but the line is the same.
+		ponyWins[i] = math.Abs(season.PonyTeams[lineRefs[i].ID])
+	}
+
+	prettyPrint(evals, ponyWins, ctx.KellyFraction, ctx.Bankroll)
+
+	log.Print("Done")
+
+	return nil
+}
+
+// ReadLines reads win total lines from a CSV file with columns team (short name), total, over price, and under price.
+// Prices are American odds. Blank lines, lines starting with '#', and a header line starting with "team" are ignored.
+func ReadLines(fileName string) ([]Line, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = 4
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]Line, 0, len(records))
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "team") {
+			continue
+		}
+		var values [3]float64
+		for j := range values {
+			v, err := strconv.ParseFloat(strings.TrimSpace(record[j+1]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: unable to parse '%s': %w", i+1, record[j+1], err)
+			}
+			values[j] = v
+		}
+		for _, price := range values[1:] {
+			if price > -100 && price < 100 {
+				return nil, fmt.Errorf("line %d: price %0.0f is not valid American odds", i+1, price)
+			}
+		}
+		lines = append(lines, Line{
+			Team:       strings.TrimSpace(record[0]),
+			Total:      values[0],
+			OverPrice:  values[1],
+			UnderPrice: values[2],
+		})
+	}
+	return lines, nil
+}
+
+func prettyPrint(evals []Evaluation, ponyWins []float64, kellyFraction float64, bankroll float64) {
+	type row struct {
+		eval Evaluation
+		pony float64
+		side string
+		ev   float64
+		f    float64
+	}
+	rows := make([]row, len(evals))
+	for i, e := range evals {
+		side, f := e.Recommendation()
+		ev := math.Max(e.OverEV, e.UnderEV)
+		rows[i] = row{eval: e, pony: ponyWins[i], side: side, ev: ev, f: f * kellyFraction}
+	}
+	// Best bets first.
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].ev > rows[j].ev })
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Team", "Line", "Over", "Under", "PYP Wins", "Sim. Wins", "P(Over)", "P(Under)", "P(Push)", "Mkt. Over", "Edge", "Bet", "EV", "Kelly", "Stake"})
+	for _, r := range rows {
+		e := r.eval
+		pony := ""
+		if r.pony != 0 {
+			pony = fmt.Sprintf("%0.1f", r.pony)
+		}
+		// The market does not price pushes, so compare against the model's probability of the over given no push.
+		var edge float64
+		if e.POver+e.PUnder > 0 {
+			edge = e.POver/(e.POver+e.PUnder) - e.FairOver
+		}
+		t.AppendRow(table.Row{
+			e.Team,
+			fmt.Sprintf("%0.1f", e.Total),
+			fmt.Sprintf("%+0.0f", e.OverPrice),
+			fmt.Sprintf("%+0.0f", e.UnderPrice),
+			pony,
+			fmt.Sprintf("%0.2f", e.MeanWins),
+			fmt.Sprintf("%0.3f", e.POver),
+			fmt.Sprintf("%0.3f", e.PUnder),
+			fmt.Sprintf("%0.3f", e.PPush),
+			fmt.Sprintf("%0.3f", e.FairOver),
+			fmt.Sprintf("%+0.3f", edge),
+			r.side,
+			fmt.Sprintf("%+0.3f", r.ev),
+			fmt.Sprintf("%0.3f", r.f),
+			fmt.Sprintf("%0.2f", r.f*bankroll),
+		})
+	}
+
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -0,0 +1,99 @@
+package cfbdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Ranking is the set of polls released going into a week.
+type Ranking struct {
+	Season     int    `json:"season"`
+	SeasonType string `json:"seasonType"`
+	Week       int    `json:"week"`
+	Polls      []Poll `json:"polls"`
+}
+
+// Poll is a single poll's ranking of teams.
+type Poll struct {
+	Poll  string `json:"poll"`
+	Ranks []Rank `json:"ranks"`
+}
+
+// Rank is a team's place in a poll.
+type Rank struct {
+	Rank            int    `json:"rank"`
+	TeamID          *int64 `json:"teamId"`
+	School          string `json:"school"`
+	Conference      string `json:"conference"`
+	FirstPlaceVotes int    `json:"firstPlaceVotes"`
+	Points          int    `json:"points"`
+}
+
+// GetRankings gets the polls released going into a week of a season. The season type is "regular" or "postseason".
+func GetRankings(client *http.Client, key string, year int, week int, seasonType string) ([]Ranking, error) {
+	query := url.Values{}
+	query.Set("year", strconv.Itoa(year))
+	query.Set("week", strconv.Itoa(week))
+	if seasonType != "" {
+		query.Set("seasonType", seasonType)
+	}
+	body, err := DoRequest(client, key, "https://api.collegefootballdata.com/rankings?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to do rankings request: %v", err)
+	}
+
+	var rankings []Ranking
+	err = json.Unmarshal(body, &rankings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rankings response body: %v", err)
+	}
+	return rankings, nil
+}
+
+// ToFirestore links the ranked teams to the teams of a season. Teams are linked by ID if the API provides one
+// and the team is in the season, otherwise by school name. Every team that cannot be linked is reported.
+func (p Poll) ToFirestore(teams *firestore.TeamResolver, fetched time.Time) (firestore.Ranking, error) {
+	r := firestore.Ranking{Poll: p.Poll, Fetched: fetched, Teams: make([]firestore.RankedTeam, 0, len(p.Ranks))}
+	var missing []error
+	for _, rank := range p.Ranks {
+		var ref *fs.DocumentRef
+		if rank.TeamID != nil {
+			ref = teamRefByID(teams, *rank.TeamID)
+		}
+		if ref == nil {
+			var err error
+			if ref, err = teams.Resolve(rank.School, firestore.OtherName); err != nil {
+				missing = append(missing, err)
+				continue
+			}
+		}
+		r.Teams = append(r.Teams, firestore.RankedTeam{
+			Rank:            rank.Rank,
+			Team:            ref,
+			Points:          rank.Points,
+			FirstPlaceVotes: rank.FirstPlaceVotes,
+		})
+	}
+	if len(missing) > 0 {
+		return r, fmt.Errorf("failed to link %d ranked teams in %s: %v", len(missing), p.Poll, missing)
+	}
+	return r, nil
+}
+
+func teamRefByID(teams *firestore.TeamResolver, id int64) *fs.DocumentRef {
+	_, refs := teams.Teams()
+	key := strconv.FormatInt(id, 10)
+	for _, ref := range refs {
+		if ref.ID == key {
+			return ref
+		}
+	}
+	return nil
+}
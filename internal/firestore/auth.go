@@ -0,0 +1,115 @@
+package firestore
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// Role is what a picker is allowed to change.
+type Role string
+
+const (
+	// PickerRole can change only the picker's own picks.
+	PickerRole Role = "picker"
+
+	// CommissionerRole runs the pool: it can parse slates, edit teams, resolve streaks, manage pickers, and change anyone's picks.
+	CommissionerRole Role = "commissioner"
+)
+
+// UnmarshalText implements the TextUnmarshaler interface.
+func (r *Role) UnmarshalText(text []byte) error {
+	switch Role(text) {
+	case PickerRole, CommissionerRole:
+		*r = Role(text)
+		return nil
+	}
+	return fmt.Errorf("unknown role '%s': expected '%s' or '%s'", string(text), PickerRole, CommissionerRole)
+}
+
+// UnauthenticatedError is returned when a token is missing or does not belong to any picker.
+type UnauthenticatedError string
+
+func (e UnauthenticatedError) Error() string {
+	return string(e)
+}
+
+// ForbiddenError is returned when a picker is not allowed to make a change.
+type ForbiddenError string
+
+func (e ForbiddenError) Error() string {
+	return string(e)
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of a token.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// NewToken makes a random API token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("NewToken: failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// IsCommissioner returns whether the picker is a commissioner.
+func (p Picker) IsCommissioner() bool {
+	return p.Role == CommissionerRole
+}
+
+// CheckPicksOf returns a ForbiddenError unless the picker may change the picks of the picker with the given LukeName:
+// pickers may change their own picks, and commissioners may change anyone's.
+func (p Picker) CheckPicksOf(lukeName string) error {
+	if p.LukeName == lukeName || p.IsCommissioner() {
+		return nil
+	}
+	return ForbiddenError(fmt.Sprintf("picker '%s' cannot change the picks of picker '%s'", p.LukeName, lukeName))
+}
+
+// CheckCommissioner returns a ForbiddenError unless the picker is a commissioner. What describes the change being made.
+func (p Picker) CheckCommissioner(what string) error {
+	if p.IsCommissioner() {
+		return nil
+	}
+	return ForbiddenError(fmt.Sprintf("picker '%s' cannot %s: only commissioners can", p.LukeName, what))
+}
+
+// Authenticate returns the picker the token belongs to, or UnauthenticatedError.
+func Authenticate(ctx context.Context, client *firestore.Client, token string) (Picker, *firestore.DocumentRef, error) {
+	var p Picker
+	if token == "" {
+		return p, nil, UnauthenticatedError("no token given")
+	}
+	q := client.Collection(PICKERS_COLLECTION).Where("token_hashes", "array-contains", HashToken(token))
+	docs, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return p, nil, fmt.Errorf("Authenticate: failed to query pickers: %w", err)
+	}
+	if len(docs) == 0 {
+		return p, nil, UnauthenticatedError("unknown token")
+	}
+	if len(docs) > 1 {
+		return p, nil, fmt.Errorf("Authenticate: more than one picker has the same token")
+	}
+	if err := docs[0].DataTo(&p); err != nil {
+		return p, nil, fmt.Errorf("Authenticate: failed to convert picker %s: %w", docs[0].Ref.ID, err)
+	}
+	return p, docs[0].Ref, nil
+}
+
+// HasCommissioner returns whether any picker is a commissioner.
+func HasCommissioner(ctx context.Context, client *firestore.Client) (bool, error) {
+	docs, err := client.Collection(PICKERS_COLLECTION).Where("role", "==", string(CommissionerRole)).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return false, fmt.Errorf("HasCommissioner: failed to query pickers: %w", err)
+	}
+	return len(docs) > 0, nil
+}
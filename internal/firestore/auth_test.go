@@ -0,0 +1,54 @@
+package firestore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPickerPermissions(t *testing.T) {
+	alice := Picker{LukeName: "Alice"}
+	commish := Picker{LukeName: "Commish", Role: CommissionerRole}
+
+	if err := alice.CheckPicksOf("Alice"); err != nil {
+		t.Errorf("expected Alice to change her own picks, got %v", err)
+	}
+	if err := alice.CheckPicksOf("Bob"); !errors.As(err, new(ForbiddenError)) {
+		t.Errorf("expected Alice not to change Bob's picks, got %v", err)
+	}
+	if err := alice.CheckCommissioner("parse slates"); !errors.As(err, new(ForbiddenError)) {
+		t.Errorf("expected Alice not to parse slates, got %v", err)
+	}
+	if err := commish.CheckPicksOf("Bob"); err != nil {
+		t.Errorf("expected commissioner to change Bob's picks, got %v", err)
+	}
+	if err := commish.CheckCommissioner("parse slates"); err != nil {
+		t.Errorf("expected commissioner to parse slates, got %v", err)
+	}
+}
+
+func TestRoleUnmarshalText(t *testing.T) {
+	var r Role
+	if err := r.UnmarshalText([]byte("commissioner")); err != nil || r != CommissionerRole {
+		t.Errorf("expected commissioner role, got '%s', %v", r, err)
+	}
+	if err := r.UnmarshalText([]byte("admin")); err == nil {
+		t.Error("expected unknown role to fail")
+	}
+}
+
+func TestTokens(t *testing.T) {
+	a, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("expected new tokens to differ")
+	}
+	if HashToken(a) != HashToken(a) || HashToken(a) == HashToken(b) || HashToken(a) == a {
+		t.Error("expected hashes to be deterministic, distinct, and not the token")
+	}
+}
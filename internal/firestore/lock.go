@@ -0,0 +1,230 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// LOCK_OVERRIDES_COLLECTION is the path to the record of overridden pick locks under a week in Firestore.
+const LOCK_OVERRIDES_COLLECTION = "lock-overrides"
+
+// PickLock is when picks lock in a season.
+// The picks of a game always lock when the game kicks off.
+// If Deadline is true, all of a week's picks also lock at Offset from the start of the week's first game.
+type PickLock struct {
+	// Deadline locks all of a week's picks at once, even for games that have not kicked off.
+	Deadline bool `firestore:"deadline"`
+
+	// Offset is the time from the start of the week's first game at which the week's picks lock.
+	// Negative offsets lock picks before the first game kicks off.
+	Offset time.Duration `firestore:"offset"`
+}
+
+// String implements the Stringer interface.
+func (l PickLock) String() string {
+	if !l.Deadline {
+		return "picks lock at kickoff"
+	}
+	switch {
+	case l.Offset < 0:
+		return fmt.Sprintf("picks lock at kickoff or %s before the first game of the week", -l.Offset)
+	case l.Offset > 0:
+		return fmt.Sprintf("picks lock at kickoff or %s after the first game of the week", l.Offset)
+	}
+	return "picks lock at the first game of the week"
+}
+
+// WeekLockTime returns when all of the week's picks lock. It is the zero time if the week's picks lock game by game.
+func (l PickLock) WeekLockTime(week Week) time.Time {
+	if !l.Deadline || week.FirstGameStart.IsZero() {
+		return time.Time{}
+	}
+	return week.FirstGameStart.Add(l.Offset)
+}
+
+// LockTime returns when the picks of a game lock: the earlier of the game's kickoff and the week's deadline.
+// It is the zero time if the game has no start time and the week has no deadline.
+func (l PickLock) LockTime(week Week, game Game) time.Time {
+	t := game.StartTime
+	if d := l.WeekLockTime(week); !d.IsZero() && (t.IsZero() || d.Before(t)) {
+		t = d
+	}
+	return t
+}
+
+// Locked reports whether picks of the game are locked at time now, and when they lock.
+func (l PickLock) Locked(week Week, game Game, now time.Time) (time.Time, bool) {
+	t := l.LockTime(week, game)
+	return t, !t.IsZero() && !now.Before(t)
+}
+
+// StreakLockTime returns when a Beat the Streak pick of the given teams locks: the earliest lock time of the teams' games.
+// Teams without a game in games are ignored. A bye, or a pick of teams without games, locks at the week's deadline, or at the start of the week's first game if the week has no deadline.
+func (l PickLock) StreakLockTime(week Week, games []Game, teams []*fs.DocumentRef) time.Time {
+	var t time.Time
+	for _, team := range teams {
+		for _, g := range games {
+			if g.HomeTeam.ID != team.ID && g.AwayTeam.ID != team.ID {
+				continue
+			}
+			if gt := l.LockTime(week, g); !gt.IsZero() && (t.IsZero() || gt.Before(t)) {
+				t = gt
+			}
+			break
+		}
+	}
+	if !t.IsZero() {
+		return t
+	}
+	if d := l.WeekLockTime(week); !d.IsZero() {
+		return d
+	}
+	return week.FirstGameStart
+}
+
+// StreakLocked reports whether a Beat the Streak pick of the given teams is locked at time now, and when it locks.
+func (l PickLock) StreakLocked(week Week, games []Game, teams []*fs.DocumentRef, now time.Time) (time.Time, bool) {
+	t := l.StreakLockTime(week, games, teams)
+	return t, !t.IsZero() && !now.Before(t)
+}
+
+// PickLockedError is returned when a pick is changed after it has locked.
+type PickLockedError struct {
+	// What describes the locked pick.
+	What string
+
+	// Locked is when the pick locked.
+	Locked time.Time
+}
+
+func (e PickLockedError) Error() string {
+	return fmt.Sprintf("%s locked at %s", e.What, e.Locked.Local().Format(time.RFC1123))
+}
+
+// PickLockedErrors are the locks a change would break.
+type PickLockedErrors []PickLockedError
+
+func (e PickLockedErrors) Error() string {
+	ss := make([]string, len(e))
+	for i, err := range e {
+		ss[i] = err.Error()
+	}
+	return fmt.Sprintf("%d picks are locked (override with a reason to change them anyway): %s", len(e), strings.Join(ss, "; "))
+}
+
+// LockedPickChanges checks the changes against the lock and returns the errors of the changed picks that are locked at time now, keyed by index in changes.
+// Changes that do not change anything are never locked.
+func LockedPickChanges(ctx context.Context, lock PickLock, week Week, changes []PickChange, now time.Time) (map[int]PickLockedError, error) {
+	locked := make(map[int]PickLockedError)
+	for i, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+		snap, err := c.SlateGame.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var sg SlateGame
+		if err := snap.DataTo(&sg); err != nil {
+			return nil, err
+		}
+		snap, err = sg.Game.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var game Game
+		if err := snap.DataTo(&game); err != nil {
+			return nil, err
+		}
+		if t, ok := lock.Locked(week, game, now); ok {
+			locked[i] = PickLockedError{What: fmt.Sprintf("%s pick of %s @ %s", RefID(c.Picker), RefID(game.AwayTeam), RefID(game.HomeTeam)), Locked: t}
+		}
+	}
+	return locked, nil
+}
+
+// OverrideLocks marks the locked changes as overridden for the given reason.
+// It returns PickLockedErrors if any changes are locked and no reason is given.
+func OverrideLocks(changes []PickChange, locked map[int]PickLockedError, reason string) error {
+	if len(locked) == 0 {
+		return nil
+	}
+	if reason == "" {
+		errs := make(PickLockedErrors, 0, len(locked))
+		for i := range changes {
+			if err, ok := locked[i]; ok {
+				errs = append(errs, err)
+			}
+		}
+		return errs
+	}
+	for i := range locked {
+		changes[i].Override = reason
+	}
+	return nil
+}
+
+// LockOverride is a record of a commissioner changing picks after they locked.
+type LockOverride struct {
+	// Picker is a reference to the picker whose locked picks were changed.
+	Picker *fs.DocumentRef `firestore:"picker"`
+
+	// Locked describes the locked picks that were changed.
+	Locked []string `firestore:"locked"`
+
+	// Reason is why the locks were overridden.
+	Reason string `firestore:"reason"`
+
+	// Actor is the Luke name of the authenticated commissioner who overrode the locks.
+	Actor string `firestore:"actor"`
+
+	// Source is the program that made the change.
+	Source string `firestore:"source"`
+
+	// User is the user and host that ran the program that made the change.
+	User string `firestore:"user"`
+
+	// Timestamp is the time the override was written to Firestore.
+	Timestamp time.Time `firestore:"timestamp,serverTimestamp"`
+}
+
+// NewLockOverride records the authenticated commissioner by overriding the given locks of a picker's picks.
+func NewLockOverride(picker *fs.DocumentRef, locked []PickLockedError, reason string, source string, by Picker) LockOverride {
+	o := LockOverride{Picker: picker, Locked: make([]string, len(locked)), Reason: reason, Actor: by.LukeName, Source: source, User: currentUser()}
+	for i, l := range locked {
+		o.Locked[i] = l.Error()
+	}
+	return o
+}
+
+// RecordLockOverride adds a lock override to the week's record of overrides as part of a transaction.
+func RecordLockOverride(t *fs.Transaction, week *fs.DocumentRef, o LockOverride) error {
+	return t.Create(week.Collection(LOCK_OVERRIDES_COLLECTION).NewDoc(), &o)
+}
+
+// GetLockOverrides returns the lock overrides of a week in the order they were made.
+// If picker is not nil, only overrides of that picker's locks are returned.
+func GetLockOverrides(ctx context.Context, week, picker *fs.DocumentRef) ([]LockOverride, error) {
+	q := week.Collection(LOCK_OVERRIDES_COLLECTION).Query
+	if picker != nil {
+		q = q.Where("picker", "==", picker)
+	}
+	snaps, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, err
+	}
+	// overrides are never updated, so the documents' creation times are the times the overrides were made
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].CreateTime.Before(snaps[j].CreateTime) })
+	overrides := make([]LockOverride, len(snaps))
+	for i, snap := range snaps {
+		if err := snap.DataTo(&overrides[i]); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
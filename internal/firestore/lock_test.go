@@ -0,0 +1,104 @@
+package firestore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestPickLock(t *testing.T) {
+	first := time.Date(2021, 9, 4, 12, 0, 0, 0, time.UTC)
+	week := Week{Number: 1, FirstGameStart: first}
+	early := Game{StartTime: first}
+	late := Game{StartTime: first.Add(8 * time.Hour)}
+
+	tests := []struct {
+		name   string
+		lock   PickLock
+		game   Game
+		now    time.Time
+		want   time.Time
+		locked bool
+	}{
+		{"before kickoff", PickLock{}, late, first.Add(time.Hour), late.StartTime, false},
+		{"at kickoff", PickLock{}, early, first, first, true},
+		{"after kickoff", PickLock{}, late, late.StartTime.Add(time.Minute), late.StartTime, true},
+		{"no start time", PickLock{}, Game{}, first, time.Time{}, false},
+		{"deadline at first game", PickLock{Deadline: true}, late, first.Add(time.Hour), first, true},
+		{"deadline before first game", PickLock{Deadline: true, Offset: -time.Hour}, early, first.Add(-30 * time.Minute), first.Add(-time.Hour), true},
+		{"deadline after kickoff", PickLock{Deadline: true, Offset: 24 * time.Hour}, late, first.Add(time.Hour), late.StartTime, false},
+		{"deadline without start time", PickLock{Deadline: true, Offset: time.Hour}, Game{}, first, first.Add(time.Hour), false},
+	}
+	for _, test := range tests {
+		got, locked := test.lock.Locked(week, test.game, test.now)
+		if !got.Equal(test.want) {
+			t.Errorf("%s: expected lock time %s, got %s", test.name, test.want, got)
+		}
+		if locked != test.locked {
+			t.Errorf("%s: expected locked %t, got %t", test.name, test.locked, locked)
+		}
+	}
+}
+
+func TestStreakLockTime(t *testing.T) {
+	first := time.Date(2021, 9, 4, 12, 0, 0, 0, time.UTC)
+	week := Week{Number: 1, FirstGameStart: first}
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	iowa := &fs.DocumentRef{ID: "iowa"}
+	msu := &fs.DocumentRef{ID: "msu"}
+	ill := &fs.DocumentRef{ID: "ill"}
+	games := []Game{
+		{HomeTeam: mich, AwayTeam: osu, StartTime: first.Add(4 * time.Hour)},
+		{HomeTeam: iowa, AwayTeam: msu, StartTime: first.Add(2 * time.Hour)},
+	}
+
+	if got := (PickLock{}).StreakLockTime(week, games, []*fs.DocumentRef{osu}); !got.Equal(games[0].StartTime) {
+		t.Errorf("single: expected lock at kickoff, got %s", got)
+	}
+	if got := (PickLock{}).StreakLockTime(week, games, []*fs.DocumentRef{osu, msu}); !got.Equal(games[1].StartTime) {
+		t.Errorf("double: expected lock at the earlier kickoff, got %s", got)
+	}
+	if got := (PickLock{}).StreakLockTime(week, games, nil); !got.Equal(first) {
+		t.Errorf("bye: expected lock at the first game of the week, got %s", got)
+	}
+	if got := (PickLock{Deadline: true, Offset: -time.Hour}).StreakLockTime(week, games, []*fs.DocumentRef{ill}); !got.Equal(first.Add(-time.Hour)) {
+		t.Errorf("no game: expected lock at the deadline, got %s", got)
+	}
+	if _, locked := (PickLock{}).StreakLocked(week, games, []*fs.DocumentRef{mich}, first.Add(3*time.Hour)); locked {
+		t.Error("expected pick to be unlocked before kickoff")
+	}
+}
+
+func TestOverrideLocks(t *testing.T) {
+	changes := []PickChange{{Created: true}, {Created: true}, {Created: true}}
+	if err := OverrideLocks(changes, nil, ""); err != nil {
+		t.Errorf("expected no error without locks, got %v", err)
+	}
+
+	locked := map[int]PickLockedError{
+		2: {What: "c", Locked: time.Unix(0, 0)},
+		0: {What: "a", Locked: time.Unix(0, 0)},
+	}
+	err := OverrideLocks(changes, locked, "")
+	var lockErrs PickLockedErrors
+	if !errors.As(err, &lockErrs) || len(lockErrs) != 2 || lockErrs[0].What != "a" || lockErrs[1].What != "c" {
+		t.Errorf("expected locked errors in change order, got %v", err)
+	}
+	for i, c := range changes {
+		if c.Override != "" {
+			t.Errorf("change %d: expected no override without a reason, got %q", i, c.Override)
+		}
+	}
+
+	if err := OverrideLocks(changes, locked, "stat correction"); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"stat correction", "", "stat correction"} {
+		if changes[i].Override != want {
+			t.Errorf("change %d: expected override %q, got %q", i, want, changes[i].Override)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package firestore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// MissingPicks is what a picker has not yet picked in a week.
+type MissingPicks struct {
+	// Picker is the LukeName of the picker.
+	Picker string
+
+	// PickerRef is a reference to the picker.
+	PickerRef *fs.DocumentRef
+
+	// Games are references to the slate games, other than superdog games, that the picker has not picked, in slate order.
+	Games []*fs.DocumentRef
+
+	// NoSuperdog is true if the slate has superdog games and the picker has not picked the underdog in any of them.
+	// Picks of the favorite in a superdog game score no points, so they do not count.
+	NoSuperdog bool
+
+	// NoStreakPick is true if the picker is still in Beat the Streak and has not made a streak pick.
+	NoStreakPick bool
+}
+
+// Complete returns whether nothing is missing.
+func (m MissingPicks) Complete() bool {
+	return len(m.Games) == 0 && !m.NoSuperdog && !m.NoStreakPick
+}
+
+// Active returns whether the picker has any streak picks remaining, byes included.
+func (s StreakTeamsRemaining) Active() bool {
+	for _, n := range s.PickTypesRemaining {
+		if n > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindMissingPicks cross-references a season's pickers (LukeNames mapped to references) with the picks made on a slate and returns what each picker is missing, ordered by LukeName.
+// Pickers who are missing nothing are left out.
+// Games are the week's games keyed by ID, which are needed to tell the underdog of each superdog game.
+// Streaks are the Beat the Streak teams remaining going into the week keyed by picker ID, and streakPicked is true for each picker ID with a streak pick; only pickers with an active streak need a streak pick.
+func FindMissingPicks(pickers map[string]*fs.DocumentRef, sgs []SlateGame, sgRefs []*fs.DocumentRef, games map[string]Game, picks []Pick, streaks map[string]StreakTeamsRemaining, streakPicked map[string]bool) []MissingPicks {
+	// picked teams keyed by picker ID and slate game ID
+	picked := make(map[string]map[string]*fs.DocumentRef)
+	for _, p := range picks {
+		if p.Picker == nil || p.SlateGame == nil || p.PickedTeam == nil {
+			continue
+		}
+		if _, ok := picked[p.Picker.ID]; !ok {
+			picked[p.Picker.ID] = make(map[string]*fs.DocumentRef)
+		}
+		picked[p.Picker.ID][p.SlateGame.ID] = p.PickedTeam
+	}
+
+	hasSuperdog := false
+	for _, sg := range sgs {
+		hasSuperdog = hasSuperdog || sg.Superdog
+	}
+
+	missing := make([]MissingPicks, 0, len(pickers))
+	for name, ref := range pickers {
+		m := MissingPicks{Picker: name, PickerRef: ref, NoSuperdog: hasSuperdog}
+		for i, sg := range sgs {
+			team, ok := picked[ref.ID][sgRefs[i].ID]
+			switch {
+			case sg.Superdog && ok && RefID(sg.Underdog(games[RefID(sg.Game)])) == team.ID:
+				m.NoSuperdog = false
+			case !sg.Superdog && !ok:
+				m.Games = append(m.Games, sgRefs[i])
+			}
+		}
+		if s, ok := streaks[ref.ID]; ok && s.Active() && !streakPicked[ref.ID] {
+			m.NoStreakPick = true
+		}
+		if !m.Complete() {
+			missing = append(missing, m)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].Picker < missing[j].Picker })
+	return missing
+}
+
+// Reminder returns a message reminding the picker of what is missing.
+// Games are the names of the missing games, in the order of m.Games.
+func (m MissingPicks) Reminder(season int, lock PickLock, week Week, games []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Hi %s,\n\nYour picks for week %d of the %d season are not complete yet:\n", m.Picker, week.Number, season)
+	if len(games) > 0 {
+		fmt.Fprintf(&sb, "- %d game(s) without a pick:\n", len(games))
+		for _, g := range games {
+			fmt.Fprintf(&sb, "    %s\n", g)
+		}
+	}
+	if m.NoSuperdog {
+		sb.WriteString("- no superdog picked\n")
+	}
+	if m.NoStreakPick {
+		sb.WriteString("- no Beat the Streak pick\n")
+	}
+	if t := lock.WeekLockTime(week); !t.IsZero() {
+		fmt.Fprintf(&sb, "\nAll picks lock at %s.\n", t.Local().Format(time.RFC1123))
+	} else if !week.FirstGameStart.IsZero() {
+		fmt.Fprintf(&sb, "\nPicks lock at kickoff, and the first game kicks off at %s.\n", week.FirstGameStart.Local().Format(time.RFC1123))
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package firestore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestFindMissingPicks(t *testing.T) {
+	alice, bob, carol, dave := &fs.DocumentRef{ID: "alice"}, &fs.DocumentRef{ID: "bob"}, &fs.DocumentRef{ID: "carol"}, &fs.DocumentRef{ID: "dave"}
+	pickers := map[string]*fs.DocumentRef{"Alice": alice, "Bob": bob, "Carol": carol, "Dave": dave}
+	sg1, sg2, sg3 := &fs.DocumentRef{ID: "sg1"}, &fs.DocumentRef{ID: "sg2"}, &fs.DocumentRef{ID: "sg3"}
+	sgRefs := []*fs.DocumentRef{sg1, sg2, sg3}
+	mich, osu := &fs.DocumentRef{ID: "mich"}, &fs.DocumentRef{ID: "osu"}
+	games := map[string]Game{"g3": {HomeTeam: mich, AwayTeam: osu}}
+	sgs := []SlateGame{{Row: 1}, {Row: 2}, {Row: 3, Game: &fs.DocumentRef{ID: "g3"}, Superdog: true, HomeFavored: false}}
+	picks := []Pick{
+		{Picker: alice, SlateGame: sg1, PickedTeam: mich},
+		{Picker: alice, SlateGame: sg2, PickedTeam: mich},
+		{Picker: alice, SlateGame: sg3, PickedTeam: mich},
+		{Picker: bob, SlateGame: sg1, PickedTeam: mich},
+		{Picker: bob, SlateGame: sg3}, // unpicked superdog
+		{Picker: carol, SlateGame: sg1, PickedTeam: mich},
+		{Picker: carol, SlateGame: sg2, PickedTeam: mich},
+		{Picker: carol, SlateGame: sg3, PickedTeam: mich},
+		{Picker: dave, SlateGame: sg1, PickedTeam: mich},
+		{Picker: dave, SlateGame: sg2, PickedTeam: mich},
+		{Picker: dave, SlateGame: sg3, PickedTeam: osu}, // favorite of a superdog game
+	}
+	streaks := map[string]StreakTeamsRemaining{
+		"alice": {Picker: alice, PickTypesRemaining: []int{0, 1}},
+		"bob":   {Picker: bob, PickTypesRemaining: []int{0, 0}},
+		"carol": {Picker: carol, PickTypesRemaining: []int{1, 0}},
+	}
+
+	missing := FindMissingPicks(pickers, sgs, sgRefs, games, picks, streaks, map[string]bool{"carol": true})
+	if len(missing) != 3 {
+		t.Fatalf("expected Alice, Bob, and Dave to be missing picks, got %+v", missing)
+	}
+	a, b, d := missing[0], missing[1], missing[2]
+	if a.Picker != "Alice" || len(a.Games) != 0 || a.NoSuperdog || !a.NoStreakPick {
+		t.Errorf("expected Alice to be missing only a streak pick, got %+v", a)
+	}
+	if b.Picker != "Bob" || len(b.Games) != 1 || b.Games[0].ID != "sg2" || !b.NoSuperdog || b.NoStreakPick {
+		t.Errorf("expected Bob to be missing a game and a superdog but no streak pick, got %+v", b)
+	}
+	if d.Picker != "Dave" || len(d.Games) != 0 || !d.NoSuperdog || d.NoStreakPick {
+		t.Errorf("expected Dave to be missing only a superdog, got %+v", d)
+	}
+
+	// a slate without superdog games needs no superdog
+	missing = FindMissingPicks(pickers, sgs[:2], sgRefs[:2], games, picks, nil, nil)
+	if len(missing) != 1 || missing[0].Picker != "Bob" || missing[0].NoSuperdog {
+		t.Errorf("expected only Bob to be missing a game, got %+v", missing)
+	}
+}
+
+func TestMissingPicksReminder(t *testing.T) {
+	m := MissingPicks{Picker: "Bob", Games: []*fs.DocumentRef{{ID: "sg2"}}, NoSuperdog: true}
+	week := Week{Number: 3, FirstGameStart: time.Date(2023, 9, 16, 16, 0, 0, 0, time.UTC)}
+	r := m.Reminder(2023, PickLock{Deadline: true, Offset: -time.Hour}, week, []string{"Iowa @ Michigan"})
+	for _, want := range []string{"Hi Bob", "week 3 of the 2023 season", "Iowa @ Michigan", "no superdog", "All picks lock at"} {
+		if !strings.Contains(r, want) {
+			t.Errorf("expected reminder to contain %q, got:\n%s", want, r)
+		}
+	}
+	if strings.Contains(r, "Beat the Streak") {
+		t.Errorf("expected reminder not to mention a streak pick, got:\n%s", r)
+	}
+}
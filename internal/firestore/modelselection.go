@@ -0,0 +1,269 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// MODEL_SELECTIONS_COLLECTION is the path to the model selections collection under a week in Firestore.
+const MODEL_SELECTIONS_COLLECTION = "model-selections"
+
+// GameType is the kind of pick made in a slate game.
+type GameType string
+
+const (
+	// StraightUpGame is a game picked straight up.
+	StraightUpGame GameType = "straight-up"
+
+	// NoisySpreadGame is a game picked against a noisy spread.
+	NoisySpreadGame GameType = "noisy-spread"
+
+	// SuperdogGame is a game in which only the underdog can be picked.
+	SuperdogGame GameType = "superdog"
+)
+
+// GameTypes are all the game types in the order they are selected.
+var GameTypes = []GameType{StraightUpGame, NoisySpreadGame, SuperdogGame}
+
+// Type returns the type of pick made in the slate game.
+func (sg SlateGame) Type() GameType {
+	switch {
+	case sg.NoisySpread != 0:
+		return NoisySpreadGame
+	case sg.Superdog:
+		return SuperdogGame
+	}
+	return StraightUpGame
+}
+
+// ModelRecord is how a model's predictions of one type of slate game have turned out.
+type ModelRecord struct {
+	Model *fs.DocumentRef
+
+	// Games is the number of decided games the model predicted.
+	Games int
+
+	// Correct is the number of games in which the model's pick would have been correct.
+	// Superdog games are counted as correct if the model predicted the winner, whether or not it was the underdog.
+	Correct int
+
+	// AbsError is the sum of the absolute errors of the model's predicted spreads.
+	AbsError float64
+}
+
+// PercentCorrect is the fraction of games the model picked correctly.
+func (r ModelRecord) PercentCorrect() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Games)
+}
+
+// MAE is the mean absolute error of the model's predicted spreads.
+func (r ModelRecord) MAE() float64 {
+	if r.Games == 0 {
+		return math.Inf(1)
+	}
+	return r.AbsError / float64(r.Games)
+}
+
+// ModelHistory collects model records by game type and model ID.
+type ModelHistory map[GameType]map[string]*ModelRecord
+
+// Add records a model's prediction of a slate game. It returns false if the game has not been decided.
+func (h ModelHistory) Add(sg SlateGame, game Game, pred ModelPrediction) bool {
+	if game.HomePoints == nil || game.AwayPoints == nil || pred.Model == nil {
+		return false
+	}
+	spread := pred.Spread
+	if pred.HomeTeam != nil && pred.HomeTeam.ID != game.HomeTeam.ID {
+		spread = -spread
+	}
+	margin := float64(*game.HomePoints - *game.AwayPoints)
+
+	var correct bool
+	switch sg.Type() {
+	case NoisySpreadGame:
+		picked := game.AwayTeam
+		if spread >= float64(sg.NoisySpread) {
+			picked = game.HomeTeam
+		}
+		points, _ := sg.Points(game, picked)
+		correct = points > 0
+	default:
+		correct = (spread >= 0) == (margin > 0)
+	}
+
+	t := sg.Type()
+	if _, ok := h[t]; !ok {
+		h[t] = make(map[string]*ModelRecord)
+	}
+	r, ok := h[t][pred.Model.ID]
+	if !ok {
+		r = &ModelRecord{Model: pred.Model}
+		h[t][pred.Model.ID] = r
+	}
+	r.Games++
+	if correct {
+		r.Correct++
+	}
+	r.AbsError += math.Abs(spread - margin)
+	return true
+}
+
+// ModelChoice is the model chosen to pick one type of slate game.
+type ModelChoice struct {
+	// GameType is the type of game the model is chosen for.
+	GameType GameType `firestore:"game_type"`
+
+	// Model is a reference to the chosen model. It is nil if no model has enough history to choose.
+	Model *fs.DocumentRef `firestore:"model"`
+
+	// Games is the number of decided games of the type the model predicted.
+	Games int `firestore:"games"`
+
+	// Correct is the number of those games the model picked correctly.
+	Correct int `firestore:"correct"`
+
+	// MAE is the mean absolute error of the model's predicted spreads in those games.
+	MAE float64 `firestore:"mae"`
+
+	// Reason explains why the model was chosen.
+	Reason string `firestore:"reason"`
+}
+
+// String implements the Stringer interface.
+func (c ModelChoice) String() string {
+	model := "none"
+	if c.Model != nil {
+		model = c.Model.ID
+	}
+	return fmt.Sprintf("%s: %s (%s)", c.GameType, model, c.Reason)
+}
+
+// better reports whether record a is better than record b for picking games of type t.
+// Superdogs are picked by the chance the underdog wins, so the closest spreads are best. Other games are picked by the most correct picks.
+func better(t GameType, a, b *ModelRecord) bool {
+	if t == SuperdogGame {
+		if a.MAE() != b.MAE() {
+			return a.MAE() < b.MAE()
+		}
+		return a.PercentCorrect() > b.PercentCorrect()
+	}
+	if a.PercentCorrect() != b.PercentCorrect() {
+		return a.PercentCorrect() > b.PercentCorrect()
+	}
+	return a.MAE() < b.MAE()
+}
+
+// Select chooses the best model for each game type among the models that predicted at least minGames decided games of that type.
+func (h ModelHistory) Select(minGames int) []ModelChoice {
+	choices := make([]ModelChoice, 0, len(GameTypes))
+	for _, t := range GameTypes {
+		records := make([]*ModelRecord, 0, len(h[t]))
+		for _, r := range h[t] {
+			if r.Games >= minGames {
+				records = append(records, r)
+			}
+		}
+		sort.Slice(records, func(i, j int) bool {
+			if better(t, records[i], records[j]) {
+				return true
+			}
+			if better(t, records[j], records[i]) {
+				return false
+			}
+			return records[i].Model.ID < records[j].Model.ID
+		})
+
+		choice := ModelChoice{GameType: t}
+		if len(records) == 0 {
+			choice.Reason = fmt.Sprintf("no model predicted at least %d decided %s games", minGames, t)
+			choices = append(choices, choice)
+			continue
+		}
+		best := records[0]
+		choice.Model = best.Model
+		choice.Games = best.Games
+		choice.Correct = best.Correct
+		choice.MAE = best.MAE()
+		choice.Reason = reason(t, records)
+		choices = append(choices, choice)
+	}
+	return choices
+}
+
+func reason(t GameType, records []*ModelRecord) string {
+	var sb strings.Builder
+	best := records[0]
+	if t == SuperdogGame {
+		sb.WriteString(fmt.Sprintf("smallest MAE in %s games: %0.2f over %d games (%d of %d winners correct)", t, best.MAE(), best.Games, best.Correct, best.Games))
+	} else {
+		sb.WriteString(fmt.Sprintf("best record in %s games: %d of %d correct (%0.1f%%, MAE %0.2f)", t, best.Correct, best.Games, 100*best.PercentCorrect(), best.MAE()))
+	}
+	if len(records) < 2 {
+		sb.WriteString("; no other model qualified")
+		return sb.String()
+	}
+	next := records[1]
+	if t == SuperdogGame {
+		sb.WriteString(fmt.Sprintf("; next best %s: MAE %0.2f over %d games", next.Model.ID, next.MAE(), next.Games))
+	} else {
+		sb.WriteString(fmt.Sprintf("; next best %s: %d of %d correct (%0.1f%%)", next.Model.ID, next.Correct, next.Games, 100*next.PercentCorrect()))
+	}
+	return sb.String()
+}
+
+// ModelSelection is the models chosen to pick each type of slate game in a week.
+type ModelSelection struct {
+	// Choices are the models chosen for each game type.
+	Choices []ModelChoice `firestore:"choices"`
+
+	// Seasons are the years of the seasons whose games were used to evaluate the models.
+	Seasons []int `firestore:"seasons"`
+
+	// Weeks is the number of weeks with slates used to evaluate the models.
+	Weeks int `firestore:"weeks"`
+
+	// Timestamp is when the selection was made.
+	Timestamp time.Time `firestore:"timestamp,serverTimestamp"`
+}
+
+// Choice returns the choice for a game type.
+func (s ModelSelection) Choice(t GameType) (ModelChoice, bool) {
+	for _, c := range s.Choices {
+		if c.GameType == t {
+			return c, true
+		}
+	}
+	return ModelChoice{}, false
+}
+
+// NoModelSelectionError is returned when no model selection has been made for a week.
+type NoModelSelectionError string
+
+func (e NoModelSelectionError) Error() string {
+	return fmt.Sprintf("no model selection found for week %s", string(e))
+}
+
+// GetModelSelection returns the most recent model selection made for a week.
+func GetModelSelection(ctx context.Context, week *fs.DocumentRef) (ModelSelection, *fs.DocumentRef, error) {
+	var s ModelSelection
+	snaps, err := week.Collection(MODEL_SELECTIONS_COLLECTION).OrderBy("timestamp", fs.Desc).Limit(1).Documents(ctx).GetAll()
+	if err != nil {
+		return s, nil, err
+	}
+	if len(snaps) == 0 {
+		return s, nil, NoModelSelectionError(week.ID)
+	}
+	if err := snaps[0].DataTo(&s); err != nil {
+		return s, nil, err
+	}
+	return s, snaps[0].Ref, nil
+}
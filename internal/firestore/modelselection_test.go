@@ -0,0 +1,69 @@
+package firestore
+
+import (
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestModelHistorySelect(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	sharp := &fs.DocumentRef{ID: "sharp"}
+	close := &fs.DocumentRef{ID: "close"}
+	game := func(home, away int) Game {
+		return Game{HomeTeam: mich, AwayTeam: osu, HomePoints: &home, AwayPoints: &away}
+	}
+	pred := func(model *fs.DocumentRef, spread float64) ModelPrediction {
+		return ModelPrediction{Model: model, HomeTeam: mich, AwayTeam: osu, Spread: spread}
+	}
+
+	h := make(ModelHistory)
+	su := SlateGame{Value: 1}
+	// sharp picks both winners, close misses one but is nearer the margins
+	h.Add(su, game(28, 21), pred(sharp, 20))
+	h.Add(su, game(28, 21), pred(close, 8))
+	h.Add(su, game(21, 24), pred(sharp, -20))
+	h.Add(su, game(21, 24), pred(close, 1))
+
+	noisy := SlateGame{Value: 2, NoisySpread: 10, HomeFavored: true}
+	// home wins by 7, so only a pick of the away team against the spread is correct
+	h.Add(noisy, game(28, 21), pred(sharp, 14))
+	h.Add(noisy, game(28, 21), pred(close, 6))
+
+	dog := SlateGame{Value: 5, Superdog: true, HomeFavored: true}
+	h.Add(dog, game(28, 21), pred(sharp, 20))
+	h.Add(dog, game(28, 21), pred(close, 8))
+
+	// swapped home and away in the prediction
+	if !h.Add(dog, game(28, 21), ModelPrediction{Model: sharp, HomeTeam: osu, AwayTeam: mich, Spread: -20}) {
+		t.Error("expected decided game to be added")
+	}
+	if h.Add(su, Game{HomeTeam: mich, AwayTeam: osu}, pred(sharp, 1)) {
+		t.Error("expected undecided game not to be added")
+	}
+
+	choices := h.Select(1)
+	want := map[GameType]string{StraightUpGame: "sharp", NoisySpreadGame: "close", SuperdogGame: "close"}
+	if len(choices) != len(want) {
+		t.Fatalf("expected %d choices, got %v", len(want), choices)
+	}
+	for _, c := range choices {
+		if c.Model == nil || c.Model.ID != want[c.GameType] {
+			t.Errorf("%s: expected %s, got %s", c.GameType, want[c.GameType], c)
+		}
+		if c.Reason == "" {
+			t.Errorf("%s: expected a reason", c.GameType)
+		}
+	}
+	if choices[0].Correct != 2 || choices[0].Games != 2 {
+		t.Errorf("expected sharp to be 2 of 2 straight up, got %s", choices[0])
+	}
+
+	choices = h.Select(3)
+	for _, c := range choices {
+		if c.Model != nil {
+			t.Errorf("%s: expected no model to qualify, got %s", c.GameType, c)
+		}
+	}
+}
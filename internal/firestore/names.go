@@ -0,0 +1,172 @@
+package firestore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cloud.google.com/go/firestore"
+)
+
+// nameExpansions are common abbreviations in team names and the words they stand for.
+// Words that map to the empty string are dropped.
+var nameExpansions = map[string]string{
+	"st":   "state",
+	"u":    "university",
+	"univ": "university",
+	"so":   "southern",
+	"cent": "central",
+	"intl": "international",
+	"mt":   "mount",
+	"ft":   "fort",
+	"miss": "mississippi",
+	"mich": "michigan",
+	"fla":  "florida",
+	"ky":   "kentucky",
+	"tenn": "tennessee",
+	"wash": "washington",
+	"car":  "carolina",
+	"la":   "louisiana",
+	"the":  "",
+	"of":   "",
+	"at":   "",
+}
+
+// NormalizeName converts a team name to a canonical form for comparison:
+// lower case, without punctuation, with common abbreviations expanded (e.g., "Ohio St." becomes "ohio state").
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, "a&m", " aandm ")
+	name = strings.ReplaceAll(name, "&", " and ")
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		case r == '\'' || r == '.':
+			// Apostrophes and periods join the letters around them: "Hawai'i" is "hawaii".
+		default:
+			sb.WriteRune(' ')
+		}
+	}
+	tokens := strings.Fields(sb.String())
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if x, ok := nameExpansions[t]; ok {
+			t = x
+		}
+		if t != "" {
+			out = append(out, t)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// NameSimilarity scores how similar two team names are, from 0 (nothing in common) to 1 (the same after normalization).
+// The score is the greater of the edit-distance similarity of the normalized names and the similarity of their sorted words,
+// so that reordered names like "Miami (OH)" and "OH Miami" still score highly.
+func NameSimilarity(a, b string) float64 {
+	na := NormalizeName(a)
+	nb := NormalizeName(b)
+	if na == nb {
+		return 1
+	}
+	if na == "" || nb == "" {
+		return 0
+	}
+	score := editSimilarity(na, nb)
+
+	ta := strings.Fields(na)
+	tb := strings.Fields(nb)
+	sort.Strings(ta)
+	sort.Strings(tb)
+	if s := editSimilarity(strings.Join(ta, " "), strings.Join(tb, " ")); s > score {
+		score = s
+	}
+	return score
+}
+
+func editSimilarity(a, b string) float64 {
+	ra := []rune(a)
+	rb := []rune(b)
+	n := len(ra)
+	if len(rb) > n {
+		n = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(n)
+}
+
+// NameCandidate is a team that might be referred to by a given name.
+type NameCandidate struct {
+	Team Team
+	Ref  *firestore.DocumentRef
+
+	// Score is the similarity between the name and the closest of the team's names.
+	Score float64
+
+	// MatchedName is the team's name that is closest to the given name.
+	MatchedName string
+}
+
+// String implements the Stringer interface.
+func (c NameCandidate) String() string {
+	return fmt.Sprintf("(%s) %s [%0.3f via '%s']", c.Ref.ID, c.Team, c.Score, c.MatchedName)
+}
+
+// Names returns the names by which a team is known: school, short names, and other names.
+// Abbreviations are not included because they are too short to compare reliably.
+func (t Team) Names() []string {
+	names := make([]string, 0, len(t.ShortNames)+len(t.OtherNames)+1)
+	if t.School != "" {
+		names = append(names, t.School)
+	}
+	names = append(names, t.ShortNames...)
+	names = append(names, t.OtherNames...)
+	return names
+}
+
+// RankNameCandidates scores every team against the given name and returns the teams in order of decreasing similarity.
+func RankNameCandidates(teams []Team, refs []*firestore.DocumentRef, name string) []NameCandidate {
+	candidates := make([]NameCandidate, len(teams))
+	for i, t := range teams {
+		c := NameCandidate{Team: t, Ref: refs[i]}
+		for _, n := range t.Names() {
+			if s := NameSimilarity(name, n); s > c.Score {
+				c.Score = s
+				c.MatchedName = n
+			}
+		}
+		candidates[i] = c
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
+}
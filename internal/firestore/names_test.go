@@ -0,0 +1,68 @@
+package firestore
+
+import (
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Ohio State", "ohio state"},
+		{"Ohio St.", "ohio state"},
+		{"  OHIO   ST ", "ohio state"},
+		{"Texas A&M", "texas aandm"},
+		{"Hawai'i", "hawaii"},
+		{"Miami (OH)", "miami oh"},
+		{"The Citadel", "citadel"},
+		{"So. Miss", "southern mississippi"},
+	}
+	for _, test := range tests {
+		if got := NormalizeName(test.name); got != test.want {
+			t.Errorf("NormalizeName(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	if s := NameSimilarity("Ohio St.", "Ohio State"); s != 1 {
+		t.Errorf("expected normalized names to be identical, got %f", s)
+	}
+	if s := NameSimilarity("OH Miami", "Miami (OH)"); s != 1 {
+		t.Errorf("expected reordered names to be identical, got %f", s)
+	}
+	if s := NameSimilarity("Michigan", "Michgan"); s < .85 || s >= 1 {
+		t.Errorf("expected typo to be similar, got %f", s)
+	}
+	if s := NameSimilarity("Michigan", "Oregon"); s > .5 {
+		t.Errorf("expected different names to be dissimilar, got %f", s)
+	}
+	if s := NameSimilarity("", "Oregon"); s != 0 {
+		t.Errorf("expected empty name to have no similarity, got %f", s)
+	}
+}
+
+func TestRankNameCandidates(t *testing.T) {
+	teams := []Team{
+		{School: "Michigan", ShortNames: []string{"UM"}, OtherNames: []string{"Michigan"}},
+		{School: "Michigan State", ShortNames: []string{"MSU"}, OtherNames: []string{"Michigan St"}},
+		{School: "Ohio State", ShortNames: []string{"OSU"}, OtherNames: []string{"Ohio St"}},
+	}
+	refs := []*fs.DocumentRef{{ID: "mich"}, {ID: "msu"}, {ID: "osu"}}
+
+	candidates := RankNameCandidates(teams, refs, "Mich. St.")
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %d", len(candidates))
+	}
+	if candidates[0].Ref.ID != "msu" || candidates[0].Score != 1 {
+		t.Errorf("expected Michigan State to match exactly, got %v", candidates[0])
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score > candidates[i-1].Score {
+			t.Errorf("expected candidates in decreasing order of score, got %v", candidates)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// PICK_CHANGES_COLLECTION is the path to the pick change log under a week in Firestore.
+const PICK_CHANGES_COLLECTION = "pick-changes"
+
+// PickChange is a record of a pick being made or changed.
+// Pick changes are only ever added, never updated or deleted, so together they are the history of every pick in a week.
+type PickChange struct {
+	// Picker is a reference to the picker whose pick changed.
+	Picker *fs.DocumentRef `firestore:"picker"`
+
+	// SlateGame is a reference to the picked game in the slate.
+	SlateGame *fs.DocumentRef `firestore:"game"`
+
+	// FromSlateGame is a reference to the picked game before the change, if the pick was migrated from another slate. It is nil otherwise.
+	FromSlateGame *fs.DocumentRef `firestore:"from_game"`
+
+	// Pick is a reference to the pick that changed.
+	Pick *fs.DocumentRef `firestore:"pick"`
+
+	// Created is true if the pick did not exist before the change.
+	Created bool `firestore:"created"`
+
+	// From is the team picked before the change. It is nil if the pick was created or no team was picked.
+	From *fs.DocumentRef `firestore:"from"`
+
+	// To is the team picked after the change. It is nil if an unpicked superdog was picked before.
+	To *fs.DocumentRef `firestore:"to"`
+
+	// FromModelPrediction is the model prediction used to make the pick before the change, if any.
+	FromModelPrediction *fs.DocumentRef `firestore:"from_model_prediction"`
+
+	// ToModelPrediction is the model prediction used to make the pick after the change, if any.
+	ToModelPrediction *fs.DocumentRef `firestore:"to_model_prediction"`
+
+	// Actor is the Luke name of the authenticated picker who made the change.
+	Actor string `firestore:"actor"`
+
+	// Source is the program that made the change.
+	Source string `firestore:"source"`
+
+	// User is the user and host that ran the program that made the change.
+	User string `firestore:"user"`
+
+	// Override is the reason given for changing the pick after it locked. It is empty if the pick was not locked.
+	Override string `firestore:"override"`
+
+	// Timestamp is the time the change was written to Firestore.
+	Timestamp time.Time `firestore:"timestamp,serverTimestamp"`
+}
+
+// RefID returns the ID of the reference, or an empty string if the reference is nil.
+func RefID(ref *fs.DocumentRef) string {
+	if ref == nil {
+		return ""
+	}
+	return ref.ID
+}
+
+// String implements the Stringer interface.
+func (c PickChange) String() string {
+	from := RefID(c.From)
+	if c.Created {
+		from = "(new)"
+	}
+	return fmt.Sprintf("%s %s: %s -> %s (%s by %s)", RefID(c.Picker), RefID(c.SlateGame), from, RefID(c.To), c.Source, c.Actor)
+}
+
+// currentUser identifies who is running the program as user@host.
+func currentUser() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return name
+	}
+	return name + "@" + host
+}
+
+// NewPickChange records the change of a pick stored at ref from before to after by the authenticated picker by.
+// Before is nil if the pick is being created.
+func NewPickChange(before *Pick, after Pick, ref *fs.DocumentRef, source string, by Picker) PickChange {
+	c := PickChange{
+		Picker:            after.Picker,
+		SlateGame:         after.SlateGame,
+		Pick:              ref,
+		Created:           before == nil,
+		To:                after.PickedTeam,
+		ToModelPrediction: after.ModelPrediction,
+		Actor:             by.LukeName,
+		Source:            source,
+		User:              currentUser(),
+	}
+	if before != nil {
+		c.From = before.PickedTeam
+		c.FromModelPrediction = before.ModelPrediction
+		if before.SlateGame != nil && after.SlateGame != nil && before.SlateGame.Path != after.SlateGame.Path {
+			c.FromSlateGame = before.SlateGame
+		}
+	}
+	return c
+}
+
+// Changed reports whether the change made a new pick, moved the pick to another slate game, or changed the picked team or the model prediction behind it.
+func (c PickChange) Changed() bool {
+	return c.Created || c.FromSlateGame != nil || RefID(c.From) != RefID(c.To) || RefID(c.FromModelPrediction) != RefID(c.ToModelPrediction)
+}
+
+// RecordPickChanges adds pick changes to the week's pick change log as part of a transaction.
+// Changes that did not change anything are not recorded.
+func RecordPickChanges(t *fs.Transaction, week *fs.DocumentRef, changes []PickChange) error {
+	coll := week.Collection(PICK_CHANGES_COLLECTION)
+	for _, c := range changes {
+		if !c.Changed() {
+			continue
+		}
+		if err := t.Create(coll.NewDoc(), &c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPickChanges returns the changes made to picks in a week in the order they were made.
+// If picker is not nil, only changes to that picker's picks are returned.
+func GetPickChanges(ctx context.Context, week, picker *fs.DocumentRef) ([]PickChange, []*fs.DocumentRef, error) {
+	q := week.Collection(PICK_CHANGES_COLLECTION).Query
+	if picker != nil {
+		q = q.Where("picker", "==", picker)
+	}
+	snaps, err := q.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	// changes are never updated, so the documents' creation times are the times the changes were made
+	sort.SliceStable(snaps, func(i, j int) bool { return snaps[i].CreateTime.Before(snaps[j].CreateTime) })
+	changes := make([]PickChange, len(snaps))
+	refs := make([]*fs.DocumentRef, len(snaps))
+	for i, snap := range snaps {
+		if err := snap.DataTo(&changes[i]); err != nil {
+			return nil, nil, err
+		}
+		refs[i] = snap.Ref
+	}
+	return changes, refs, nil
+}
@@ -0,0 +1,56 @@
+package firestore
+
+import (
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestNewPickChange(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	alice := &fs.DocumentRef{ID: "alice"}
+	sg := &fs.DocumentRef{ID: "sg1", Path: "slates/old/games/sg1"}
+	migrated := &fs.DocumentRef{ID: "sg1", Path: "slates/new/games/sg1"}
+	ref := &fs.DocumentRef{ID: "pick1"}
+	line := &fs.DocumentRef{ID: "line-pred"}
+	sag := &fs.DocumentRef{ID: "sag-pred"}
+	commish := Picker{LukeName: "Commish", Role: CommissionerRole}
+
+	created := NewPickChange(nil, Pick{SlateGame: sg, PickedTeam: mich, Picker: alice, ModelPrediction: line}, ref, "test", commish)
+	if !created.Created || created.From != nil || created.To != mich || created.Pick != ref || created.Picker != alice || created.SlateGame != sg || created.Actor != "Commish" {
+		t.Errorf("unexpected new pick change %+v", created)
+	}
+	if !created.Changed() {
+		t.Error("expected new pick to be a change")
+	}
+
+	before := Pick{SlateGame: sg, PickedTeam: mich, Picker: alice, ModelPrediction: line}
+	tests := []struct {
+		name    string
+		after   Pick
+		changed bool
+	}{
+		{"same", Pick{SlateGame: sg, PickedTeam: &fs.DocumentRef{ID: "mich"}, Picker: alice, ModelPrediction: line}, false},
+		{"team", Pick{SlateGame: sg, PickedTeam: osu, Picker: alice, ModelPrediction: line}, true},
+		{"model", Pick{SlateGame: sg, PickedTeam: mich, Picker: alice, ModelPrediction: sag}, true},
+		{"manual", Pick{SlateGame: sg, PickedTeam: mich, Picker: alice}, true},
+		{"unpicked", Pick{SlateGame: sg, Picker: alice}, true},
+		{"migrated", Pick{SlateGame: migrated, PickedTeam: mich, Picker: alice, ModelPrediction: line}, true},
+	}
+	for _, test := range tests {
+		c := NewPickChange(&before, test.after, ref, "test", commish)
+		if c.Created {
+			t.Errorf("%s: expected update not to be a creation", test.name)
+		}
+		if c.From != mich || c.FromModelPrediction != line {
+			t.Errorf("%s: expected change from the previous pick, got %+v", test.name, c)
+		}
+		if (c.FromSlateGame != nil) != (test.name == "migrated") {
+			t.Errorf("%s: unexpected previous slate game %v", test.name, c.FromSlateGame)
+		}
+		if c.Changed() != test.changed {
+			t.Errorf("%s: expected changed %t, got %t", test.name, test.changed, c.Changed())
+		}
+	}
+}
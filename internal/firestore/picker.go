@@ -21,6 +21,12 @@ type Picker struct {
 
 	// Joined is a timestamp marking when the picker joined Pick 'Em.
 	Joined time.Time `firestore:"joined"`
+
+	// Role is what the picker is allowed to change. Pickers without a role are plain pickers.
+	Role Role `firestore:"role,omitempty"`
+
+	// TokenHashes are the hashes of the picker's API tokens (see HashToken). The tokens themselves are never stored.
+	TokenHashes []string `firestore:"token_hashes,omitempty"`
 }
 
 // UnmarshalText implements the TextUnmarshaler interface
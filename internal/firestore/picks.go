@@ -79,6 +79,24 @@ func GetPicks(ctx context.Context, weekRef, pickerRef *firestore.DocumentRef) (p
 	return
 }
 
+// GetWeekPicks gets all pickers' picks for a given week.
+func GetWeekPicks(ctx context.Context, weekRef *firestore.DocumentRef) (picks []Pick, pickRefs []*firestore.DocumentRef, err error) {
+	snaps, err := weekRef.Collection(PICKS_COLLECTION).Documents(ctx).GetAll()
+	if err != nil {
+		return
+	}
+	picks = make([]Pick, len(snaps))
+	pickRefs = make([]*firestore.DocumentRef, len(snaps))
+	for i, snap := range snaps {
+		if err = snap.DataTo(&picks[i]); err != nil {
+			return
+		}
+		pickRefs[i] = snap.Ref
+	}
+
+	return
+}
+
 // FillOut uses game and model performance information to fill out a pick
 func (p *Pick) FillOut(game Game, perf ModelPerformance, pred ModelPrediction, predRef *firestore.DocumentRef, spread int) {
 	dist := distuv.Normal{Mu: perf.Bias, Sigma: perf.StdDev}
@@ -311,56 +329,219 @@ func (p Pick) BuildSlateRows(ctx context.Context) ([][]string, error) {
 	return output, nil
 }
 
-// BuildSlateRow creates a row of strings for direct output to a slate spreadsheet.
-// TODO: still not printing DDs correctly.
-func (sg StreakPick) BuildSlateRows(ctx context.Context) ([][]string, error) {
-	tupleStrings := [...]string{
-		"BEAT THE STREAK!",
-		"DOUBLE DOWN!",
-		"TRIPLE DOWN!",
-		"QUADRUPLE DOWN!",
-		"QUINTUPLE DOWN!",
-		"SEXTUPLE DOWN!",
-		"SEPTUPLE DOWN!",
-		"OCTUPLE DOWN!",
-		"NONTUPLE DOWN!",
-		"DECTUPLE DOWN!",
-	}
-
-	// game, instruction, pick(s), total remaining spread, notes?, probability of beating the streak
-	output := make([][]string, 0)
-
-	if len(sg.PickedTeams) == 0 {
+// streakLabels label the streak rows of a slate by the number of teams picked in the week.
+var streakLabels = [...]string{
+	"BEAT THE STREAK!",
+	"DOUBLE DOWN!",
+	"TRIPLE DOWN!",
+	"QUADRUPLE DOWN!",
+	"QUINTUPLE DOWN!",
+	"SEXTUPLE DOWN!",
+	"SEPTUPLE DOWN!",
+	"OCTUPLE DOWN!",
+	"NONUPLE DOWN!",
+	"DECUPLE DOWN!",
+}
+
+// streakLabel returns the label of a week in which n teams are picked. Byes are labeled like single picks.
+func streakLabel(n int) string {
+	if n <= 1 {
+		return streakLabels[0]
+	}
+	if n > len(streakLabels) {
+		return fmt.Sprintf("%d-TUPLE DOWN!", n)
+	}
+	return streakLabels[n-1]
+}
+
+// StreakRow is a team picked in a week of Beat the Streak, with everything needed to print it on a slate.
+type StreakRow struct {
+	Team Team
+
+	// Opponent is the team's opponent this week. It is the zero Team if the team's game is not known.
+	Opponent Team
+
+	// Home and NeutralSite are where the team plays its game.
+	Home        bool
+	NeutralSite bool
+
+	// Venue is the name of the venue of the game, if known.
+	Venue string
+
+	// Predicted is true if Probability and Spread were found in the streak predictions.
+	Predicted bool
+
+	// Probability is the predicted probability the team wins.
+	Probability float64
+
+	// Spread is the predicted spread of the game (positive favoring the team).
+	Spread float64
+}
+
+// matchup describes the team's game from the team's point of view, like "@ Iowa (away, Kinnick Stadium)".
+func (r StreakRow) matchup() string {
+	if r.Opponent.School == "" {
+		return ""
+	}
+	vs, where := "@", "away"
+	switch {
+	case r.NeutralSite:
+		vs, where = "vs", "neutral site"
+	case r.Home:
+		vs, where = "vs", "home"
+	}
+	if r.Venue != "" {
+		where += ", " + r.Venue
+	}
+	return fmt.Sprintf("%s %s (%s)", vs, r.Opponent.School, where)
+}
+
+// StreakSlateRows lays out a week of Beat the Streak picks as slate rows, one row per team picked, or a single row for a bye.
+// Each row has the label of the week, the team's game, the team, its predicted spread, notes, and the probability that every pick of the week so far wins.
+// The first row's notes also give the predicted probability of beating the streak and the predicted spread of the remaining streak.
+func StreakSlateRows(rows []StreakRow, spread, probability float64) [][]string {
+	label := streakLabel(len(rows))
+	streakNote := fmt.Sprintf("Chance of beating the streak: %0.4f (remaining spread %+0.2f)", probability, spread)
+
+	if len(rows) == 0 {
+		return [][]string{{label, "", "BYE", "", streakNote, fmt.Sprintf("%0.4f", 1.)}}
+	}
+
+	output := make([][]string, len(rows))
+	survival := 1.
+	known := true
+	for i, r := range rows {
 		line := make([]string, 6)
-		line[0] = tupleStrings[0]
-		line[2] = "BYE"
-		line[3] = fmt.Sprintf("%0.12f", sg.PredictedSpread)
-		line[5] = fmt.Sprintf("%0.4f", sg.PredictedProbability)
-		output = append(output, line)
-	} else {
+		line[0] = label
+		line[1] = r.matchup()
+		line[2] = r.Team.Mascot
+
+		notes := make([]string, 0, 2)
+		if i == 0 {
+			notes = append(notes, streakNote)
+		}
+		if r.Predicted {
+			line[3] = fmt.Sprintf("%+0.2f", r.Spread)
+			notes = append(notes, fmt.Sprintf("Win probability: %0.4f", r.Probability))
+			survival *= r.Probability
+		} else {
+			known = false
+		}
+		line[4] = strings.Join(notes, "\n")
+		if known {
+			line[5] = fmt.Sprintf("%0.4f", survival)
+		}
+		output[i] = line
+	}
+	return output
+}
+
+// predictedStreakWeek returns the first week of the possible streak that starts with the given picks.
+// The bool is false if no possible streak starts with exactly those picks.
+func predictedStreakWeek(preds StreakPredictions, picks []*firestore.DocumentRef) (StreakWeek, bool) {
+	for _, pp := range preds.PossiblePicks {
+		if len(pp.Weeks) == 0 {
+			continue
+		}
+		week := pp.Weeks[0]
+		if len(week.Pick) != len(picks) || len(week.Probabilities) != len(picks) || len(week.Spreads) != len(picks) {
+			continue
+		}
+		ids := make(map[string]bool)
+		for _, ref := range week.Pick {
+			ids[ref.ID] = true
+		}
+		match := true
+		for _, ref := range picks {
+			if !ids[ref.ID] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return week, true
+		}
+	}
+	return StreakWeek{}, false
+}
+
+// BuildSlateRows creates rows of strings for direct output to a slate spreadsheet.
+// The games and predictions of the picks are looked up from the week of the streak predictions used to make the pick, if any.
+func (sg StreakPick) BuildSlateRows(ctx context.Context) ([][]string, error) {
+	rows := make([]StreakRow, len(sg.PickedTeams))
+	for i, teamRef := range sg.PickedTeams {
+		snap, err := teamRef.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get team %s: %w", teamRef.ID, err)
+		}
+		if err = snap.DataTo(&rows[i].Team); err != nil {
+			return nil, fmt.Errorf("unable to build team from data %+v: %w", snap, err)
+		}
+	}
+
+	if sg.StreakPredictions == nil || len(rows) == 0 {
+		return StreakSlateRows(rows, sg.PredictedSpread, sg.PredictedProbability), nil
+	}
+
+	snap, err := sg.StreakPredictions.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get streak predictions %s: %w", sg.StreakPredictions.ID, err)
+	}
+	var preds StreakPredictions
+	if err = snap.DataTo(&preds); err != nil {
+		return nil, fmt.Errorf("unable to build streak predictions from data %+v: %w", snap, err)
+	}
+	if week, ok := predictedStreakWeek(preds, sg.PickedTeams); ok {
 		for i, teamRef := range sg.PickedTeams {
-			var team Team
-			snap, err := teamRef.Get(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("unable to get team %s: %w", teamRef.ID, err)
+			for j, ref := range week.Pick {
+				if ref.ID == teamRef.ID {
+					rows[i].Predicted = true
+					rows[i].Probability = week.Probabilities[j]
+					rows[i].Spread = week.Spreads[j]
+				}
 			}
-			err = snap.DataTo(&team)
+		}
+	}
+
+	// streak predictions are stored under the week they predict
+	weekRef := sg.StreakPredictions.Parent.Parent
+	games, _, err := GetGames(ctx, weekRef)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get games of week %s: %w", weekRef.ID, err)
+	}
+	for i, teamRef := range sg.PickedTeams {
+		for _, game := range games {
+			opponent := game.AwayTeam
+			switch teamRef.ID {
+			case game.HomeTeam.ID:
+				rows[i].Home = true
+			case game.AwayTeam.ID:
+				opponent = game.HomeTeam
+			default:
+				continue
+			}
+			rows[i].NeutralSite = game.NeutralSite
+			snap, err := opponent.Get(ctx)
 			if err != nil {
+				return nil, fmt.Errorf("unable to get team %s: %w", opponent.ID, err)
+			}
+			if err = snap.DataTo(&rows[i].Opponent); err != nil {
 				return nil, fmt.Errorf("unable to build team from data %+v: %w", snap, err)
 			}
-
-			line := make([]string, 6)
-			if i > len(tupleStrings) {
-				line[0] = fmt.Sprintf("%d-TUPLE DOWN!", i+1)
-			} else {
-				line[0] = tupleStrings[i]
+			if game.Venue != nil {
+				snap, err := game.Venue.Get(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("unable to get venue %s: %w", game.Venue.ID, err)
+				}
+				var venue Venue
+				if err = snap.DataTo(&venue); err != nil {
+					return nil, fmt.Errorf("unable to build venue from data %+v: %w", snap, err)
+				}
+				rows[i].Venue = venue.Name
 			}
-			line[2] = team.Mascot
-			line[3] = fmt.Sprintf("%0.12f", sg.PredictedSpread)
-			line[5] = fmt.Sprintf("%0.4f", sg.PredictedProbability)
-			output = append(output, line)
+			break
 		}
 	}
 
-	return output, nil
+	return StreakSlateRows(rows, sg.PredictedSpread, sg.PredictedProbability), nil
 }
@@ -0,0 +1,114 @@
+package firestore
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares rows to the CSV golden file testdata/<name>.golden, rewriting the file instead if -update is set.
+func checkGolden(t *testing.T, name string, rows [][]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("%s: rows do not match golden file\ngot:\n%s\nwant:\n%s", name, buf.String(), want)
+	}
+}
+
+func TestStreakSlateRows(t *testing.T) {
+	mich := Team{School: "Michigan", Mascot: "Wolverines"}
+	osu := Team{School: "Ohio State", Mascot: "Buckeyes"}
+	iowa := Team{School: "Iowa", Mascot: "Hawkeyes"}
+	msu := Team{School: "Michigan State", Mascot: "Spartans"}
+	ill := Team{School: "Illinois", Mascot: "Fighting Illini"}
+	nw := Team{School: "Northwestern", Mascot: "Wildcats"}
+	wisc := Team{School: "Wisconsin", Mascot: "Badgers"}
+
+	tests := []struct {
+		name        string
+		rows        []StreakRow
+		spread      float64
+		probability float64
+	}{
+		{"streak_bye", nil, 123.456, 0.0123},
+		{"streak_single", []StreakRow{
+			{Team: mich, Opponent: msu, Home: true, Venue: "Michigan Stadium", Predicted: true, Probability: .85, Spread: 12.5},
+		}, 98.7, 0.0456},
+		{"streak_double", []StreakRow{
+			{Team: osu, Opponent: iowa, Venue: "Kinnick Stadium", Predicted: true, Probability: .7, Spread: 6.25},
+			{Team: wisc, Opponent: nw, NeutralSite: true, Venue: "Wrigley Field", Predicted: true, Probability: .9, Spread: 14},
+		}, 87.65, 0.0321},
+		{"streak_quintuple", []StreakRow{
+			{Team: mich, Opponent: ill, Home: true, Predicted: true, Probability: .95, Spread: 21},
+			{Team: osu, Opponent: nw, Predicted: true, Probability: .9, Spread: 17.5},
+			{Team: iowa, Opponent: msu, Home: true, Predicted: true, Probability: .6, Spread: 3},
+			{Team: wisc, Predicted: true, Probability: .8, Spread: 10},
+			{Team: nw, Opponent: osu, Home: true},
+		}, 45.5, 0.0011},
+	}
+	for _, test := range tests {
+		checkGolden(t, test.name, StreakSlateRows(test.rows, test.spread, test.probability))
+	}
+}
+
+func TestStreakLabel(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "BEAT THE STREAK!"},
+		{1, "BEAT THE STREAK!"},
+		{2, "DOUBLE DOWN!"},
+		{10, "DECUPLE DOWN!"},
+		{11, "11-TUPLE DOWN!"},
+	}
+	for _, test := range tests {
+		if got := streakLabel(test.n); got != test.want {
+			t.Errorf("streakLabel(%d): expected %q, got %q", test.n, test.want, got)
+		}
+	}
+}
+
+func TestPredictedStreakWeek(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	iowa := &fs.DocumentRef{ID: "iowa"}
+	preds := StreakPredictions{PossiblePicks: []StreakPrediction{
+		{Weeks: []StreakWeek{{Pick: []*fs.DocumentRef{mich}, Probabilities: []float64{.9}, Spreads: []float64{14}}}},
+		{Weeks: []StreakWeek{{Pick: []*fs.DocumentRef{osu, iowa}, Probabilities: []float64{.8, .6}, Spreads: []float64{10, 3}}}},
+		{Weeks: []StreakWeek{{Pick: []*fs.DocumentRef{}, Probabilities: []float64{1}, Spreads: []float64{0}}}},
+	}}
+
+	week, ok := predictedStreakWeek(preds, []*fs.DocumentRef{iowa, osu})
+	if !ok || week.Probabilities[1] != .6 {
+		t.Errorf("expected to find the double down in either order, got %v, %t", week, ok)
+	}
+	if _, ok := predictedStreakWeek(preds, []*fs.DocumentRef{mich, osu}); ok {
+		t.Error("expected no possible streak starting with Michigan and Ohio State")
+	}
+	if _, ok := predictedStreakWeek(preds, nil); ok {
+		t.Error("expected bye with mismatched probabilities to be skipped")
+	}
+}
@@ -0,0 +1,175 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RANKINGS_COLLECTION is the path to the poll rankings collection under a week in Firestore.
+const RANKINGS_COLLECTION = "rankings"
+
+// DefaultPoll is the poll used when none is specified.
+const DefaultPoll = "AP Top 25"
+
+// Ranking is a poll's ranking of teams going into a week.
+// Rankings are stored under the week they were released before, so the first week's rankings are the preseason polls.
+type Ranking struct {
+	// Poll is the name of the poll, like "AP Top 25" or "Coaches Poll".
+	Poll string `firestore:"poll"`
+
+	// Teams are the ranked teams in order of rank.
+	Teams []RankedTeam `firestore:"teams"`
+
+	// Fetched is when the ranking was last fetched.
+	Fetched time.Time `firestore:"fetched"`
+}
+
+// RankedTeam is a team's place in a poll.
+type RankedTeam struct {
+	Rank            int             `firestore:"rank"`
+	Team            *fs.DocumentRef `firestore:"team"`
+	Points          int             `firestore:"points"`
+	FirstPlaceVotes int             `firestore:"first_place_votes"`
+}
+
+// String implements the Stringer interface.
+func (r Ranking) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s (fetched %s)", r.Poll, r.Fetched.Format(time.UnixDate)))
+	for _, t := range r.Teams {
+		sb.WriteString(fmt.Sprintf("\n#%d %s (%d points, %d first place votes)", t.Rank, t.Team.ID, t.Points, t.FirstPlaceVotes))
+	}
+	return sb.String()
+}
+
+// Ranks returns the ranks of the ranked teams keyed by team ID.
+func (r Ranking) Ranks() map[string]int {
+	ranks := make(map[string]int)
+	for _, t := range r.Teams {
+		ranks[t.Team.ID] = t.Rank
+	}
+	return ranks
+}
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// PollID converts a poll name into the ID of its ranking document (e.g., "AP Top 25" becomes "ap-top-25").
+func PollID(poll string) string {
+	return strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(poll), "-"), "-")
+}
+
+// NoRankingError is returned when a poll has no ranking stored for a week.
+type NoRankingError struct {
+	Poll string
+	Week string
+}
+
+func (e NoRankingError) Error() string {
+	return fmt.Sprintf("no %s ranking stored for week %s", e.Poll, e.Week)
+}
+
+// GetRankings returns all the poll rankings stored for a week.
+func GetRankings(ctx context.Context, week *fs.DocumentRef) ([]Ranking, []*fs.DocumentRef, error) {
+	snaps, err := week.Collection(RANKINGS_COLLECTION).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting ranking documents for week %s: %w", week.ID, err)
+	}
+	rankings := make([]Ranking, len(snaps))
+	refs := make([]*fs.DocumentRef, len(snaps))
+	for i, ss := range snaps {
+		if err := ss.DataTo(&rankings[i]); err != nil {
+			return nil, nil, fmt.Errorf("error getting ranking snapshot data %s: %w", ss.Ref.ID, err)
+		}
+		refs[i] = ss.Ref
+	}
+	return rankings, refs, nil
+}
+
+// GetRanking returns the ranking of the named poll stored for a week. If there is none, the error is a NoRankingError.
+func GetRanking(ctx context.Context, week *fs.DocumentRef, poll string) (Ranking, *fs.DocumentRef, error) {
+	var r Ranking
+	ref := week.Collection(RANKINGS_COLLECTION).Doc(PollID(poll))
+	snap, err := ref.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return r, nil, NoRankingError{Poll: poll, Week: week.ID}
+	}
+	if err != nil {
+		return r, nil, fmt.Errorf("error getting %s ranking for week %s: %w", poll, week.ID, err)
+	}
+	if err := snap.DataTo(&r); err != nil {
+		return r, nil, fmt.Errorf("error getting %s ranking data for week %s: %w", poll, week.ID, err)
+	}
+	return r, ref, nil
+}
+
+// GetLatestRanking returns the most recent ranking of the named poll stored for a week at or before the given week number,
+// since polls are not always stored every week. If there is none, the error is a NoRankingError.
+func GetLatestRanking(ctx context.Context, season *fs.DocumentRef, week int, poll string) (Ranking, *fs.DocumentRef, error) {
+	for w := week; w >= 0; w-- {
+		_, weekRef, err := GetWeek(ctx, season, w)
+		if _, ok := err.(NoWeekError); ok {
+			continue
+		}
+		if err != nil {
+			return Ranking{}, nil, err
+		}
+		r, ref, err := GetRanking(ctx, weekRef, poll)
+		if _, ok := err.(NoRankingError); ok {
+			continue
+		}
+		return r, ref, err
+	}
+	return Ranking{}, nil, NoRankingError{Poll: poll, Week: fmt.Sprintf("%d or earlier", week)}
+}
+
+// RankMismatch is a team whose rank on a slate does not match its rank in a poll.
+type RankMismatch struct {
+	// Row is the row of the slate in which the team appears.
+	Row int
+
+	// Game is the game in which the team appears.
+	Game *fs.DocumentRef
+
+	// Team is the team.
+	Team *fs.DocumentRef
+
+	// SlateRank is the rank of the team on the slate, or 0 if unranked.
+	SlateRank int
+
+	// PollRank is the rank of the team in the poll, or 0 if unranked.
+	PollRank int
+}
+
+// String implements the Stringer interface.
+func (m RankMismatch) String() string {
+	return fmt.Sprintf("row %d: %s is #%d on the slate but #%d in the poll", m.Row, m.Team.ID, m.SlateRank, m.PollRank)
+}
+
+// CompareSlateRanks compares the ranks of teams on a slate with the ranks of a poll, keyed by team ID.
+// Games are looked up by ID to find the true home and away teams. Superdog games, which do not show ranks, are skipped.
+func CompareSlateRanks(sgames []SlateGame, games map[string]Game, ranks map[string]int) ([]RankMismatch, error) {
+	mismatches := make([]RankMismatch, 0)
+	for _, sg := range sgames {
+		if sg.Superdog {
+			continue
+		}
+		game, ok := games[sg.Game.ID]
+		if !ok {
+			return nil, fmt.Errorf("game %s on slate row %d not found", sg.Game.ID, sg.Row)
+		}
+		if r := ranks[game.HomeTeam.ID]; r != sg.HomeRank {
+			mismatches = append(mismatches, RankMismatch{Row: sg.Row, Game: sg.Game, Team: game.HomeTeam, SlateRank: sg.HomeRank, PollRank: r})
+		}
+		if r := ranks[game.AwayTeam.ID]; r != sg.AwayRank {
+			mismatches = append(mismatches, RankMismatch{Row: sg.Row, Game: sg.Game, Team: game.AwayTeam, SlateRank: sg.AwayRank, PollRank: r})
+		}
+	}
+	return mismatches, nil
+}
@@ -0,0 +1,59 @@
+package firestore
+
+import (
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestPollID(t *testing.T) {
+	tests := map[string]string{
+		"AP Top 25":                  "ap-top-25",
+		"Coaches Poll":               "coaches-poll",
+		"Playoff Committee Rankings": "playoff-committee-rankings",
+		" FCS Coaches' Poll ":        "fcs-coaches-poll",
+	}
+	for poll, want := range tests {
+		if got := PollID(poll); got != want {
+			t.Errorf("PollID(%q): expected %q, got %q", poll, want, got)
+		}
+	}
+}
+
+func TestCompareSlateRanks(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	ill := &fs.DocumentRef{ID: "ill"}
+	pur := &fs.DocumentRef{ID: "pur"}
+	g1 := &fs.DocumentRef{ID: "g1"}
+	g2 := &fs.DocumentRef{ID: "g2"}
+
+	games := map[string]Game{
+		"g1": {HomeTeam: mich, AwayTeam: osu},
+		"g2": {HomeTeam: pur, AwayTeam: ill},
+	}
+	ranks := map[string]int{"mich": 2, "osu": 5, "pur": 25}
+	sgames := []SlateGame{
+		{Row: 1, Game: g1, HomeRank: 2, AwayRank: 4},
+		{Row: 2, Game: g2},
+		{Row: 4, Game: g2, Superdog: true},
+	}
+
+	mismatches, err := CompareSlateRanks(sgames, games, ranks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %v", mismatches)
+	}
+	if m := mismatches[0]; m.Team.ID != "osu" || m.SlateRank != 4 || m.PollRank != 5 || m.Row != 1 {
+		t.Errorf("expected osu #4 on slate but #5 in poll, got %s", m)
+	}
+	if m := mismatches[1]; m.Team.ID != "pur" || m.SlateRank != 0 || m.PollRank != 25 || m.Row != 2 {
+		t.Errorf("expected pur unranked on slate but #25 in poll, got %s", m)
+	}
+
+	if _, err := CompareSlateRanks([]SlateGame{{Row: 1, Game: &fs.DocumentRef{ID: "g3"}}}, games, ranks); err == nil {
+		t.Error("expected error for missing game")
+	}
+}
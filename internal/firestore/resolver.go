@@ -0,0 +1,197 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+)
+
+// TeamLookup looks up teams by name.
+type TeamLookup interface {
+	// Lookup returns the team known by the given name, or false if there is no such team.
+	Lookup(name string, nameType NameType) (*firestore.DocumentRef, bool)
+}
+
+// Lookup implements TeamLookup. Names must match exactly and the name type is ignored.
+func (t TeamRefsByName) Lookup(name string, nameType NameType) (*firestore.DocumentRef, bool) {
+	ref, ok := t[name]
+	return ref, ok
+}
+
+// TeamResolver looks up the teams of a season by any of the names they are known by.
+// Names are first matched exactly against names of the requested type, then against every name of every team after normalization (see NormalizeName),
+// so "Ohio St." finds the team with the short name "Ohio State".
+// Names that still do not match can be ranked against all teams with Candidates, and names confirmed by a human can be learned with Learn or AddAlias.
+type TeamResolver struct {
+	teams []Team
+	refs  []*firestore.DocumentRef
+
+	exact      map[NameType]TeamRefsByName
+	normalized map[string][]int
+}
+
+// NewTeamResolver indexes the names of teams. Names of the same type that are shared by more than one team are reported as an error.
+func NewTeamResolver(teams []Team, refs []*firestore.DocumentRef) (*TeamResolver, *DuplicateTeamNameError) {
+	r := &TeamResolver{
+		teams: make([]Team, len(teams)),
+		refs:  make([]*firestore.DocumentRef, len(refs)),
+	}
+	copy(r.teams, teams)
+	copy(r.refs, refs)
+	if err := r.index(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetTeamResolver gets the teams of a season from Firestore and indexes their names.
+func GetTeamResolver(ctx context.Context, season *firestore.DocumentRef) (*TeamResolver, error) {
+	teams, refs, err := GetTeams(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	r, dupErr := NewTeamResolver(teams, refs)
+	if dupErr != nil {
+		return nil, dupErr
+	}
+	return r, nil
+}
+
+func (r *TeamResolver) index() *DuplicateTeamNameError {
+	short, dupErr := NewTeamRefsByShortName(r.teams, r.refs)
+	if dupErr != nil {
+		return dupErr
+	}
+	other, dupErr := NewTeamRefsByOtherName(r.teams, r.refs)
+	if dupErr != nil {
+		return dupErr
+	}
+	r.exact = map[NameType]TeamRefsByName{ShortName: short, OtherName: other}
+
+	r.normalized = make(map[string][]int)
+	for i, t := range r.teams {
+		seen := make(map[string]struct{})
+		for _, n := range t.Names() {
+			key := NormalizeName(n)
+			if _, ok := seen[key]; ok || key == "" {
+				continue
+			}
+			seen[key] = struct{}{}
+			r.normalized[key] = append(r.normalized[key], i)
+		}
+	}
+	return nil
+}
+
+// Teams returns the indexed teams and their references, including any names learned since the resolver was made.
+func (r *TeamResolver) Teams() ([]Team, []*firestore.DocumentRef) {
+	return r.teams, r.refs
+}
+
+// Team returns the team with the given reference.
+func (r *TeamResolver) Team(ref *firestore.DocumentRef) (Team, bool) {
+	if i := r.indexOf(ref); i >= 0 {
+		return r.teams[i], true
+	}
+	return Team{}, false
+}
+
+func (r *TeamResolver) indexOf(ref *firestore.DocumentRef) int {
+	for i, x := range r.refs {
+		if x.ID == ref.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Lookup implements TeamLookup.
+// A name that does not exactly match a name of the given type matches a team if it normalizes to one of the names of exactly one team.
+func (r *TeamResolver) Lookup(name string, nameType NameType) (*firestore.DocumentRef, bool) {
+	if ref, ok := r.exact[nameType][name]; ok {
+		return ref, true
+	}
+	if idx := r.normalized[NormalizeName(name)]; len(idx) == 1 {
+		return r.refs[idx[0]], true
+	}
+	return nil, false
+}
+
+// suggestionThreshold is the minimum similarity of a name for it to be suggested when a lookup fails.
+const suggestionThreshold = .5
+
+// Resolve looks up a team by name. If no single team is known by the name, the error wraps a NameNotFoundError
+// and suggests the most similar name, if any is similar enough.
+func (r *TeamResolver) Resolve(name string, nameType NameType) (*firestore.DocumentRef, error) {
+	if ref, ok := r.Lookup(name, nameType); ok {
+		return ref, nil
+	}
+	err := NameNotFoundError{Name: name, NameType: nameType}
+	if c := r.Candidates(name, 1); len(c) > 0 && c[0].Score >= suggestionThreshold {
+		return nil, fmt.Errorf("%w: did you mean '%s' (%s)?", err, c[0].MatchedName, c[0].Ref.ID)
+	}
+	return nil, err
+}
+
+// Candidates ranks the teams by how similar their names are to the given name and returns the best n. If n is not positive, all teams are returned.
+func (r *TeamResolver) Candidates(name string, n int) []NameCandidate {
+	candidates := RankNameCandidates(r.teams, r.refs, name)
+	if n > 0 && n < len(candidates) {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// Learn adds a name to a team for the life of the resolver. Nothing is written to Firestore.
+func (r *TeamResolver) Learn(ref *firestore.DocumentRef, name string, nameType NameType) error {
+	i := r.indexOf(ref)
+	if i < 0 {
+		return fmt.Errorf("team %s not found", ref.ID)
+	}
+	t := r.teams[i]
+	// Copy the names so that slices shared with the caller are not modified.
+	switch nameType {
+	case ShortName:
+		t.ShortNames = append(append([]string{}, t.ShortNames...), name)
+	case OtherName:
+		t.OtherNames = append(append([]string{}, t.OtherNames...), name)
+	default:
+		return fmt.Errorf("name type not recognized")
+	}
+
+	old := r.teams[i]
+	r.teams[i] = t
+	if err := r.index(); err != nil {
+		r.teams[i] = old
+		r.index()
+		return err
+	}
+	return nil
+}
+
+// AddAlias adds a name to a team in Firestore and learns it.
+func (r *TeamResolver) AddAlias(ctx context.Context, ref *firestore.DocumentRef, name string, nameType NameType) error {
+	if _, ok := r.Team(ref); !ok {
+		return fmt.Errorf("team %s not found", ref.ID)
+	}
+	if err := AddTeamAlias(ctx, ref, name, nameType); err != nil {
+		return err
+	}
+	return r.Learn(ref, name, nameType)
+}
+
+// AddTeamAlias adds a name to the short or other names of a team in Firestore, leaving the team's other names as they are.
+func AddTeamAlias(ctx context.Context, ref *firestore.DocumentRef, name string, nameType NameType) error {
+	var path string
+	switch nameType {
+	case ShortName:
+		path = "short_names"
+	case OtherName:
+		path = "other_names"
+	default:
+		return fmt.Errorf("name type not recognized")
+	}
+	_, err := ref.Update(ctx, []firestore.Update{{Path: path, Value: firestore.ArrayUnion(name)}})
+	return err
+}
@@ -0,0 +1,93 @@
+package firestore
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func testResolver(t *testing.T) *TeamResolver {
+	teams := []Team{
+		{School: "Ohio State", ShortNames: []string{"OSU"}, OtherNames: []string{"Ohio St"}},
+		{School: "Miami", ShortNames: []string{"MIAMI FL"}, OtherNames: []string{"Miami-Florida"}},
+		{School: "Miami", ShortNames: []string{"MIAMI OH"}, OtherNames: []string{"Miami-Ohio"}},
+	}
+	refs := []*fs.DocumentRef{{ID: "osu"}, {ID: "mia"}, {ID: "moh"}}
+	r, err := NewTeamResolver(teams, refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestTeamResolverLookup(t *testing.T) {
+	r := testResolver(t)
+
+	tests := []struct {
+		name     string
+		nameType NameType
+		want     string
+	}{
+		{"OSU", ShortName, "osu"},
+		{"Ohio St", OtherName, "osu"},
+		{"Ohio St.", ShortName, "osu"},
+		{"ohio state", OtherName, "osu"},
+		{"Miami (OH)", ShortName, "moh"},
+		{"Miami", ShortName, ""},
+		{"Oregon", ShortName, ""},
+	}
+	for _, test := range tests {
+		ref, ok := r.Lookup(test.name, test.nameType)
+		if test.want == "" {
+			if ok {
+				t.Errorf("Lookup(%q) = %s, expected not found", test.name, ref.ID)
+			}
+			continue
+		}
+		if !ok || ref.ID != test.want {
+			t.Errorf("Lookup(%q) = %v, %t, want %s", test.name, ref, ok, test.want)
+		}
+	}
+}
+
+func TestTeamResolverResolve(t *testing.T) {
+	r := testResolver(t)
+	_, err := r.Resolve("Ohoi State", ShortName)
+	var nnf NameNotFoundError
+	if !errors.As(err, &nnf) || nnf.Name != "Ohoi State" {
+		t.Fatalf("expected NameNotFoundError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "osu") {
+		t.Errorf("expected suggestion of osu, got %v", err)
+	}
+}
+
+func TestTeamResolverLearn(t *testing.T) {
+	r := testResolver(t)
+	mia := &fs.DocumentRef{ID: "mia"}
+	if err := r.Learn(mia, "The U", ShortName); err != nil {
+		t.Fatal(err)
+	}
+	if ref, ok := r.Lookup("The U", ShortName); !ok || ref.ID != "mia" {
+		t.Errorf("expected learned name to be found")
+	}
+	if err := r.Learn(&fs.DocumentRef{ID: "moh"}, "The U", ShortName); err == nil {
+		t.Errorf("expected learning a duplicate name to fail")
+	}
+	if ref, ok := r.Lookup("The U", ShortName); !ok || ref.ID != "mia" {
+		t.Errorf("expected failed learn to leave names unchanged")
+	}
+	if err := r.Learn(&fs.DocumentRef{ID: "nobody"}, "Nobody", ShortName); err == nil {
+		t.Errorf("expected learning a name for an unknown team to fail")
+	}
+}
+
+func TestNewTeamResolverDuplicates(t *testing.T) {
+	teams := []Team{{ShortNames: []string{"A"}}, {ShortNames: []string{"A"}}}
+	refs := []*fs.DocumentRef{{ID: "a"}, {ID: "b"}}
+	if _, err := NewTeamResolver(teams, refs); err == nil || err.Name != "A" {
+		t.Errorf("expected duplicate name A, got %v", err)
+	}
+}
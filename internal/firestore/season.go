@@ -34,6 +34,9 @@ type Season struct {
 	//   2: the number of double-down pick weeks
 	//   ...
 	StreakPickTypes []int `firestore:"streak_pick_types"`
+
+	// PickLock is when picks lock. By default, picks lock when their games kick off.
+	PickLock PickLock `firestore:"pick_lock"`
 }
 
 // GetSeason gets the season defined by `year`. If `year<0`, the most recent season (by `start_time`) is returned.
@@ -61,6 +61,14 @@ type SlateGame struct {
 	NoisySpread int `firestore:"noisy_spread"`
 }
 
+// Underdog returns the team that is not favored in the slate game, which is played as game.
+func (g SlateGame) Underdog(game Game) *fs.DocumentRef {
+	if g.HomeFavored {
+		return game.AwayTeam
+	}
+	return game.HomeTeam
+}
+
 // String implements the Stringer interface.
 func (g SlateGame) String() string {
 	if g.Superdog {
@@ -91,6 +99,7 @@ func (e NoSlateError) Error() string {
 	return string(e)
 }
 
+// GetSlateGames returns the games of the most recently parsed slate for the given week.
 func GetSlateGames(ctx context.Context, weekRef *fs.DocumentRef) (sgs []SlateGame, refs []*fs.DocumentRef, err error) {
 	var snaps []*fs.DocumentSnapshot
 	snaps, err = weekRef.Collection(SLATES_COLLECTION).OrderBy("parsed", fs.Desc).Limit(1).Documents(ctx).GetAll()
@@ -102,7 +111,13 @@ func GetSlateGames(ctx context.Context, weekRef *fs.DocumentRef) (sgs []SlateGam
 		return
 	}
 
-	snaps, err = snaps[0].Ref.Collection(SLATE_GAMES_COLLECTION).Documents(ctx).GetAll()
+	return GetSlateGamesFromSlate(ctx, snaps[0].Ref)
+}
+
+// GetSlateGamesFromSlate returns the games of the given slate.
+func GetSlateGamesFromSlate(ctx context.Context, slateRef *fs.DocumentRef) (sgs []SlateGame, refs []*fs.DocumentRef, err error) {
+	var snaps []*fs.DocumentSnapshot
+	snaps, err = slateRef.Collection(SLATE_GAMES_COLLECTION).Documents(ctx).GetAll()
 	if err != nil {
 		return
 	}
@@ -120,3 +135,20 @@ func GetSlateGames(ctx context.Context, weekRef *fs.DocumentRef) (sgs []SlateGam
 	}
 	return
 }
+
+// GetSlates returns all the slates parsed for the given week, most recently parsed first.
+func GetSlates(ctx context.Context, weekRef *fs.DocumentRef) ([]Slate, []*fs.DocumentRef, error) {
+	snaps, err := weekRef.Collection(SLATES_COLLECTION).OrderBy("parsed", fs.Desc).Documents(ctx).GetAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	slates := make([]Slate, len(snaps))
+	refs := make([]*fs.DocumentRef, len(snaps))
+	for i, snap := range snaps {
+		if err := snap.DataTo(&slates[i]); err != nil {
+			return nil, nil, err
+		}
+		refs[i] = snap.Ref
+	}
+	return slates, refs, nil
+}
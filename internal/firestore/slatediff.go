@@ -0,0 +1,232 @@
+package firestore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// SlateGameChange pairs a game in an old slate with the same game in a new slate.
+// Old is nil if the game was added to the new slate, and New is nil if the game was removed from the old slate.
+type SlateGameChange struct {
+	Old    *SlateGame
+	OldRef *fs.DocumentRef
+	New    *SlateGame
+	NewRef *fs.DocumentRef
+
+	// Fields are the names of the fields that differ between the old and new games.
+	Fields []string
+}
+
+// String implements the Stringer interface.
+func (c SlateGameChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("added: %s", c.New)
+	case c.New == nil:
+		return fmt.Sprintf("removed: %s", c.Old)
+	case len(c.Fields) == 0:
+		return fmt.Sprintf("unchanged: %s", c.New)
+	}
+	diffs := make([]string, len(c.Fields))
+	for i, f := range c.Fields {
+		diffs[i] = fmt.Sprintf("%s %v -> %v", f, slateGameField(*c.Old, f), slateGameField(*c.New, f))
+	}
+	return fmt.Sprintf("changed: game %s: %s", c.New.Game.ID, strings.Join(diffs, ", "))
+}
+
+// SlateDiff describes the differences between two slates for the same week.
+type SlateDiff struct {
+	Added     []SlateGameChange
+	Removed   []SlateGameChange
+	Changed   []SlateGameChange
+	Unchanged []SlateGameChange
+}
+
+// Empty returns true if the slates contain the same games with the same terms.
+func (d SlateDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String implements the Stringer interface.
+func (d SlateDiff) String() string {
+	if d.Empty() {
+		return "no changes"
+	}
+	var sb strings.Builder
+	for _, cs := range [][]SlateGameChange{d.Added, d.Removed, d.Changed} {
+		for _, c := range cs {
+			sb.WriteString(c.String())
+			sb.WriteString("\n")
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Matched returns a lookup from the path of each old slate game to the change pairing it with the same game in the new slate.
+// Removed games are not included.
+func (d SlateDiff) Matched() map[string]SlateGameChange {
+	m := make(map[string]SlateGameChange)
+	for _, cs := range [][]SlateGameChange{d.Changed, d.Unchanged} {
+		for _, c := range cs {
+			m[c.OldRef.Path] = c
+		}
+	}
+	return m
+}
+
+// slateGameFields are the fields of SlateGame that change the terms of a pick.
+// Row is not included because moving a game around the slate does not change how it is picked.
+var slateGameFields = []string{"Superdog", "GOTW", "Value", "HomeFavored", "NoisySpread", "HomeRank", "AwayRank", "HomeDisagreement", "NeutralDisagreement"}
+
+func slateGameField(g SlateGame, field string) interface{} {
+	switch field {
+	case "Superdog":
+		return g.Superdog
+	case "GOTW":
+		return g.GOTW
+	case "Value":
+		return g.Value
+	case "HomeFavored":
+		return g.HomeFavored
+	case "NoisySpread":
+		return g.NoisySpread
+	case "HomeRank":
+		return g.HomeRank
+	case "AwayRank":
+		return g.AwayRank
+	case "HomeDisagreement":
+		return g.HomeDisagreement
+	case "NeutralDisagreement":
+		return g.NeutralDisagreement
+	}
+	panic(fmt.Errorf("unknown SlateGame field %s", field))
+}
+
+// DiffSlateGames compares the games of an old slate with the games of a new slate.
+// Games are matched by the game they refer to. Because a game can appear in a slate more than once (for instance, as both a straight pick and a superdog),
+// games with the same superdog flag are matched first, in row order, before matching any that remain.
+func DiffSlateGames(oldGames []SlateGame, oldRefs []*fs.DocumentRef, newGames []SlateGame, newRefs []*fs.DocumentRef) SlateDiff {
+	byGame := func(games []SlateGame) map[string][]int {
+		m := make(map[string][]int)
+		for i, g := range games {
+			m[g.Game.ID] = append(m[g.Game.ID], i)
+		}
+		for _, idx := range m {
+			sort.SliceStable(idx, func(i, j int) bool { return games[idx[i]].Row < games[idx[j]].Row })
+		}
+		return m
+	}
+	oldByGame := byGame(oldGames)
+	newByGame := byGame(newGames)
+
+	var diff SlateDiff
+	oldMatched := make([]bool, len(oldGames))
+	newMatched := make([]bool, len(newGames))
+
+	match := func(io, in int) {
+		oldMatched[io] = true
+		newMatched[in] = true
+		c := SlateGameChange{Old: &oldGames[io], OldRef: oldRefs[io], New: &newGames[in], NewRef: newRefs[in]}
+		for _, f := range slateGameFields {
+			if slateGameField(oldGames[io], f) != slateGameField(newGames[in], f) {
+				c.Fields = append(c.Fields, f)
+			}
+		}
+		if len(c.Fields) == 0 {
+			diff.Unchanged = append(diff.Unchanged, c)
+		} else {
+			diff.Changed = append(diff.Changed, c)
+		}
+	}
+
+	for _, sameFlag := range []bool{true, false} {
+		for id, oldIdx := range oldByGame {
+			for _, io := range oldIdx {
+				if oldMatched[io] {
+					continue
+				}
+				for _, in := range newByGame[id] {
+					if newMatched[in] {
+						continue
+					}
+					if sameFlag && oldGames[io].Superdog != newGames[in].Superdog {
+						continue
+					}
+					match(io, in)
+					break
+				}
+			}
+		}
+	}
+
+	for i := range oldGames {
+		if !oldMatched[i] {
+			diff.Removed = append(diff.Removed, SlateGameChange{Old: &oldGames[i], OldRef: oldRefs[i]})
+		}
+	}
+	for i := range newGames {
+		if !newMatched[i] {
+			diff.Added = append(diff.Added, SlateGameChange{New: &newGames[i], NewRef: newRefs[i]})
+		}
+	}
+
+	// Map iteration is random, so sort for reproducible output.
+	for _, cs := range [][]SlateGameChange{diff.Changed, diff.Unchanged, diff.Removed} {
+		sort.SliceStable(cs, func(i, j int) bool { return cs[i].Old.Row < cs[j].Old.Row })
+	}
+	sort.SliceStable(diff.Added, func(i, j int) bool { return diff.Added[i].New.Row < diff.Added[j].New.Row })
+
+	return diff
+}
+
+// StalePick is a pick that refers to a game that is not in the most recent slate.
+type StalePick struct {
+	Pick Pick
+	Ref  *fs.DocumentRef
+
+	// Change pairs the pick's game with the same game in the most recent slate.
+	// If the game was removed from the slate, Change.New is nil and the pick cannot be migrated.
+	Change SlateGameChange
+}
+
+// Migratable returns true if the pick's game is still in the most recent slate.
+func (s StalePick) Migratable() bool {
+	return s.Change.New != nil
+}
+
+// FindStalePicks finds picks that refer to games in slates other than the new slate of the diff.
+// Picks that refer to games in the new slate are not stale and are not returned.
+// Picks that refer to games in slates other than the old slate of the diff are reported as removed, with only Change.OldRef set.
+func FindStalePicks(picks []Pick, pickRefs []*fs.DocumentRef, diff SlateDiff) []StalePick {
+	current := make(map[string]struct{})
+	for _, cs := range [][]SlateGameChange{diff.Added, diff.Changed, diff.Unchanged} {
+		for _, c := range cs {
+			current[c.NewRef.Path] = struct{}{}
+		}
+	}
+	matched := diff.Matched()
+	for _, c := range diff.Removed {
+		matched[c.OldRef.Path] = c
+	}
+
+	var stale []StalePick
+	for i, p := range picks {
+		if p.SlateGame == nil {
+			continue
+		}
+		if _, ok := current[p.SlateGame.Path]; ok {
+			continue
+		}
+		sp := StalePick{Pick: p, Ref: pickRefs[i]}
+		if c, ok := matched[p.SlateGame.Path]; ok {
+			sp.Change = c
+		} else {
+			sp.Change = SlateGameChange{OldRef: p.SlateGame}
+		}
+		stale = append(stale, sp)
+	}
+	return stale
+}
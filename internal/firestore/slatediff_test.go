@@ -0,0 +1,140 @@
+package firestore
+
+import (
+	"strings"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func testSlateGames(slate string, games ...SlateGame) ([]SlateGame, []*fs.DocumentRef) {
+	refs := make([]*fs.DocumentRef, len(games))
+	for i, g := range games {
+		id := g.Game.ID
+		if g.Superdog {
+			id += "-dog"
+		}
+		refs[i] = &fs.DocumentRef{ID: id, Path: "slates/" + slate + "/games/" + id}
+	}
+	return games, refs
+}
+
+func TestDiffSlateGames(t *testing.T) {
+	a := &fs.DocumentRef{ID: "a"}
+	b := &fs.DocumentRef{ID: "b"}
+	c := &fs.DocumentRef{ID: "c"}
+	d := &fs.DocumentRef{ID: "d"}
+
+	oldGames, oldRefs := testSlateGames("old",
+		SlateGame{Row: 1, Game: a},
+		SlateGame{Row: 2, Game: b, NoisySpread: 7, HomeFavored: true},
+		SlateGame{Row: 3, Game: c},
+		SlateGame{Row: 4, Game: a, Superdog: true, Value: 5},
+	)
+	newGames, newRefs := testSlateGames("new",
+		SlateGame{Row: 1, Game: a},
+		SlateGame{Row: 2, Game: b, NoisySpread: 10, HomeFavored: true, GOTW: true},
+		SlateGame{Row: 3, Game: d},
+		SlateGame{Row: 4, Game: a, Superdog: true, Value: 5},
+	)
+
+	diff := DiffSlateGames(oldGames, oldRefs, newGames, newRefs)
+	if len(diff.Unchanged) != 2 {
+		t.Errorf("expected 2 unchanged games, got %d", len(diff.Unchanged))
+	}
+	if len(diff.Changed) != 1 || len(diff.Changed[0].Fields) != 2 || diff.Changed[0].Fields[0] != "GOTW" || diff.Changed[0].Fields[1] != "NoisySpread" {
+		t.Errorf("expected game b to change GOTW and NoisySpread, got %v", diff.Changed)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Old.Game.ID != "c" {
+		t.Errorf("expected game c removed, got %v", diff.Removed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].New.Game.ID != "d" {
+		t.Errorf("expected game d added, got %v", diff.Added)
+	}
+	for _, u := range diff.Unchanged {
+		if u.Old.Superdog != u.New.Superdog {
+			t.Errorf("expected superdog games to match superdog games, got %v", u)
+		}
+	}
+
+	picks := []Pick{
+		{SlateGame: oldRefs[1]},
+		{SlateGame: oldRefs[2]},
+		{SlateGame: newRefs[0]},
+	}
+	pickRefs := []*fs.DocumentRef{{ID: "p1"}, {ID: "p2"}, {ID: "p3"}}
+	stale := FindStalePicks(picks, pickRefs, diff)
+	if len(stale) != 2 {
+		t.Fatalf("expected 2 stale picks, got %d", len(stale))
+	}
+	if !stale[0].Migratable() || stale[0].Change.NewRef.Path != newRefs[1].Path {
+		t.Errorf("expected pick on game b to migrate to new slate, got %v", stale[0].Change)
+	}
+	if stale[1].Migratable() {
+		t.Errorf("expected pick on removed game c not to be migratable")
+	}
+}
+
+func TestFindStalePicks(t *testing.T) {
+	a := &fs.DocumentRef{ID: "a"}
+	b := &fs.DocumentRef{ID: "b"}
+	c := &fs.DocumentRef{ID: "c"}
+
+	// game a is on both slates twice, as a straight pick and as a superdog
+	oldGames, oldRefs := testSlateGames("old",
+		SlateGame{Row: 1, Game: a},
+		SlateGame{Row: 2, Game: b, NoisySpread: 7, HomeFavored: true},
+		SlateGame{Row: 3, Game: c},
+		SlateGame{Row: 4, Game: a, Superdog: true, Value: 5},
+	)
+	newGames, newRefs := testSlateGames("new",
+		SlateGame{Row: 1, Game: a, Superdog: true, Value: 5},
+		SlateGame{Row: 2, Game: b, NoisySpread: 10, HomeFavored: true},
+		SlateGame{Row: 3, Game: a},
+	)
+	diff := DiffSlateGames(oldGames, oldRefs, newGames, newRefs)
+
+	tests := []struct {
+		name       string
+		game       *fs.DocumentRef
+		stale      bool
+		migratable bool
+		to         *fs.DocumentRef
+		fields     []string
+	}{
+		{"unchanged terms", oldRefs[0], true, true, newRefs[2], nil},
+		{"changed terms", oldRefs[1], true, true, newRefs[1], []string{"NoisySpread"}},
+		{"removed game", oldRefs[2], true, false, nil, nil},
+		{"superdog of a game listed twice", oldRefs[3], true, true, newRefs[0], nil},
+		{"other slate", &fs.DocumentRef{ID: "a", Path: "slates/older/games/a"}, true, false, nil, nil},
+		{"new slate", newRefs[1], false, false, nil, nil},
+		{"no game", nil, false, false, nil, nil},
+	}
+	for _, test := range tests {
+		stale := FindStalePicks([]Pick{{SlateGame: test.game}}, []*fs.DocumentRef{{ID: "p1"}}, diff)
+		if (len(stale) == 1) != test.stale {
+			t.Errorf("%s: expected stale %t, got %v", test.name, test.stale, stale)
+			continue
+		}
+		if !test.stale {
+			continue
+		}
+		s := stale[0]
+		if s.Ref.ID != "p1" || s.Change.OldRef.Path != test.game.Path {
+			t.Errorf("%s: expected pick p1 of %s, got %+v", test.name, test.game.Path, s)
+		}
+		if s.Migratable() != test.migratable {
+			t.Errorf("%s: expected migratable %t, got %t", test.name, test.migratable, s.Migratable())
+			continue
+		}
+		if !test.migratable {
+			continue
+		}
+		if s.Change.NewRef.Path != test.to.Path {
+			t.Errorf("%s: expected migration to %s, got %s", test.name, test.to.Path, s.Change.NewRef.Path)
+		}
+		if strings.Join(s.Change.Fields, ",") != strings.Join(test.fields, ",") {
+			t.Errorf("%s: expected changed fields %v, got %v", test.name, test.fields, s.Change.Fields)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+// Points returns the points earned by picking a team in the slate game, and whether the game has been decided.
+// A straight-up pick is correct if the team wins. A noisy spread pick of the favorite is correct if the favorite wins by at least the spread,
+// and a pick of the underdog is correct otherwise. A superdog pick earns points only if the underdog wins; an unpicked (nil) superdog earns nothing.
+func (sg SlateGame) Points(game Game, picked *fs.DocumentRef) (int, bool) {
+	if game.HomePoints == nil || game.AwayPoints == nil {
+		return 0, false
+	}
+	if picked == nil {
+		return 0, true
+	}
+	margin := *game.HomePoints - *game.AwayPoints
+	var homeCorrect bool
+	switch {
+	case sg.NoisySpread > 0:
+		homeCorrect = margin >= sg.NoisySpread
+	case sg.NoisySpread < 0:
+		homeCorrect = margin > sg.NoisySpread
+	default:
+		homeCorrect = margin > 0
+	}
+	if sg.Superdog && (picked.ID == game.HomeTeam.ID) == sg.HomeFavored {
+		// picking the favorite in a superdog game is not allowed
+		return 0, true
+	}
+	if (picked.ID == game.HomeTeam.ID) == homeCorrect {
+		return sg.Value, true
+	}
+	return 0, true
+}
+
+// WeekResult is what happened in a week of pick 'em.
+type WeekResult struct {
+	Week int
+
+	// Scores are the points earned by each picker, keyed by picker ID.
+	Scores map[string]int
+
+	// Picks are the teams picked in each non-superdog slate game, keyed by slate game ID, then picker ID, then picked team ID.
+	Picks map[string]map[string]string
+
+	// Favorites are the IDs of the teams favored on the slate in each non-superdog slate game, keyed by slate game ID.
+	Favorites map[string]string
+
+	// Decided is true if every game on the slate has been decided.
+	Decided bool
+}
+
+// ScoreWeek scores the picks made against a slate. Picks made against other slates are ignored.
+// Games are looked up by ID to find the results.
+func ScoreWeek(sgames []SlateGame, sgRefs []*fs.DocumentRef, games map[string]Game, picks []Pick) (WeekResult, error) {
+	result := WeekResult{Scores: make(map[string]int), Picks: make(map[string]map[string]string), Favorites: make(map[string]string), Decided: true}
+	byPath := make(map[string]int)
+	for i, ref := range sgRefs {
+		byPath[ref.Path] = i
+		game, ok := games[sgames[i].Game.ID]
+		if !ok {
+			return result, fmt.Errorf("game %s of slate game %s not found", sgames[i].Game.ID, ref.ID)
+		}
+		if _, decided := sgames[i].Points(game, nil); !decided {
+			result.Decided = false
+		}
+		if !sgames[i].Superdog {
+			favorite := game.AwayTeam
+			if sgames[i].HomeFavored {
+				favorite = game.HomeTeam
+			}
+			if favorite != nil {
+				result.Favorites[ref.ID] = favorite.ID
+			}
+		}
+	}
+	for _, p := range picks {
+		if p.Picker == nil || p.SlateGame == nil {
+			continue
+		}
+		i, ok := byPath[p.SlateGame.Path]
+		if !ok {
+			continue
+		}
+		sg := sgames[i]
+		points, _ := sg.Points(games[sg.Game.ID], p.PickedTeam)
+		result.Scores[p.Picker.ID] += points
+		if sg.Superdog || p.PickedTeam == nil {
+			continue
+		}
+		id := sgRefs[i].ID
+		if _, ok := result.Picks[id]; !ok {
+			result.Picks[id] = make(map[string]string)
+		}
+		result.Picks[id][p.Picker.ID] = p.PickedTeam.ID
+	}
+	return result, nil
+}
+
+// GetWeekResults scores every week of the season before the given week number against the week's most recent slate.
+// Weeks without a slate are skipped.
+func GetWeekResults(ctx context.Context, season *fs.DocumentRef, before int) ([]WeekResult, error) {
+	weeks, weekRefs, err := GetWeeks(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]WeekResult, 0, len(weeks))
+	for i, week := range weeks {
+		if week.Number >= before {
+			continue
+		}
+		sgames, sgRefs, err := GetSlateGames(ctx, weekRefs[i])
+		if _, ok := err.(NoSlateError); ok {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slate games for week %d: %w", week.Number, err)
+		}
+		games, gameRefs, err := GetGames(ctx, weekRefs[i])
+		if err != nil {
+			return nil, err
+		}
+		gamesByID := make(map[string]Game)
+		for j, ref := range gameRefs {
+			gamesByID[ref.ID] = games[j]
+		}
+		picks, _, err := GetWeekPicks(ctx, weekRefs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get picks for week %d: %w", week.Number, err)
+		}
+		result, err := ScoreWeek(sgames, sgRefs, gamesByID, picks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score week %d: %w", week.Number, err)
+		}
+		result.Week = week.Number
+		results = append(results, result)
+	}
+	return results, nil
+}
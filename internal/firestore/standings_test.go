@@ -0,0 +1,96 @@
+package firestore
+
+import (
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+func TestSlateGamePoints(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	score := func(home, away int) Game {
+		return Game{HomeTeam: mich, AwayTeam: osu, HomePoints: &home, AwayPoints: &away}
+	}
+
+	tests := []struct {
+		name   string
+		sg     SlateGame
+		game   Game
+		picked *fs.DocumentRef
+		want   int
+	}{
+		{"straight up home", SlateGame{Value: 1}, score(28, 21), mich, 1},
+		{"straight up away", SlateGame{Value: 1}, score(28, 21), osu, 0},
+		{"noisy favorite covers", SlateGame{Value: 2, NoisySpread: 7, HomeFavored: true}, score(28, 21), mich, 2},
+		{"noisy favorite misses", SlateGame{Value: 2, NoisySpread: 8, HomeFavored: true}, score(28, 21), osu, 2},
+		{"noisy away favorite", SlateGame{Value: 2, NoisySpread: -7}, score(21, 28), osu, 2},
+		{"noisy away underdog", SlateGame{Value: 2, NoisySpread: -7}, score(21, 27), mich, 2},
+		{"superdog wins", SlateGame{Value: 5, Superdog: true, HomeFavored: false}, score(28, 21), mich, 5},
+		{"superdog favorite", SlateGame{Value: 5, Superdog: true, HomeFavored: true}, score(28, 21), mich, 0},
+		{"unpicked superdog", SlateGame{Value: 5, Superdog: true}, score(28, 21), nil, 0},
+	}
+	for _, test := range tests {
+		got, decided := test.sg.Points(test.game, test.picked)
+		if !decided {
+			t.Errorf("%s: expected game to be decided", test.name)
+		}
+		if got != test.want {
+			t.Errorf("%s: expected %d points, got %d", test.name, test.want, got)
+		}
+	}
+
+	if _, decided := (SlateGame{Value: 1}).Points(Game{HomeTeam: mich, AwayTeam: osu}, mich); decided {
+		t.Error("expected game without a score to be undecided")
+	}
+}
+
+func TestScoreWeek(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	iowa := &fs.DocumentRef{ID: "iowa"}
+	msu := &fs.DocumentRef{ID: "msu"}
+	alice := &fs.DocumentRef{ID: "alice"}
+	bob := &fs.DocumentRef{ID: "bob"}
+	sg1 := &fs.DocumentRef{ID: "sg1", Path: "slates/s/games/sg1"}
+	sg2 := &fs.DocumentRef{ID: "sg2", Path: "slates/s/games/sg2"}
+	old := &fs.DocumentRef{ID: "sg1", Path: "slates/old/games/sg1"}
+
+	h, a := 28, 21
+	games := map[string]Game{
+		"g1": {HomeTeam: mich, AwayTeam: osu, HomePoints: &h, AwayPoints: &a},
+		"g2": {HomeTeam: iowa, AwayTeam: msu},
+	}
+	sgames := []SlateGame{
+		{Game: &fs.DocumentRef{ID: "g1"}, Value: 1},
+		{Game: &fs.DocumentRef{ID: "g2"}, Value: 5, Superdog: true, HomeFavored: true},
+	}
+	picks := []Pick{
+		{SlateGame: sg1, PickedTeam: mich, Picker: alice},
+		{SlateGame: sg1, PickedTeam: osu, Picker: bob},
+		{SlateGame: sg2, PickedTeam: msu, Picker: alice},
+		{SlateGame: old, PickedTeam: mich, Picker: bob},
+	}
+
+	result, err := ScoreWeek(sgames, []*fs.DocumentRef{sg1, sg2}, games, picks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Decided {
+		t.Error("expected week with an unplayed game to be undecided")
+	}
+	if result.Scores["alice"] != 1 || result.Scores["bob"] != 0 {
+		t.Errorf("expected alice 1 and bob 0, got %v", result.Scores)
+	}
+	if len(result.Picks) != 1 || result.Picks["sg1"]["alice"] != "mich" || result.Picks["sg1"]["bob"] != "osu" {
+		t.Errorf("expected only sg1 picks, got %v", result.Picks)
+	}
+	if len(result.Favorites) != 1 || result.Favorites["sg1"] != "osu" {
+		t.Errorf("expected osu favored in sg1 only, got %v", result.Favorites)
+	}
+
+	delete(games, "g2")
+	if _, err := ScoreWeek(sgames, []*fs.DocumentRef{sg1, sg2}, games, picks); err == nil {
+		t.Error("expected error for missing game")
+	}
+}
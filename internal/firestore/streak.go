@@ -95,6 +95,31 @@ type StreakTeamsRemaining struct {
 	PickTypesRemaining []int `firestore:"pick_types_remaining"`
 }
 
+// StreakPickFromRemaining derives the streak pick a picker made in a week from the teams remaining going into the week (before) and the following week (after).
+// Teams no longer remaining were picked, and the pick is a bye if a bye was used. It returns false if the remaining teams show no pick.
+func StreakPickFromRemaining(before, after StreakTeamsRemaining) (StreakPick, bool) {
+	pick := StreakPick{Picker: after.Picker}
+	if pick.Picker == nil {
+		pick.Picker = before.Picker
+	}
+	left := make(map[string]bool)
+	for _, team := range after.TeamsRemaining {
+		if team != nil {
+			left[team.ID] = true
+		}
+	}
+	for _, team := range before.TeamsRemaining {
+		if team != nil && !left[team.ID] {
+			pick.PickedTeams = append(pick.PickedTeams, team)
+		}
+	}
+	if len(pick.PickedTeams) > 0 {
+		return pick, true
+	}
+	bye := len(before.PickTypesRemaining) > 0 && len(after.PickTypesRemaining) > 0 && after.PickTypesRemaining[0] < before.PickTypesRemaining[0]
+	return pick, bye
+}
+
 type NoStreakTeamsRemaining struct {
 	PickerID string
 	WeekID   string
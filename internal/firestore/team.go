@@ -81,6 +81,24 @@ func (n NameType) String() string {
 	return "unknown"
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+func (n NameType) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (n *NameType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "short":
+		*n = ShortName
+	case "other":
+		*n = OtherName
+	default:
+		return fmt.Errorf("unrecognized name type '%s'", string(text))
+	}
+	return nil
+}
+
 type DuplicateTeamNameError struct {
 	// Name is the duplicate name detected
 	Name string
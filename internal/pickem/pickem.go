@@ -0,0 +1,176 @@
+// Package pickem models the outcomes of a week's pick 'em slate so that picks can be chosen to maximize expected points
+// or the chance of finishing ahead of the other pickers.
+package pickem
+
+import (
+	"math/rand"
+)
+
+// Side is the team picked in a slate game.
+type Side int
+
+const (
+	// None means the game is not picked, as with every superdog but the one chosen.
+	None Side = iota
+	Home
+	Away
+)
+
+func (s Side) String() string {
+	switch s {
+	case Home:
+		return "home"
+	case Away:
+		return "away"
+	}
+	return "none"
+}
+
+// Other returns the other team.
+func (s Side) Other() Side {
+	switch s {
+	case Home:
+		return Away
+	case Away:
+		return Home
+	}
+	return None
+}
+
+// Game is a slate game reduced to what matters for scoring.
+type Game struct {
+	// ID identifies the game in Picks.
+	ID string
+
+	// Value is the number of points earned by a correct pick.
+	Value int
+
+	// Superdog is true if only the underdog may be picked.
+	Superdog bool
+
+	// Dog is the underdog of a superdog game.
+	Dog Side
+
+	// PHome is the probability that a pick of the home team is correct: that the home team wins, or covers the noisy spread if there is one.
+	PHome float64
+}
+
+// P returns the probability that a pick of the given side is correct.
+func (g Game) P(s Side) float64 {
+	switch s {
+	case Home:
+		return g.PHome
+	case Away:
+		return 1 - g.PHome
+	}
+	return 0
+}
+
+// Favorite returns the side more likely to be correct.
+func (g Game) Favorite() Side {
+	if g.PHome >= .5 {
+		return Home
+	}
+	return Away
+}
+
+// Picks are a picker's picks keyed by game ID. Games that are missing are not picked.
+type Picks map[string]Side
+
+// Score returns the points earned by picks given which games the home team won (or covered).
+func Score(games []Game, picks Picks, homeWins []bool) int {
+	score := 0
+	for i, g := range games {
+		switch picks[g.ID] {
+		case Home:
+			if homeWins[i] {
+				score += g.Value
+			}
+		case Away:
+			if !homeWins[i] {
+				score += g.Value
+			}
+		}
+	}
+	return score
+}
+
+// ExpectedScore returns the expected points earned by picks.
+func ExpectedScore(games []Game, picks Picks) float64 {
+	e := 0.
+	for _, g := range games {
+		e += g.P(picks[g.ID]) * float64(g.Value)
+	}
+	return e
+}
+
+// Draw draws an outcome for every game, returning true for each game the home team won (or covered).
+func Draw(games []Game, rng *rand.Rand) []bool {
+	homeWins := make([]bool, len(games))
+	for i, g := range games {
+		homeWins[i] = rng.Float64() < g.PHome
+	}
+	return homeWins
+}
+
+// LikelyPicks returns the picks a picker who follows the odds would make: the favorite in every game,
+// and the superdog with the most expected points.
+func LikelyPicks(games []Game) Picks {
+	picks := make(Picks)
+	best := -1
+	for i, g := range games {
+		if !g.Superdog {
+			picks[g.ID] = g.Favorite()
+			continue
+		}
+		if best < 0 || g.P(g.Dog)*float64(g.Value) > games[best].P(games[best].Dog)*float64(games[best].Value) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		picks[games[best].ID] = games[best].Dog
+	}
+	return picks
+}
+
+// WinProbability estimates by simulation the probability that mine scores the most points of all the pickers.
+// Ties for first count as a fraction of a win split evenly among the tied pickers.
+func WinProbability(games []Game, mine Picks, others []Picks, iterations int, rng *rand.Rand) float64 {
+	return SimulatedWinProbability(games, mine, others, Simulate(games, iterations, rng))
+}
+
+// Simulate draws outcomes for the games repeatedly. Evaluating different picks against the same outcomes makes their comparison less noisy.
+func Simulate(games []Game, iterations int, rng *rand.Rand) [][]bool {
+	outcomes := make([][]bool, iterations)
+	for i := range outcomes {
+		outcomes[i] = Draw(games, rng)
+	}
+	return outcomes
+}
+
+// SimulatedWinProbability returns the fraction of simulated outcomes in which mine scores the most points of all the pickers.
+func SimulatedWinProbability(games []Game, mine Picks, others []Picks, outcomes [][]bool) float64 {
+	if len(outcomes) == 0 {
+		return 0
+	}
+	wins := 0.
+	for _, homeWins := range outcomes {
+		wins += winShare(Score(games, mine, homeWins), games, others, homeWins)
+	}
+	return wins / float64(len(outcomes))
+}
+
+// winShare returns the share of first place earned by a score against the other pickers' picks.
+func winShare(score int, games []Game, others []Picks, homeWins []bool) float64 {
+	tied := 1
+	for _, o := range others {
+		s := Score(games, o, homeWins)
+		if s > score {
+			return 0
+		}
+		if s == score {
+			tied++
+		}
+	}
+	return 1 / float64(tied)
+}
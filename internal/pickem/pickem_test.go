@@ -0,0 +1,156 @@
+package pickem
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var testGames = []Game{
+	{ID: "a", Value: 1, PHome: .8},
+	{ID: "b", Value: 2, PHome: .3},
+	{ID: "c", Value: 5, Superdog: true, Dog: Home, PHome: .3},
+	{ID: "d", Value: 10, Superdog: true, Dog: Away, PHome: .9},
+	{ID: "e", Value: 3, Superdog: true, Dog: Away, PHome: .55},
+}
+
+func TestScore(t *testing.T) {
+	picks := Picks{"a": Home, "b": Home, "d": Away}
+	if s := Score(testGames, picks, []bool{true, false, true, false, true}); s != 11 {
+		t.Errorf("expected 11 points, got %d", s)
+	}
+	if s := Score(testGames, picks, []bool{false, true, false, true, false}); s != 2 {
+		t.Errorf("expected 2 points, got %d", s)
+	}
+	if e := ExpectedScore(testGames, picks); math.Abs(e-(.8+.6+1)) > 1e-9 {
+		t.Errorf("expected 2.4 expected points, got %f", e)
+	}
+}
+
+func TestLikelyPicks(t *testing.T) {
+	picks := LikelyPicks(testGames)
+	want := Picks{"a": Home, "b": Away, "c": Home}
+	if len(picks) != len(want) {
+		t.Fatalf("expected %v, got %v", want, picks)
+	}
+	for id, s := range want {
+		if picks[id] != s {
+			t.Errorf("game %s: expected %s, got %s", id, s, picks[id])
+		}
+	}
+}
+
+func TestEvaluateSuperdogsExpectedPoints(t *testing.T) {
+	options, err := EvaluateSuperdogs(testGames, Picks{"a": Home, "b": Away}, nil, MaxExpectedPoints, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	order := []string{"c", "e", "d"}
+	for i, o := range options {
+		if o.Game != order[i] {
+			t.Errorf("option %d: expected %s, got %s", i, order[i], o)
+		}
+	}
+	if math.Abs(options[0].ExpectedPoints-1.5) > 1e-9 {
+		t.Errorf("expected 1.5 expected points for c, got %s", options[0])
+	}
+
+	if _, err := EvaluateSuperdogs(testGames, nil, nil, "bogus", 0, nil); err == nil {
+		t.Error("expected error for unrecognized strategy")
+	}
+}
+
+func TestEvaluateSuperdogsWeekWin(t *testing.T) {
+	// Against a rival who makes the same picks and takes the best expected superdog, only a different superdog can win the week outright,
+	// and the long shot worth the most points wins the most often.
+	mine := Picks{"a": Home, "b": Away}
+	rival := Picks{"a": Home, "b": Away, "c": Home}
+	options, err := EvaluateSuperdogs(testGames, mine, []Picks{rival}, MaxWeekWin, 20000, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options[0].Game == "c" {
+		t.Errorf("expected copying the rival's superdog not to be best, got %v", options)
+	}
+	for _, o := range options {
+		if o.Game == "c" && math.Abs(o.WinProbability-.5) > 1e-9 {
+			t.Errorf("expected copying the rival to win exactly half the time, got %s", o)
+		}
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	games := []Game{{ID: "a", Value: 1, PHome: .7}}
+	p := WinProbability(games, Picks{"a": Home}, []Picks{{"a": Away}}, 20000, rand.New(rand.NewSource(0)))
+	if math.Abs(p-.7) > .02 {
+		t.Errorf("expected win probability near .7, got %f", p)
+	}
+	if p := WinProbability(games, Picks{"a": Home}, nil, 10, rand.New(rand.NewSource(0))); p != 1 {
+		t.Errorf("expected a lone picker to always win, got %f", p)
+	}
+}
+
+func TestChooseSeasonWin(t *testing.T) {
+	// One point behind a rival who has already picked the favorite, the only way to catch up is to pick the underdog.
+	games := []Game{{ID: "a", Value: 1, PHome: .6}}
+	contest := Contest{
+		Games:     games,
+		Opponents: []Opponent{{ID: "rival", Picks: Picks{"a": Home}, Chalk: 1, Points: 1}},
+	}
+	eval, err := contest.Choose(SeasonWin, 20000, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Picks["a"] != Away {
+		t.Errorf("expected the underdog to be picked, got %v", eval.Picks)
+	}
+	if eval.Baseline != 0 {
+		t.Errorf("expected no chance of winning with the favorite, got %f", eval.Baseline)
+	}
+	if math.Abs(eval.WinProbability-.2) > .02 {
+		t.Errorf("expected win probability near .2, got %f", eval.WinProbability)
+	}
+
+	// For the week alone, the points behind do not matter and copying the rival guarantees a tie.
+	eval, err = contest.Choose(WeekWin, 20000, rand.New(rand.NewSource(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Picks["a"] != Home {
+		t.Errorf("expected the favorite to be picked, got %v", eval.Picks)
+	}
+	if math.Abs(eval.WinProbability-.5) > 1e-9 {
+		t.Errorf("expected to win exactly half the time, got %f", eval.WinProbability)
+	}
+
+	eval, err = contest.Choose(Expected, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Picks["a"] != Home || math.Abs(eval.ExpectedPoints-.6) > 1e-9 {
+		t.Errorf("expected the favorite with .6 expected points, got %+v", eval)
+	}
+
+	if _, err := contest.Choose("bogus", 0, nil); err == nil {
+		t.Error("expected error for unrecognized strategy")
+	}
+}
+
+func TestChalkRates(t *testing.T) {
+	games := []PastGame{
+		// most pickers faded the favorite, but only picks of the favorite count
+		{Favorite: "OSU", Picks: map[string]string{"x": "MICH", "y": "MICH", "z": "OSU"}},
+		{Favorite: "IOWA", Picks: map[string]string{"x": "IOWA", "y": "IOWA"}},
+		{Picks: map[string]string{"x": "PUR", "z": "PUR"}},
+	}
+	rates := ChalkRates(games, .8, 5)
+	if math.Abs(rates["x"]-5./7) > 1e-9 {
+		t.Errorf("expected x to pick the favorite at 5/7, got %f", rates["x"])
+	}
+	if math.Abs(rates["z"]-5./6) > 1e-9 {
+		t.Errorf("expected z to pick the favorite at 5/6, got %f", rates["z"])
+	}
+	if _, ok := rates["w"]; ok {
+		t.Error("expected no rate for a picker without picks")
+	}
+}
@@ -0,0 +1,270 @@
+package pickem
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Strategy is what picks are chosen to maximize.
+type Strategy string
+
+const (
+	// Expected maximizes expected points: the favorite in every game and the superdog with the most expected points.
+	Expected Strategy = "expected"
+
+	// WeekWin maximizes the probability of scoring the most points of all pickers this week.
+	WeekWin Strategy = "week"
+
+	// SeasonWin maximizes the probability of finishing the season with the most points of all pickers.
+	SeasonWin Strategy = "season"
+)
+
+// Opponent is how another picker is expected to pick.
+type Opponent struct {
+	// ID identifies the picker.
+	ID string
+
+	// Picks are the picks the picker has already made this week. Games that are not picked are picked according to Chalk.
+	// If no superdog is picked, the superdog with the most expected points is assumed.
+	Picks Picks
+
+	// Chalk is the probability that the picker picks the favorite in a game.
+	Chalk float64
+
+	// Points are the picker's season points before this week.
+	Points float64
+
+	// WeeklyMean is the average number of points the picker scores in a week, used to project the rest of the season.
+	WeeklyMean float64
+}
+
+// Contest is a week of pick 'em and, for SeasonWin, the standings going into it.
+type Contest struct {
+	Games     []Game
+	Opponents []Opponent
+
+	// Points are my season points before this week.
+	Points float64
+
+	// WeeklyMean is the average number of points I score in a week.
+	WeeklyMean float64
+
+	// WeeklyStdDev is the standard deviation of a picker's weekly points, used to project the rest of the season.
+	WeeklyStdDev float64
+
+	// RemainingWeeks is the number of weeks left in the season after this one.
+	RemainingWeeks int
+}
+
+// scenario is one simulated week (and rest of the season).
+type scenario struct {
+	homeWins []bool
+
+	// mine is my points from outside this week: season points so far plus projected future points.
+	mine float64
+
+	// best is the best opponent total, and nBest the number of opponents with that total.
+	best  float64
+	nBest int
+}
+
+// simulate draws outcomes, opponents' picks, and projected futures.
+func (c Contest) simulate(strategy Strategy, iterations int, rng *rand.Rand) []scenario {
+	likely := LikelyPicks(c.Games)
+	scenarios := make([]scenario, iterations)
+	for i := range scenarios {
+		s := scenario{homeWins: Draw(c.Games, rng), best: math.Inf(-1)}
+		if strategy == SeasonWin {
+			s.mine = c.Points + c.future(c.WeeklyMean, rng)
+		}
+		for _, o := range c.Opponents {
+			total := float64(Score(c.Games, o.draw(c.Games, likely, rng), s.homeWins))
+			if strategy == SeasonWin {
+				total += o.Points + c.future(o.WeeklyMean, rng)
+			}
+			switch {
+			case total > s.best:
+				s.best = total
+				s.nBest = 1
+			case total == s.best:
+				s.nBest++
+			}
+		}
+		scenarios[i] = s
+	}
+	return scenarios
+}
+
+// future projects the points scored in the remaining weeks of the season.
+func (c Contest) future(mean float64, rng *rand.Rand) float64 {
+	if c.RemainingWeeks <= 0 {
+		return 0
+	}
+	n := float64(c.RemainingWeeks)
+	return n*mean + math.Sqrt(n)*c.WeeklyStdDev*rng.NormFloat64()
+}
+
+// draw draws the opponent's picks for the week.
+func (o Opponent) draw(games []Game, likely Picks, rng *rand.Rand) Picks {
+	picks := make(Picks)
+	pickedDog := false
+	for _, g := range games {
+		if s, ok := o.Picks[g.ID]; ok && s != None {
+			picks[g.ID] = s
+			if g.Superdog {
+				pickedDog = true
+			}
+			continue
+		}
+		if g.Superdog {
+			continue
+		}
+		if rng.Float64() < o.Chalk {
+			picks[g.ID] = g.Favorite()
+		} else {
+			picks[g.ID] = g.Favorite().Other()
+		}
+	}
+	if !pickedDog {
+		for _, g := range games {
+			if g.Superdog && likely[g.ID] != None {
+				picks[g.ID] = likely[g.ID]
+			}
+		}
+	}
+	return picks
+}
+
+// winProbability returns my share of first place over the scenarios with the given picks.
+func (c Contest) winProbability(scenarios []scenario, picks Picks) float64 {
+	if len(scenarios) == 0 {
+		return 0
+	}
+	wins := 0.
+	for _, s := range scenarios {
+		mine := s.mine + float64(Score(c.Games, picks, s.homeWins))
+		switch {
+		case s.nBest == 0 || mine > s.best:
+			wins++
+		case mine == s.best:
+			wins += 1 / float64(s.nBest+1)
+		}
+	}
+	return wins / float64(len(scenarios))
+}
+
+// Evaluation is the result of choosing picks with a strategy.
+type Evaluation struct {
+	Picks Picks
+
+	// ExpectedPoints are the expected points earned this week.
+	ExpectedPoints float64
+
+	// WinProbability is the estimated probability of finishing first for the week or season. It is not computed for Expected.
+	WinProbability float64
+
+	// Baseline is the estimated probability of finishing first with the picks that maximize expected points.
+	Baseline float64
+}
+
+// Choose chooses the picks that maximize the objective of the strategy.
+// Starting from the picks that maximize expected points, picks are switched to the other team (or to another superdog) one at a time
+// as long as the switch improves the estimated probability of finishing first. Every candidate is evaluated against the same simulated scenarios.
+func (c Contest) Choose(strategy Strategy, iterations int, rng *rand.Rand) (Evaluation, error) {
+	picks := LikelyPicks(c.Games)
+	switch strategy {
+	case Expected:
+		return Evaluation{Picks: picks, ExpectedPoints: ExpectedScore(c.Games, picks)}, nil
+	case WeekWin, SeasonWin:
+	default:
+		return Evaluation{}, fmt.Errorf("unrecognized strategy '%s'", strategy)
+	}
+
+	scenarios := c.simulate(strategy, iterations, rng)
+	best := c.winProbability(scenarios, picks)
+	eval := Evaluation{Baseline: best}
+	for {
+		var bestMove Picks
+		for _, move := range c.moves(picks) {
+			if p := c.winProbability(scenarios, move); p > best {
+				best = p
+				bestMove = move
+			}
+		}
+		if bestMove == nil {
+			break
+		}
+		picks = bestMove
+	}
+	eval.Picks = picks
+	eval.WinProbability = best
+	eval.ExpectedPoints = ExpectedScore(c.Games, picks)
+	return eval, nil
+}
+
+// moves returns every set of picks that differs from picks by switching one game to the other team or by picking a different superdog.
+func (c Contest) moves(picks Picks) []Picks {
+	moves := make([]Picks, 0, len(c.Games))
+	for _, g := range c.Games {
+		if g.Superdog {
+			if picks[g.ID] != None {
+				continue
+			}
+			move := copyPicks(picks)
+			for _, other := range c.Games {
+				if other.Superdog {
+					delete(move, other.ID)
+				}
+			}
+			move[g.ID] = g.Dog
+			moves = append(moves, move)
+			continue
+		}
+		move := copyPicks(picks)
+		move[g.ID] = picks[g.ID].Other()
+		moves = append(moves, move)
+	}
+	return moves
+}
+
+func copyPicks(p Picks) Picks {
+	c := make(Picks, len(p))
+	for id, s := range p {
+		c[id] = s
+	}
+	return c
+}
+
+// PastGame is a game picked in a past week.
+type PastGame struct {
+	// Favorite is the team favored on the slate.
+	Favorite string
+
+	// Picks map picker IDs to the team picked.
+	Picks map[string]string
+}
+
+// ChalkRates estimates how often each picker picks the favorite from the picks made in past games.
+// Rates are shrunk toward priorRate as if each picker had made priorWeight picks at that rate, so pickers with few past picks are assumed to pick like everyone else.
+// Games without a favorite are ignored.
+func ChalkRates(games []PastGame, priorRate float64, priorWeight float64) map[string]float64 {
+	agree := make(map[string]float64)
+	total := make(map[string]float64)
+	for _, g := range games {
+		if g.Favorite == "" {
+			continue
+		}
+		for picker, team := range g.Picks {
+			total[picker]++
+			if team == g.Favorite {
+				agree[picker]++
+			}
+		}
+	}
+	rates := make(map[string]float64)
+	for picker, n := range total {
+		rates[picker] = (agree[picker] + priorRate*priorWeight) / (n + priorWeight)
+	}
+	return rates
+}
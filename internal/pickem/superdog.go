@@ -0,0 +1,90 @@
+package pickem
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// SuperdogStrategy decides which superdog to pick.
+type SuperdogStrategy string
+
+const (
+	// MaxExpectedPoints picks the superdog with the most expected points (probability of winning times value).
+	MaxExpectedPoints SuperdogStrategy = "ev"
+
+	// MaxWeekWin picks the superdog that gives the best chance of scoring the most points of all pickers for the week.
+	MaxWeekWin SuperdogStrategy = "week"
+)
+
+// SuperdogOption is the evaluation of picking the underdog in one superdog game.
+type SuperdogOption struct {
+	Game  string
+	Dog   Side
+	Value int
+
+	// Probability is the probability the underdog wins.
+	Probability float64
+
+	// ExpectedPoints is the expected points earned by the superdog pick alone.
+	ExpectedPoints float64
+
+	// WinProbability is the probability of scoring the most points of all pickers for the week with this superdog. It is only computed for MaxWeekWin.
+	WinProbability float64
+}
+
+// String implements the Stringer interface.
+func (o SuperdogOption) String() string {
+	return fmt.Sprintf("%s (%s): %d points x %0.3f = %0.3f expected points, %0.3f to win the week", o.Game, o.Dog, o.Value, o.Probability, o.ExpectedPoints, o.WinProbability)
+}
+
+// EvaluateSuperdogs evaluates picking each superdog in games along with the non-superdog picks in mine.
+// Options are returned best first according to the strategy.
+// Win probabilities against the other pickers' picks are simulated only for MaxWeekWin.
+func EvaluateSuperdogs(games []Game, mine Picks, others []Picks, strategy SuperdogStrategy, iterations int, rng *rand.Rand) ([]SuperdogOption, error) {
+	if strategy != MaxExpectedPoints && strategy != MaxWeekWin {
+		return nil, fmt.Errorf("unrecognized superdog strategy '%s'", strategy)
+	}
+
+	base := make(Picks)
+	for _, g := range games {
+		if !g.Superdog {
+			base[g.ID] = mine[g.ID]
+		}
+	}
+
+	var outcomes [][]bool
+	if strategy == MaxWeekWin {
+		outcomes = Simulate(games, iterations, rng)
+	}
+
+	options := make([]SuperdogOption, 0)
+	for _, g := range games {
+		if !g.Superdog {
+			continue
+		}
+		p := g.P(g.Dog)
+		o := SuperdogOption{Game: g.ID, Dog: g.Dog, Value: g.Value, Probability: p, ExpectedPoints: p * float64(g.Value)}
+		if strategy == MaxWeekWin {
+			picks := make(Picks)
+			for id, s := range base {
+				picks[id] = s
+			}
+			picks[g.ID] = g.Dog
+			o.WinProbability = SimulatedWinProbability(games, picks, others, outcomes)
+		}
+		options = append(options, o)
+	}
+
+	sort.SliceStable(options, func(i, j int) bool {
+		a, b := options[i], options[j]
+		if strategy == MaxWeekWin && a.WinProbability != b.WinProbability {
+			return a.WinProbability > b.WinProbability
+		}
+		if a.ExpectedPoints != b.ExpectedPoints {
+			return a.ExpectedPoints > b.ExpectedPoints
+		}
+		return a.Probability > b.Probability
+	})
+	return options, nil
+}
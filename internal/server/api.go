@@ -0,0 +1,429 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/recap"
+)
+
+// Season is a season as served by the API.
+type Season struct {
+	Year      int       `json:"year"`
+	StartTime time.Time `json:"start_time"`
+
+	// Pickers are the LukeNames of the pickers playing in the season.
+	Pickers []string `json:"pickers"`
+
+	// PickLock describes when picks lock.
+	PickLock string `json:"pick_lock"`
+}
+
+// Week is a week as served by the API.
+type Week struct {
+	Number         int       `json:"number"`
+	FirstGameStart time.Time `json:"first_game_start"`
+
+	// LockTime is when all of the week's picks lock. It is omitted if picks lock game by game.
+	LockTime *time.Time `json:"lock_time,omitempty"`
+}
+
+// Team is a team as served by the API.
+type Team struct {
+	ID           string `json:"id"`
+	School       string `json:"school"`
+	Mascot       string `json:"mascot"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// SlateGame is a game on a slate as served by the API.
+type SlateGame struct {
+	// ID is the ID of the slate game. Picks refer to games by this ID.
+	ID  string `json:"id"`
+	Row int    `json:"row"`
+
+	Home        Team `json:"home"`
+	Away        Team `json:"away"`
+	HomeRank    int  `json:"home_rank,omitempty"`
+	AwayRank    int  `json:"away_rank,omitempty"`
+	NeutralSite bool `json:"neutral_site"`
+
+	HomeFavored bool `json:"home_favored"`
+	GOTW        bool `json:"gotw"`
+	Superdog    bool `json:"superdog"`
+	Value       int  `json:"value"`
+	NoisySpread int  `json:"noisy_spread"`
+
+	Kickoff  time.Time `json:"kickoff"`
+	LockTime time.Time `json:"lock_time"`
+	Locked   bool      `json:"locked"`
+
+	HomePoints *int `json:"home_points,omitempty"`
+	AwayPoints *int `json:"away_points,omitempty"`
+}
+
+// Slate is a week's slate as served by the API.
+type Slate struct {
+	Season int         `json:"season"`
+	Week   Week        `json:"week"`
+	Games  []SlateGame `json:"games"`
+}
+
+// Pick is a pick as served by the API.
+type Pick struct {
+	Picker string `json:"picker"`
+
+	// Game is the ID of the slate game picked.
+	Game string `json:"game"`
+
+	// Team is the team picked. It is nil if the underdog of a superdog game is not picked.
+	Team *Team `json:"team"`
+
+	PredictedSpread      float64 `json:"predicted_spread,omitempty"`
+	PredictedProbability float64 `json:"predicted_probability,omitempty"`
+
+	// Points are the points the pick earned. They are omitted until the game is decided.
+	Points *int `json:"points,omitempty"`
+}
+
+// Standing is a picker's place in the season standings as served by the API.
+type Standing struct {
+	Picker     string `json:"picker"`
+	Rank       int    `json:"rank"`
+	Points     int    `json:"points"`
+	WeekPoints int    `json:"week_points"`
+}
+
+// Streak is the state of a picker's Beat the Streak streak in a week as served by the API.
+type Streak struct {
+	Picker             string `json:"picker"`
+	TeamsRemaining     []Team `json:"teams_remaining"`
+	PickTypesRemaining []int  `json:"pick_types_remaining"`
+
+	// Picked is true if the picker has made a pick this week.
+	Picked bool `json:"picked"`
+
+	// Pick are the teams picked this week. A pick of no teams is a bye.
+	Pick []Team `json:"pick"`
+
+	// Hidden is true if the pick is another picker's and has not locked yet, in which case Pick is empty.
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Suggested is the best streak of the picker's most recent streak predictions, if the picker has any.
+	Suggested *SuggestedStreak `json:"suggested,omitempty"`
+}
+
+// SuggestedStreak is the streak predicted to give a picker the best chance of beating the streak.
+type SuggestedStreak struct {
+	Probability float64 `json:"probability"`
+	Spread      float64 `json:"spread"`
+
+	// Weeks are the teams to pick this week and in each week after it. A week of no teams is a bye.
+	Weeks [][]Team `json:"weeks"`
+}
+
+// Prediction is a model's prediction of a game as served by the API.
+type Prediction struct {
+	Model string `json:"model"`
+
+	// Spread is the predicted number of points in favor of the game's home team.
+	Spread float64 `json:"spread"`
+}
+
+// GamePredictions are the model predictions of a slate game as served by the API.
+type GamePredictions struct {
+	// Game is the ID of the slate game.
+	Game        string       `json:"game"`
+	Predictions []Prediction `json:"predictions"`
+}
+
+// MissingPicks is what a picker has not yet picked in a week as served by the API.
+type MissingPicks struct {
+	Picker string `json:"picker"`
+
+	// Games are the IDs of the slate games, other than superdog games, that the picker has not picked.
+	Games []string `json:"games"`
+
+	NoSuperdog   bool `json:"no_superdog"`
+	NoStreakPick bool `json:"no_streak_pick"`
+
+	// Reminder is a message reminding the picker of what is missing. It is only served when asked for.
+	Reminder string `json:"reminder,omitempty"`
+}
+
+// names are the names of the pickers and teams of a season, used to convert the season's data for the API.
+type names struct {
+	// pickers are picker LukeNames keyed by picker ID.
+	pickers map[string]string
+
+	// teams are teams keyed by team ID.
+	teams map[string]Team
+}
+
+func newNames(season firestore.Season, teams []firestore.Team, refs []*fs.DocumentRef) names {
+	n := names{pickers: make(map[string]string), teams: make(map[string]Team)}
+	for name, ref := range season.Pickers {
+		n.pickers[ref.ID] = name
+	}
+	for i, t := range teams {
+		n.teams[refs[i].ID] = Team{ID: refs[i].ID, School: t.School, Mascot: t.Mascot, Abbreviation: t.Abbreviation}
+	}
+	return n
+}
+
+func (n names) picker(ref *fs.DocumentRef) string {
+	if name, ok := n.pickers[firestore.RefID(ref)]; ok {
+		return name
+	}
+	return firestore.RefID(ref)
+}
+
+func (n names) team(ref *fs.DocumentRef) Team {
+	if t, ok := n.teams[firestore.RefID(ref)]; ok {
+		return t
+	}
+	return Team{ID: firestore.RefID(ref)}
+}
+
+func (n names) teamList(refs []*fs.DocumentRef) []Team {
+	teams := make([]Team, len(refs))
+	for i, ref := range refs {
+		teams[i] = n.team(ref)
+	}
+	return teams
+}
+
+func newSeason(s firestore.Season) Season {
+	pickers := make([]string, 0, len(s.Pickers))
+	for name := range s.Pickers {
+		pickers = append(pickers, name)
+	}
+	sort.Strings(pickers)
+	return Season{Year: s.Year, StartTime: s.StartTime, Pickers: pickers, PickLock: s.PickLock.String()}
+}
+
+func newWeek(w firestore.Week, lock firestore.PickLock) Week {
+	week := Week{Number: w.Number, FirstGameStart: w.FirstGameStart}
+	if t := lock.WeekLockTime(w); !t.IsZero() {
+		week.LockTime = &t
+	}
+	return week
+}
+
+func newSlate(year int, ws WeekSlate, lock firestore.PickLock, n names, now time.Time) Slate {
+	s := Slate{Season: year, Week: newWeek(ws.Week, lock), Games: make([]SlateGame, len(ws.SlateGames))}
+	for i, sg := range ws.SlateGames {
+		game := ws.Games[sg.Game.ID]
+		lockTime, locked := lock.Locked(ws.Week, game, now)
+		s.Games[i] = SlateGame{
+			ID:          ws.SlateRefs[i].ID,
+			Row:         sg.Row,
+			Home:        n.team(game.HomeTeam),
+			Away:        n.team(game.AwayTeam),
+			HomeRank:    sg.HomeRank,
+			AwayRank:    sg.AwayRank,
+			NeutralSite: game.NeutralSite,
+			HomeFavored: sg.HomeFavored,
+			GOTW:        sg.GOTW,
+			Superdog:    sg.Superdog,
+			Value:       sg.Value,
+			NoisySpread: sg.NoisySpread,
+			Kickoff:     game.StartTime,
+			LockTime:    lockTime,
+			Locked:      locked,
+			HomePoints:  game.HomePoints,
+			AwayPoints:  game.AwayPoints,
+		}
+	}
+	sort.SliceStable(s.Games, func(i, j int) bool { return s.Games[i].Row < s.Games[j].Row })
+	return s
+}
+
+// newPicks converts the picks made against the slate, optionally only those of one picker (by ID).
+func newPicks(ws WeekSlate, picks []firestore.Pick, pickerID string, n names) []Pick {
+	byID := make(map[string]int)
+	for i, ref := range ws.SlateRefs {
+		byID[ref.ID] = i
+	}
+	out := make([]Pick, 0, len(picks))
+	for _, p := range picks {
+		if p.SlateGame == nil || (pickerID != "" && firestore.RefID(p.Picker) != pickerID) {
+			continue
+		}
+		i, ok := byID[p.SlateGame.ID]
+		if !ok {
+			continue
+		}
+		pick := Pick{Picker: n.picker(p.Picker), Game: p.SlateGame.ID, PredictedSpread: p.PredictedSpread, PredictedProbability: p.PredictedProbability}
+		if p.PickedTeam != nil {
+			t := n.team(p.PickedTeam)
+			pick.Team = &t
+		}
+		sg := ws.SlateGames[i]
+		if points, decided := sg.Points(ws.Games[sg.Game.ID], p.PickedTeam); decided {
+			pick.Points = &points
+		}
+		out = append(out, pick)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Picker != out[j].Picker {
+			return out[i].Picker < out[j].Picker
+		}
+		return ws.SlateGames[byID[out[i].Game]].Row < ws.SlateGames[byID[out[j].Game]].Row
+	})
+	return out
+}
+
+// newStandings totals the points of the season's pickers through the results, the last of which is the week of the standings.
+func newStandings(season firestore.Season, results []firestore.WeekResult, week int) []Standing {
+	points := make(map[string]int)
+	weekPoints := make(map[string]int)
+	for _, ref := range season.Pickers {
+		points[ref.ID] = 0
+	}
+	for _, r := range results {
+		for id, score := range r.Scores {
+			if _, ok := points[id]; !ok {
+				continue
+			}
+			points[id] += score
+			if r.Week == week {
+				weekPoints[id] = score
+			}
+		}
+	}
+	ranks := recap.Rank(points)
+	s := make([]Standing, 0, len(season.Pickers))
+	for name, ref := range season.Pickers {
+		s = append(s, Standing{Picker: name, Rank: ranks[ref.ID], Points: points[ref.ID], WeekPoints: weekPoints[ref.ID]})
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].Rank != s[j].Rank {
+			return s[i].Rank < s[j].Rank
+		}
+		return s[i].Picker < s[j].Picker
+	})
+	return s
+}
+
+// newSuggestedStreak converts the most probable of the possible streaks of the predictions.
+func newSuggestedStreak(n names, sp firestore.StreakPredictions) *SuggestedStreak {
+	best := -1
+	for i, p := range sp.PossiblePicks {
+		if best < 0 || p.CumulativeProbability > sp.PossiblePicks[best].CumulativeProbability {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	p := sp.PossiblePicks[best]
+	s := &SuggestedStreak{Probability: p.CumulativeProbability, Spread: p.CumulativeSpread, Weeks: make([][]Team, len(p.Weeks))}
+	for i, w := range p.Weeks {
+		s.Weeks[i] = n.teamList(w.Pick)
+	}
+	return s
+}
+
+func newStreak(n names, remaining firestore.StreakTeamsRemaining, picked *firestore.StreakTeamsRemaining, sp *firestore.StreakPredictions) Streak {
+	s := Streak{
+		Picker:             n.picker(remaining.Picker),
+		TeamsRemaining:     n.teamList(remaining.TeamsRemaining),
+		PickTypesRemaining: remaining.PickTypesRemaining,
+		Pick:               []Team{},
+	}
+	if picked != nil {
+		s.Picked = true
+		s.Pick = n.teamList(streakDifference(remaining, *picked))
+	}
+	if sp != nil {
+		s.Suggested = newSuggestedStreak(n, *sp)
+	}
+	return s
+}
+
+// hide removes the teams picked from the streak.
+func (s *Streak) hide() {
+	s.Pick = []Team{}
+	s.Hidden = true
+}
+
+// newStreaks converts the streaks of every picker with teams remaining, in picker order. The picks of hidden pickers (by ID) are removed.
+func newStreaks(n names, ws WeekStreaks, sps map[string]firestore.StreakPredictions, hidden map[string]bool) []Streak {
+	streaks := make([]Streak, 0, len(ws.Remaining))
+	for id, remaining := range ws.Remaining {
+		var picked *firestore.StreakTeamsRemaining
+		if p, ok := ws.Picked[id]; ok {
+			picked = &p
+		}
+		var sp *firestore.StreakPredictions
+		if p, ok := sps[id]; ok {
+			sp = &p
+		}
+		s := newStreak(n, remaining, picked, sp)
+		if hidden[id] {
+			s.hide()
+		}
+		streaks = append(streaks, s)
+	}
+	sort.Slice(streaks, func(i, j int) bool { return streaks[i].Picker < streaks[j].Picker })
+	return streaks
+}
+
+// newPredictions converts the predictions of the slate games, normalizing spreads to favor the home team of each game.
+func newPredictions(ws WeekSlate, preds map[string][]firestore.ModelPrediction, model string) []GamePredictions {
+	out := make([]GamePredictions, 0, len(ws.SlateGames))
+	order := make([]int, len(ws.SlateGames))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return ws.SlateGames[order[i]].Row < ws.SlateGames[order[j]].Row })
+	for _, i := range order {
+		sg := ws.SlateGames[i]
+		game := ws.Games[sg.Game.ID]
+		gp := GamePredictions{Game: ws.SlateRefs[i].ID, Predictions: []Prediction{}}
+		for _, p := range preds[sg.Game.ID] {
+			if model != "" && firestore.RefID(p.Model) != model {
+				continue
+			}
+			spread := p.Spread
+			if firestore.RefID(p.HomeTeam) != firestore.RefID(game.HomeTeam) {
+				spread = -spread
+			}
+			gp.Predictions = append(gp.Predictions, Prediction{Model: firestore.RefID(p.Model), Spread: spread})
+		}
+		sort.Slice(gp.Predictions, func(i, j int) bool { return gp.Predictions[i].Model < gp.Predictions[j].Model })
+		out = append(out, gp)
+	}
+	return out
+}
+
+// newMissingPicks converts what the pickers are missing, with reminder messages if remind is true.
+func newMissingPicks(season firestore.Season, ws WeekSlate, missing []firestore.MissingPicks, n names, remind bool) []MissingPicks {
+	rows := make(map[string]int)
+	for i, ref := range ws.SlateRefs {
+		rows[ref.ID] = i
+	}
+	out := make([]MissingPicks, len(missing))
+	for i, m := range missing {
+		mp := MissingPicks{Picker: m.Picker, Games: make([]string, len(m.Games)), NoSuperdog: m.NoSuperdog, NoStreakPick: m.NoStreakPick}
+		games := make([]string, len(m.Games))
+		for j, ref := range m.Games {
+			mp.Games[j] = ref.ID
+			game := ws.Games[ws.SlateGames[rows[ref.ID]].Game.ID]
+			sep := "@"
+			if game.NeutralSite {
+				sep = "vs."
+			}
+			games[j] = fmt.Sprintf("%s %s %s", n.team(game.AwayTeam).School, sep, n.team(game.HomeTeam).School)
+		}
+		if remind {
+			mp.Reminder = m.Reminder(season.Year, season.PickLock, ws.Week, games)
+		}
+		out[i] = mp
+	}
+	return out
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Authenticator identifies the picker making a request from the token it carries.
+type Authenticator interface {
+	// Authenticate returns the picker the token belongs to, or a firestore.UnauthenticatedError.
+	Authenticate(ctx context.Context, token string) (firestore.Picker, error)
+}
+
+// FirestoreTokens authenticates tokens against the token hashes stored with the pickers in Firestore, so pickers keep their roles.
+type FirestoreTokens struct {
+	Client *fs.Client
+}
+
+// Authenticate implements Authenticator.
+func (f FirestoreTokens) Authenticate(ctx context.Context, token string) (firestore.Picker, error) {
+	p, _, err := firestore.Authenticate(ctx, f.Client, token)
+	return p, err
+}
+
+// StaticTokens authenticates tokens against a fixed set of token hashes (see firestore.HashToken), keyed to picker LukeNames.
+// Pickers authenticated this way have no role beyond changing their own picks.
+type StaticTokens map[string]string
+
+// Authenticate implements Authenticator.
+func (t StaticTokens) Authenticate(ctx context.Context, token string) (firestore.Picker, error) {
+	if token == "" {
+		return firestore.Picker{}, firestore.UnauthenticatedError("no token given")
+	}
+	picker, ok := t[firestore.HashToken(token)]
+	if !ok {
+		return firestore.Picker{}, firestore.UnauthenticatedError("unknown token")
+	}
+	return firestore.Picker{LukeName: picker, Role: firestore.PickerRole}, nil
+}
+
+// LoadTokens reads StaticTokens from a JSON file mapping token hashes to picker LukeNames.
+func LoadTokens(file string) (StaticTokens, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("LoadTokens: failed to read '%s': %w", file, err)
+	}
+	var t StaticTokens
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("LoadTokens: failed to parse '%s': %w", file, err)
+	}
+	return t, nil
+}
+
+// bearerToken returns the token in the request's "Authorization: Bearer" header, if any.
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	h := r.Header.Get("Authorization")
+	if len(h) < len(prefix) || !strings.EqualFold(h[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(prefix):])
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// RequestError is returned when a submission cannot be made as requested.
+type RequestError string
+
+func (e RequestError) Error() string {
+	return string(e)
+}
+
+// slateGameOf returns the index of the slate game the team plays in, or -1 if the team is not on the slate.
+func (ws WeekSlate) slateGameOf(team *fs.DocumentRef) int {
+	for i, sg := range ws.SlateGames {
+		game := ws.Games[sg.Game.ID]
+		if firestore.RefID(game.HomeTeam) == team.ID || firestore.RefID(game.AwayTeam) == team.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// games returns the week's games.
+func (ws WeekSlate) games() []firestore.Game {
+	games := make([]firestore.Game, 0, len(ws.Games))
+	for _, g := range ws.Games {
+		games = append(games, g)
+	}
+	return games
+}
+
+// planPicks resolves the team names of a submission into the team picked in each slate game it changes, keyed by slate game ID.
+// Like pickem.Pickem, every picked team must play in a slate game, and picking a superdog unpicks (nil) the picker's picks of the other superdog games.
+// The superdog must be the underdog of a superdog game, and superdog games cannot be picked as regular picks.
+// Changes to the picker's current picks of games that have locked at time now are returned as PickLockedErrors.
+func planPicks(ws WeekSlate, teams *firestore.TeamResolver, lock firestore.PickLock, current []firestore.Pick, picker *fs.DocumentRef, sub PickSubmission, now time.Time) (map[string]*fs.DocumentRef, error) {
+	picks := make(map[string]*fs.DocumentRef)
+	for _, name := range sub.Picks {
+		team, err := teams.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return nil, err
+		}
+		i := ws.slateGameOf(team)
+		if i < 0 {
+			return nil, RequestError(fmt.Sprintf("team '%s' is not playing in a slate game", name))
+		}
+		if ws.SlateGames[i].Superdog {
+			return nil, RequestError(fmt.Sprintf("team '%s' is playing in a superdog game: pick it as the superdog", name))
+		}
+		id := ws.SlateRefs[i].ID
+		if _, ok := picks[id]; ok {
+			return nil, RequestError(fmt.Sprintf("team '%s' is playing in a game that is already picked", name))
+		}
+		picks[id] = team
+	}
+
+	if sub.Superdog != "" {
+		team, err := teams.Resolve(sub.Superdog, firestore.OtherName)
+		if err != nil {
+			return nil, err
+		}
+		i := ws.slateGameOf(team)
+		if i < 0 || !ws.SlateGames[i].Superdog {
+			return nil, RequestError(fmt.Sprintf("team '%s' is not playing in a superdog game", sub.Superdog))
+		}
+		sg := ws.SlateGames[i]
+		if sg.Underdog(ws.Games[sg.Game.ID]).ID != team.ID {
+			return nil, RequestError(fmt.Sprintf("team '%s' is not the underdog of its superdog game", sub.Superdog))
+		}
+		for j, sg := range ws.SlateGames {
+			if sg.Superdog {
+				picks[ws.SlateRefs[j].ID] = nil
+			}
+		}
+		picks[ws.SlateRefs[i].ID] = team
+	}
+
+	previous := make(map[string]*fs.DocumentRef)
+	for _, p := range current {
+		if firestore.RefID(p.Picker) == picker.ID && p.SlateGame != nil {
+			previous[p.SlateGame.ID] = p.PickedTeam
+		}
+	}
+	var locked firestore.PickLockedErrors
+	for i, sg := range ws.SlateGames {
+		id := ws.SlateRefs[i].ID
+		team, ok := picks[id]
+		if !ok {
+			continue
+		}
+		before, picked := previous[id]
+		if team == nil && !picked {
+			// nothing to unpick
+			delete(picks, id)
+			continue
+		}
+		if picked && firestore.RefID(before) == firestore.RefID(team) {
+			continue
+		}
+		game := ws.Games[sg.Game.ID]
+		if t, ok := lock.Locked(ws.Week, game, now); ok {
+			locked = append(locked, firestore.PickLockedError{What: fmt.Sprintf("%s pick of %s @ %s", picker.ID, firestore.RefID(game.AwayTeam), firestore.RefID(game.HomeTeam)), Locked: t})
+		}
+	}
+	if len(locked) > 0 {
+		return nil, locked
+	}
+	return picks, nil
+}
+
+// planStreakPick resolves the team names of a Beat the Streak pick and checks the picker can make it, like btspick.MakePicks:
+// every team must play this week and be one of the picker's remaining teams, and the picker must have a pick of that many teams remaining.
+// It returns the teams and what the picker will have remaining after the pick.
+// If the pick, or the pick it replaces (the difference between remaining and picked), has locked at time now, it returns PickLockedErrors.
+func planStreakPick(ws WeekSlate, teams *firestore.TeamResolver, lock firestore.PickLock, remaining firestore.StreakTeamsRemaining, picked *firestore.StreakTeamsRemaining, sub StreakSubmission, now time.Time) ([]*fs.DocumentRef, firestore.StreakTeamsRemaining, error) {
+	after := firestore.StreakTeamsRemaining{
+		Picker:             remaining.Picker,
+		TeamsRemaining:     make([]*fs.DocumentRef, 0, len(remaining.TeamsRemaining)),
+		PickTypesRemaining: make([]int, len(remaining.PickTypesRemaining)),
+	}
+	copy(after.PickTypesRemaining, remaining.PickTypesRemaining)
+
+	refs := make([]*fs.DocumentRef, 0, len(sub.Teams))
+	pickedIDs := make(map[string]bool)
+	for _, name := range sub.Teams {
+		if name == "" {
+			continue
+		}
+		team, err := teams.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return nil, after, err
+		}
+		if !playing(ws.Games, team) {
+			return nil, after, RequestError(fmt.Sprintf("team '%s' is not playing this week", name))
+		}
+		if pickedIDs[team.ID] {
+			return nil, after, RequestError(fmt.Sprintf("team '%s' is picked more than once", name))
+		}
+		pickedIDs[team.ID] = true
+		refs = append(refs, team)
+	}
+	for _, ref := range remaining.TeamsRemaining {
+		if !pickedIDs[ref.ID] {
+			after.TeamsRemaining = append(after.TeamsRemaining, ref)
+		}
+	}
+	if len(after.TeamsRemaining) != len(remaining.TeamsRemaining)-len(refs) {
+		return nil, after, RequestError("teams picked are not all remaining in the picker's streak")
+	}
+	n := len(refs)
+	if n >= len(after.PickTypesRemaining) || after.PickTypesRemaining[n] <= 0 {
+		return nil, after, RequestError(fmt.Sprintf("no picks of %d teams remaining", n))
+	}
+	after.PickTypesRemaining[n]--
+
+	games := ws.games()
+	t, locked := lock.StreakLocked(ws.Week, games, refs, now)
+	if !locked && picked != nil {
+		t, locked = lock.StreakLocked(ws.Week, games, streakDifference(remaining, *picked), now)
+	}
+	if locked {
+		return nil, after, firestore.PickLockedErrors{{What: fmt.Sprintf("%s streak pick", firestore.RefID(remaining.Picker)), Locked: t}}
+	}
+	return refs, after, nil
+}
+
+// playing reports whether the team plays in one of the games.
+func playing(games map[string]firestore.Game, team *fs.DocumentRef) bool {
+	for _, g := range games {
+		if firestore.RefID(g.HomeTeam) == team.ID || firestore.RefID(g.AwayTeam) == team.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// streakDifference returns the teams remaining before a streak pick that are not remaining after it: the teams picked.
+func streakDifference(before, after firestore.StreakTeamsRemaining) []*fs.DocumentRef {
+	left := make(map[string]bool)
+	for _, ref := range after.TeamsRemaining {
+		left[ref.ID] = true
+	}
+	picked := make([]*fs.DocumentRef, 0)
+	for _, ref := range before.TeamsRemaining {
+		if !left[ref.ID] {
+			picked = append(picked, ref)
+		}
+	}
+	return picked
+}
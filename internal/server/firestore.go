@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/btspick"
+	"github.com/reallyasi9/b1gpickem/internal/tools/pickem"
+)
+
+// source is what the server records as the program making picks.
+const source = "b1gserver"
+
+// FirestoreStore is a Store that reads and writes the pool's data in Firestore.
+// Picks are written by the same tools the command line uses, so they are checked and logged the same way.
+type FirestoreStore struct {
+	Client *fs.Client
+}
+
+// NewFirestoreStore creates a store backed by the given Firestore client.
+func NewFirestoreStore(client *fs.Client) *FirestoreStore {
+	return &FirestoreStore{Client: client}
+}
+
+func (f *FirestoreStore) season(ctx context.Context, year int) (firestore.Season, *fs.DocumentRef, error) {
+	seasons, refs, err := firestore.GetSeasons(ctx, f.Client)
+	if err != nil {
+		return firestore.Season{}, nil, fmt.Errorf("failed to get seasons: %w", err)
+	}
+	for i, s := range seasons {
+		if s.Year == year {
+			return s, refs[i], nil
+		}
+	}
+	return firestore.Season{}, nil, seasonNotFound(year)
+}
+
+func (f *FirestoreStore) week(ctx context.Context, year, week int) (firestore.Week, *fs.DocumentRef, *fs.DocumentRef, error) {
+	_, seasonRef, err := f.season(ctx, year)
+	if err != nil {
+		return firestore.Week{}, nil, nil, err
+	}
+	w, weekRef, err := firestore.GetWeek(ctx, seasonRef, week)
+	if err != nil {
+		return w, nil, nil, err
+	}
+	return w, seasonRef, weekRef, nil
+}
+
+// Seasons implements Store.
+func (f *FirestoreStore) Seasons(ctx context.Context) ([]firestore.Season, error) {
+	seasons, _, err := firestore.GetSeasons(ctx, f.Client)
+	return seasons, err
+}
+
+// Season implements Store.
+func (f *FirestoreStore) Season(ctx context.Context, year int) (firestore.Season, error) {
+	s, _, err := f.season(ctx, year)
+	return s, err
+}
+
+// Teams implements Store.
+func (f *FirestoreStore) Teams(ctx context.Context, year int) ([]firestore.Team, []*fs.DocumentRef, error) {
+	_, seasonRef, err := f.season(ctx, year)
+	if err != nil {
+		return nil, nil, err
+	}
+	return firestore.GetTeams(ctx, seasonRef)
+}
+
+// Weeks implements Store.
+func (f *FirestoreStore) Weeks(ctx context.Context, year int) ([]firestore.Week, error) {
+	_, seasonRef, err := f.season(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+	weeks, _, err := firestore.GetWeeks(ctx, seasonRef)
+	return weeks, err
+}
+
+// Slate implements Store.
+func (f *FirestoreStore) Slate(ctx context.Context, year, week int) (WeekSlate, error) {
+	w, _, weekRef, err := f.week(ctx, year, week)
+	if err != nil {
+		return WeekSlate{}, err
+	}
+	ws := WeekSlate{Week: w, Games: make(map[string]firestore.Game)}
+	ws.SlateGames, ws.SlateRefs, err = firestore.GetSlateGames(ctx, weekRef)
+	if _, ok := err.(firestore.NoSlateError); ok {
+		ws.SlateGames, ws.SlateRefs = nil, nil
+	} else if err != nil {
+		return ws, fmt.Errorf("failed to get slate games: %w", err)
+	}
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return ws, fmt.Errorf("failed to get games: %w", err)
+	}
+	for i, ref := range gameRefs {
+		ws.Games[ref.ID] = games[i]
+	}
+	return ws, nil
+}
+
+// Picks implements Store.
+func (f *FirestoreStore) Picks(ctx context.Context, year, week int) ([]firestore.Pick, error) {
+	_, _, weekRef, err := f.week(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	picks, _, err := firestore.GetWeekPicks(ctx, weekRef)
+	return picks, err
+}
+
+// Results implements Store.
+func (f *FirestoreStore) Results(ctx context.Context, year, before int) ([]firestore.WeekResult, error) {
+	_, seasonRef, err := f.season(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+	return firestore.GetWeekResults(ctx, seasonRef, before)
+}
+
+// Streaks implements Store. What the pickers have remaining after their picks is read from the following week, where btspick.MakePicks records it.
+func (f *FirestoreStore) Streaks(ctx context.Context, year, week int) (WeekStreaks, error) {
+	_, seasonRef, weekRef, err := f.week(ctx, year, week)
+	if err != nil {
+		return WeekStreaks{}, err
+	}
+	ws := WeekStreaks{Picked: make(map[string]firestore.StreakTeamsRemaining)}
+	ws.Remaining, _, err = firestore.GetRemainingStreaks(ctx, seasonRef, weekRef)
+	if err != nil {
+		return ws, fmt.Errorf("failed to get remaining streaks: %w", err)
+	}
+	_, nextRef, err := firestore.GetWeek(ctx, seasonRef, week+1)
+	if _, ok := err.(firestore.NoWeekError); ok {
+		return ws, nil
+	}
+	if err != nil {
+		return ws, fmt.Errorf("failed to get following week: %w", err)
+	}
+	ws.Picked, _, err = firestore.GetRemainingStreaks(ctx, seasonRef, nextRef)
+	if err != nil {
+		return ws, fmt.Errorf("failed to get remaining streaks of following week: %w", err)
+	}
+	return ws, nil
+}
+
+// StreakPicks implements Store.
+func (f *FirestoreStore) StreakPicks(ctx context.Context, year, week int) ([]firestore.StreakPick, error) {
+	_, _, weekRef, err := f.week(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	picks, _, err := firestore.GetStreakPicks(ctx, weekRef)
+	return picks, err
+}
+
+// StreakPredictions implements Store.
+func (f *FirestoreStore) StreakPredictions(ctx context.Context, year, week int) (map[string]firestore.StreakPredictions, error) {
+	season, seasonRef, err := f.season(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, week)
+	if err != nil {
+		return nil, err
+	}
+	sps := make(map[string]firestore.StreakPredictions)
+	for _, picker := range season.Pickers {
+		sp, _, err := firestore.GetMostRecentStreakPrediction(ctx, weekRef, picker)
+		if _, ok := err.(firestore.NoStreakPickError); ok {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get streak predictions of picker %s: %w", picker.ID, err)
+		}
+		sps[picker.ID] = sp
+	}
+	return sps, nil
+}
+
+// Predictions implements Store.
+func (f *FirestoreStore) Predictions(ctx context.Context, year, week int) (map[string][]firestore.ModelPrediction, error) {
+	_, _, weekRef, err := f.week(ctx, year, week)
+	if err != nil {
+		return nil, err
+	}
+	sgs, _, err := firestore.GetSlateGames(ctx, weekRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slate games: %w", err)
+	}
+	preds := make(map[string][]firestore.ModelPrediction)
+	for _, sg := range sgs {
+		p, _, err := firestore.GetPredictions(ctx, f.Client, sg.Game)
+		if err != nil {
+			return nil, err
+		}
+		preds[sg.Game.ID] = p
+	}
+	return preds, nil
+}
+
+// SubmitPicks implements Store with pickem.Pickem.
+func (f *FirestoreStore) SubmitPicks(ctx context.Context, year, week int, picker string, sub PickSubmission, by firestore.Picker) error {
+	pctx := pickem.NewContext(ctx)
+	pctx.FirestoreClient = f.Client
+	pctx.Force = true
+	pctx.Source = source
+	pctx.Actor = by
+	pctx.Season = year
+	pctx.Week = week
+	pctx.Picker = picker
+	pctx.Picks = sub.Picks
+	pctx.SuperDog = sub.Superdog
+	return pickem.Pickem(pctx)
+}
+
+// SubmitStreakPick implements Store with btspick.MakePicks.
+func (f *FirestoreStore) SubmitStreakPick(ctx context.Context, year, week int, picker string, sub StreakSubmission, by firestore.Picker) error {
+	bctx := btspick.NewContext(ctx)
+	bctx.FirestoreClient = f.Client
+	bctx.Force = true
+	bctx.Actor = by
+	bctx.Season = year
+	bctx.Week = week
+	bctx.Picks = map[string]string{picker: strings.Join(sub.Teams, ",")}
+	return btspick.MakePicks(bctx)
+}
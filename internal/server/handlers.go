@@ -0,0 +1,389 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// maxBodyBytes limits the size of submissions.
+const maxBodyBytes = 1 << 16
+
+func (s *Server) seasons(req *request) (interface{}, error) {
+	seasons, err := s.store.Seasons(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Season, len(seasons))
+	for i, season := range seasons {
+		out[i] = newSeason(season)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Year < out[j].Year })
+	return out, nil
+}
+
+func (s *Server) season(req *request) (interface{}, error) {
+	season, err := s.store.Season(req.Context(), req.year)
+	if err != nil {
+		return nil, err
+	}
+	return newSeason(season), nil
+}
+
+func (s *Server) weeks(req *request) (interface{}, error) {
+	season, err := s.store.Season(req.Context(), req.year)
+	if err != nil {
+		return nil, err
+	}
+	weeks, err := s.store.Weeks(req.Context(), req.year)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Week, len(weeks))
+	for i, w := range weeks {
+		out[i] = newWeek(w, season.PickLock)
+	}
+	return out, nil
+}
+
+// seasonNames loads a season and the names of its pickers and teams.
+// If the request names a picker, the picker must be playing in the season.
+func (s *Server) seasonNames(req *request) (firestore.Season, names, error) {
+	season, err := s.store.Season(req.Context(), req.year)
+	if err != nil {
+		return season, names{}, err
+	}
+	if _, ok := season.Pickers[req.picker]; req.picker != "" && !ok {
+		return season, names{}, NotFoundError(fmt.Sprintf("picker '%s' is not playing in season %d", req.picker, req.year))
+	}
+	teams, refs, err := s.store.Teams(req.Context(), req.year)
+	if err != nil {
+		return season, names{}, err
+	}
+	return season, newNames(season, teams, refs), nil
+}
+
+// weekSlate loads a week's slate, which must exist.
+func (s *Server) weekSlate(req *request) (WeekSlate, error) {
+	ws, err := s.store.Slate(req.Context(), req.year, req.week)
+	if err != nil {
+		return ws, err
+	}
+	if len(ws.SlateGames) == 0 {
+		return ws, NotFoundError(fmt.Sprintf("week %d of season %d has no slate", req.week, req.year))
+	}
+	return ws, nil
+}
+
+func (s *Server) slate(req *request) (interface{}, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		return nil, err
+	}
+	return newSlate(req.year, ws, season.PickLock, n, s.now()), nil
+}
+
+func (s *Server) picks(req *request) (interface{}, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		return nil, err
+	}
+	picks, err := s.store.Picks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	return newPicks(ws, s.visiblePicks(req, season, ws, picks), firestore.RefID(season.Pickers[req.picker]), n), nil
+}
+
+// seesPicksOf reports whether the picker making the request may see the picks of the picker with the given ID before they lock:
+// pickers may see their own picks, and commissioners may see everyone's.
+func (req *request) seesPicksOf(season firestore.Season, pickerID string) bool {
+	if req.me.IsCommissioner() {
+		return true
+	}
+	ref, ok := season.Pickers[req.me.LukeName]
+	return ok && ref.ID == pickerID
+}
+
+// visiblePicks removes the picks the picker making the request may not see: other pickers' picks of games that have not locked.
+func (s *Server) visiblePicks(req *request, season firestore.Season, ws WeekSlate, picks []firestore.Pick) []firestore.Pick {
+	byID := make(map[string]firestore.SlateGame)
+	for i, ref := range ws.SlateRefs {
+		byID[ref.ID] = ws.SlateGames[i]
+	}
+	now := s.now()
+	visible := make([]firestore.Pick, 0, len(picks))
+	for _, p := range picks {
+		if p.Picker == nil || p.SlateGame == nil {
+			continue
+		}
+		if !req.seesPicksOf(season, p.Picker.ID) {
+			sg, ok := byID[p.SlateGame.ID]
+			if !ok {
+				continue
+			}
+			if _, locked := season.PickLock.Locked(ws.Week, ws.Games[sg.Game.ID], now); !locked {
+				continue
+			}
+		}
+		visible = append(visible, p)
+	}
+	return visible
+}
+
+// hiddenStreaks returns the IDs of the pickers whose streak picks the picker making the request may not see: other pickers' picks that have not locked.
+func (s *Server) hiddenStreaks(req *request, season firestore.Season, ws WeekSlate, streaks WeekStreaks) map[string]bool {
+	games := ws.games()
+	now := s.now()
+	hidden := make(map[string]bool)
+	for id, picked := range streaks.Picked {
+		remaining, ok := streaks.Remaining[id]
+		if !ok || req.seesPicksOf(season, id) {
+			continue
+		}
+		if _, locked := season.PickLock.StreakLocked(ws.Week, games, streakDifference(remaining, picked), now); !locked {
+			hidden[id] = true
+		}
+	}
+	return hidden
+}
+
+func (s *Server) standings(req *request) (interface{}, error) {
+	season, err := s.store.Season(req.Context(), req.year)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.store.Results(req.Context(), req.year, req.week+1)
+	if err != nil {
+		return nil, err
+	}
+	return newStandings(season, results, req.week), nil
+}
+
+func (s *Server) streaks(req *request) (interface{}, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return nil, err
+	}
+	slate, err := s.store.Slate(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.store.Streaks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	sps, err := s.store.StreakPredictions(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	hidden := s.hiddenStreaks(req, season, slate, ws)
+	if req.picker == "" {
+		return newStreaks(n, ws, sps, hidden), nil
+	}
+	picker := season.Pickers[req.picker]
+	remaining, picked, err := pickerStreak(ws, picker)
+	if err != nil {
+		return nil, NotFoundError(fmt.Sprintf("picker '%s' has no streak in week %d", req.picker, req.week))
+	}
+	streak := newStreak(n, remaining, picked, pickerPredictions(sps, picker))
+	if hidden[picker.ID] {
+		streak.hide()
+	}
+	return streak, nil
+}
+
+func (s *Server) missing(req *request) (interface{}, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		return nil, err
+	}
+	picks, err := s.store.Picks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	streaks, err := s.store.Streaks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	streakPicks, err := s.store.StreakPicks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	streakPicked := make(map[string]bool)
+	for id := range streaks.Picked {
+		streakPicked[id] = true
+	}
+	for _, sp := range streakPicks {
+		streakPicked[firestore.RefID(sp.Picker)] = true
+	}
+	missing := firestore.FindMissingPicks(season.Pickers, ws.SlateGames, ws.SlateRefs, ws.Games, picks, streaks.Remaining, streakPicked)
+	remind, _ := strconv.ParseBool(req.URL.Query().Get("remind"))
+	return newMissingPicks(season, ws, missing, n, remind), nil
+}
+
+// pickerPredictions returns the picker's streak predictions, if any.
+func pickerPredictions(sps map[string]firestore.StreakPredictions, picker *fs.DocumentRef) *firestore.StreakPredictions {
+	if sp, ok := sps[picker.ID]; ok {
+		return &sp
+	}
+	return nil
+}
+
+func (s *Server) predictions(req *request) (interface{}, error) {
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		return nil, err
+	}
+	preds, err := s.store.Predictions(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	return newPredictions(ws, preds, req.URL.Query().Get("model")), nil
+}
+
+// decode reads the JSON body of a request into v.
+func decode(req *request, v interface{}) error {
+	dec := json.NewDecoder(io.LimitReader(req.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return RequestError(fmt.Sprintf("invalid request body: %v", err))
+	}
+	return nil
+}
+
+// teamResolver loads the teams of the request's season for resolving team names.
+func (s *Server) teamResolver(req *request) (*firestore.TeamResolver, error) {
+	teams, refs, err := s.store.Teams(req.Context(), req.year)
+	if err != nil {
+		return nil, err
+	}
+	r, dupErr := firestore.NewTeamResolver(teams, refs)
+	if dupErr != nil {
+		return nil, dupErr
+	}
+	return r, nil
+}
+
+func (s *Server) submitPicks(req *request) (interface{}, error) {
+	var sub PickSubmission
+	if err := decode(req, &sub); err != nil {
+		return nil, err
+	}
+	return s.makePicks(req, sub)
+}
+
+// makePicks checks a picker's picks against the slate and the locks of the games before submitting them, and returns the picker's picks.
+func (s *Server) makePicks(req *request, sub PickSubmission) ([]Pick, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamResolver(req)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.Picks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	picker := season.Pickers[req.picker]
+	if _, err := planPicks(ws, teams, season.PickLock, current, picker, sub, s.now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.SubmitPicks(req.Context(), req.year, req.week, req.picker, sub, req.me); err != nil {
+		return nil, fmt.Errorf("failed to submit picks: %w", err)
+	}
+	picks, err := s.store.Picks(req.Context(), req.year, req.week)
+	if err != nil {
+		return nil, err
+	}
+	return newPicks(ws, picks, picker.ID, n), nil
+}
+
+func (s *Server) submitStreakPick(req *request) (interface{}, error) {
+	var sub StreakSubmission
+	if err := decode(req, &sub); err != nil {
+		return nil, err
+	}
+	return s.makeStreakPick(req, sub)
+}
+
+// makeStreakPick checks a picker's Beat the Streak pick against the picker's remaining teams and the locks of the games before submitting it, and returns the picker's streak.
+func (s *Server) makeStreakPick(req *request, sub StreakSubmission) (Streak, error) {
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		return Streak{}, err
+	}
+	teams, err := s.teamResolver(req)
+	if err != nil {
+		return Streak{}, err
+	}
+	ws, err := s.store.Slate(req.Context(), req.year, req.week)
+	if err != nil {
+		return Streak{}, err
+	}
+	streaks, err := s.store.Streaks(req.Context(), req.year, req.week)
+	if err != nil {
+		return Streak{}, err
+	}
+	picker := season.Pickers[req.picker]
+	remaining, picked, err := pickerStreak(streaks, picker)
+	if err != nil {
+		return Streak{}, err
+	}
+	if _, _, err := planStreakPick(ws, teams, season.PickLock, remaining, picked, sub, s.now()); err != nil {
+		return Streak{}, err
+	}
+
+	if err := s.store.SubmitStreakPick(req.Context(), req.year, req.week, req.picker, sub, req.me); err != nil {
+		return Streak{}, fmt.Errorf("failed to submit streak pick: %w", err)
+	}
+	streaks, err = s.store.Streaks(req.Context(), req.year, req.week)
+	if err != nil {
+		return Streak{}, err
+	}
+	remaining, picked, err = pickerStreak(streaks, picker)
+	if err != nil {
+		return Streak{}, err
+	}
+	sps, err := s.store.StreakPredictions(req.Context(), req.year, req.week)
+	if err != nil {
+		return Streak{}, err
+	}
+	return newStreak(n, remaining, picked, pickerPredictions(sps, picker)), nil
+}
+
+// pickerStreak returns the teams the picker has remaining going into the week, and going into the following week if the picker has picked.
+func pickerStreak(ws WeekStreaks, picker *fs.DocumentRef) (firestore.StreakTeamsRemaining, *firestore.StreakTeamsRemaining, error) {
+	remaining, ok := ws.Remaining[picker.ID]
+	if !ok {
+		return remaining, nil, RequestError(fmt.Sprintf("picker %s has no streak to pick", picker.ID))
+	}
+	if p, ok := ws.Picked[picker.ID]; ok {
+		return remaining, &p, nil
+	}
+	return remaining, nil, nil
+}
@@ -0,0 +1,400 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// MemoryStore is a Store that keeps the pool's data in memory, standing in for Firestore in tests and local development.
+// Document references are made with IDs and paths but without a client, so they cannot be used to read from Firestore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	seasons map[int]*memorySeason
+}
+
+type memorySeason struct {
+	season   firestore.Season
+	teams    []firestore.Team
+	teamRefs []*fs.DocumentRef
+	weeks    map[int]*memoryWeek
+}
+
+type memoryWeek struct {
+	week        firestore.Week
+	slate       WeekSlate
+	picks       []firestore.Pick
+	remaining   map[string]firestore.StreakTeamsRemaining
+	streakPicks []firestore.StreakPick
+	streakPreds map[string]firestore.StreakPredictions
+	predictions map[string][]firestore.ModelPrediction
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{seasons: make(map[int]*memorySeason)}
+}
+
+// AddSeason adds a season and its teams.
+func (m *MemoryStore) AddSeason(season firestore.Season, teams []firestore.Team, refs []*fs.DocumentRef) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seasons[season.Year] = &memorySeason{season: season, teams: teams, teamRefs: refs, weeks: make(map[int]*memoryWeek)}
+}
+
+// AddWeek adds a week to a season, along with the games played in it.
+func (m *MemoryStore) AddWeek(year int, week firestore.Week, games map[string]firestore.Game) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return seasonNotFound(year)
+	}
+	s.weeks[week.Number] = &memoryWeek{
+		week:        week,
+		slate:       WeekSlate{Week: week, Games: games},
+		remaining:   make(map[string]firestore.StreakTeamsRemaining),
+		streakPreds: make(map[string]firestore.StreakPredictions),
+		predictions: make(map[string][]firestore.ModelPrediction),
+	}
+	return nil
+}
+
+// SetSlate sets the slate of a week.
+func (m *MemoryStore) SetSlate(year, week int, sgs []firestore.SlateGame, refs []*fs.DocumentRef) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.slate.SlateGames = sgs
+	w.slate.SlateRefs = refs
+	return nil
+}
+
+// AddPicks adds picks to a week.
+func (m *MemoryStore) AddPicks(year, week int, picks ...firestore.Pick) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.picks = append(w.picks, picks...)
+	return nil
+}
+
+// SetStreakTeamsRemaining sets the Beat the Streak teams a picker has remaining going into a week.
+func (m *MemoryStore) SetStreakTeamsRemaining(year, week int, str firestore.StreakTeamsRemaining) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.remaining[str.Picker.ID] = str
+	return nil
+}
+
+// AddStreakPicks adds Beat the Streak picks to a week, as pickem4me records them.
+func (m *MemoryStore) AddStreakPicks(year, week int, picks ...firestore.StreakPick) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.streakPicks = append(w.streakPicks, picks...)
+	return nil
+}
+
+// SetStreakPredictions sets the Beat the Streak predictions of a picker in a week.
+func (m *MemoryStore) SetStreakPredictions(year, week int, sp firestore.StreakPredictions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.streakPreds[sp.Picker.ID] = sp
+	return nil
+}
+
+// AddPredictions adds model predictions of a game in a week.
+func (m *MemoryStore) AddPredictions(year, week int, game string, preds ...firestore.ModelPrediction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	w.predictions[game] = append(w.predictions[game], preds...)
+	return nil
+}
+
+// week returns a week of a season. The caller must hold the lock.
+func (m *MemoryStore) week(year, week int) (*memoryWeek, error) {
+	s, ok := m.seasons[year]
+	if !ok {
+		return nil, seasonNotFound(year)
+	}
+	w, ok := s.weeks[week]
+	if !ok {
+		return nil, firestore.NoWeekError(week)
+	}
+	return w, nil
+}
+
+// Seasons implements Store.
+func (m *MemoryStore) Seasons(ctx context.Context) ([]firestore.Season, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seasons := make([]firestore.Season, 0, len(m.seasons))
+	for _, s := range m.seasons {
+		seasons = append(seasons, s.season)
+	}
+	sort.Slice(seasons, func(i, j int) bool { return seasons[i].Year < seasons[j].Year })
+	return seasons, nil
+}
+
+// Season implements Store.
+func (m *MemoryStore) Season(ctx context.Context, year int) (firestore.Season, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return firestore.Season{}, seasonNotFound(year)
+	}
+	return s.season, nil
+}
+
+// Teams implements Store.
+func (m *MemoryStore) Teams(ctx context.Context, year int) ([]firestore.Team, []*fs.DocumentRef, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return nil, nil, seasonNotFound(year)
+	}
+	return s.teams, s.teamRefs, nil
+}
+
+// Weeks implements Store.
+func (m *MemoryStore) Weeks(ctx context.Context, year int) ([]firestore.Week, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return nil, seasonNotFound(year)
+	}
+	weeks := make([]firestore.Week, 0, len(s.weeks))
+	for _, w := range s.weeks {
+		weeks = append(weeks, w.week)
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Number < weeks[j].Number })
+	return weeks, nil
+}
+
+// Slate implements Store.
+func (m *MemoryStore) Slate(ctx context.Context, year, week int) (WeekSlate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return WeekSlate{}, err
+	}
+	return w.slate, nil
+}
+
+// Picks implements Store.
+func (m *MemoryStore) Picks(ctx context.Context, year, week int) ([]firestore.Pick, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return nil, err
+	}
+	picks := make([]firestore.Pick, len(w.picks))
+	copy(picks, w.picks)
+	return picks, nil
+}
+
+// Results implements Store.
+func (m *MemoryStore) Results(ctx context.Context, year, before int) ([]firestore.WeekResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return nil, seasonNotFound(year)
+	}
+	results := make([]firestore.WeekResult, 0, len(s.weeks))
+	for n, w := range s.weeks {
+		if n >= before || len(w.slate.SlateGames) == 0 {
+			continue
+		}
+		result, err := firestore.ScoreWeek(w.slate.SlateGames, w.slate.SlateRefs, w.slate.Games, w.picks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score week %d: %w", n, err)
+		}
+		result.Week = n
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Week < results[j].Week })
+	return results, nil
+}
+
+// Streaks implements Store.
+func (m *MemoryStore) Streaks(ctx context.Context, year, week int) (WeekStreaks, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return WeekStreaks{}, err
+	}
+	ws := WeekStreaks{Remaining: copyStreaks(w.remaining), Picked: make(map[string]firestore.StreakTeamsRemaining)}
+	if next, err := m.week(year, week+1); err == nil {
+		ws.Picked = copyStreaks(next.remaining)
+	}
+	return ws, nil
+}
+
+func copyStreaks(strs map[string]firestore.StreakTeamsRemaining) map[string]firestore.StreakTeamsRemaining {
+	c := make(map[string]firestore.StreakTeamsRemaining, len(strs))
+	for id, str := range strs {
+		c[id] = str
+	}
+	return c
+}
+
+// StreakPicks implements Store.
+func (m *MemoryStore) StreakPicks(ctx context.Context, year, week int) ([]firestore.StreakPick, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return nil, err
+	}
+	picks := make([]firestore.StreakPick, len(w.streakPicks))
+	copy(picks, w.streakPicks)
+	return picks, nil
+}
+
+// StreakPredictions implements Store.
+func (m *MemoryStore) StreakPredictions(ctx context.Context, year, week int) (map[string]firestore.StreakPredictions, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return nil, err
+	}
+	sps := make(map[string]firestore.StreakPredictions, len(w.streakPreds))
+	for id, sp := range w.streakPreds {
+		sps[id] = sp
+	}
+	return sps, nil
+}
+
+// Predictions implements Store.
+func (m *MemoryStore) Predictions(ctx context.Context, year, week int) (map[string][]firestore.ModelPrediction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, err := m.week(year, week)
+	if err != nil {
+		return nil, err
+	}
+	preds := make(map[string][]firestore.ModelPrediction, len(w.predictions))
+	for id, p := range w.predictions {
+		preds[id] = p
+	}
+	return preds, nil
+}
+
+// SubmitPicks implements Store. The picks are checked the same way the server checks them.
+func (m *MemoryStore) SubmitPicks(ctx context.Context, year, week int, picker string, sub PickSubmission, by firestore.Picker) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return seasonNotFound(year)
+	}
+	pickerRef, ok := s.season.Pickers[picker]
+	if !ok {
+		return NotFoundError(fmt.Sprintf("picker '%s' is not playing in season %d", picker, year))
+	}
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	teams, dupErr := firestore.NewTeamResolver(s.teams, s.teamRefs)
+	if dupErr != nil {
+		return dupErr
+	}
+	picks, err := planPicks(w.slate, teams, s.season.PickLock, w.picks, pickerRef, sub, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i, ref := range w.slate.SlateRefs {
+		team, ok := picks[ref.ID]
+		if !ok {
+			continue
+		}
+		found := false
+		for j, p := range w.picks {
+			if firestore.RefID(p.Picker) == pickerRef.ID && firestore.RefID(p.SlateGame) == ref.ID {
+				w.picks[j] = firestore.Pick{SlateGame: ref, PickedTeam: team, Picker: pickerRef, Timestamp: time.Now()}
+				found = true
+			}
+		}
+		if !found {
+			w.picks = append(w.picks, firestore.Pick{SlateGame: w.slate.SlateRefs[i], PickedTeam: team, Picker: pickerRef, Timestamp: time.Now()})
+		}
+	}
+	return nil
+}
+
+// SubmitStreakPick implements Store. Like btspick.MakePicks, it records the pick as the teams the picker has remaining going into the following week, which must exist.
+func (m *MemoryStore) SubmitStreakPick(ctx context.Context, year, week int, picker string, sub StreakSubmission, by firestore.Picker) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.seasons[year]
+	if !ok {
+		return seasonNotFound(year)
+	}
+	pickerRef, ok := s.season.Pickers[picker]
+	if !ok {
+		return NotFoundError(fmt.Sprintf("picker '%s' is not playing in season %d", picker, year))
+	}
+	w, err := m.week(year, week)
+	if err != nil {
+		return err
+	}
+	next, err := m.week(year, week+1)
+	if err != nil {
+		return fmt.Errorf("failed to get following week: %w", err)
+	}
+	remaining, ok := w.remaining[pickerRef.ID]
+	if !ok {
+		return RequestError(fmt.Sprintf("picker %s has no streak to pick", pickerRef.ID))
+	}
+	var picked *firestore.StreakTeamsRemaining
+	if p, ok := next.remaining[pickerRef.ID]; ok {
+		picked = &p
+	}
+	teams, dupErr := firestore.NewTeamResolver(s.teams, s.teamRefs)
+	if dupErr != nil {
+		return dupErr
+	}
+	_, after, err := planStreakPick(w.slate, teams, s.season.PickLock, remaining, picked, sub, time.Now())
+	if err != nil {
+		return err
+	}
+	next.remaining[pickerRef.ID] = after
+	return nil
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// APIPrefix is the path under which the JSON API is served.
+const APIPrefix = "/api/"
+
+// Server serves the pool's data as JSON over HTTP.
+//
+//	GET /api/seasons
+//	GET /api/seasons/{year}
+//	GET /api/seasons/{year}/weeks
+//	GET /api/seasons/{year}/weeks/{week}/slate
+//	GET /api/seasons/{year}/weeks/{week}/picks
+//	GET /api/seasons/{year}/weeks/{week}/picks/{picker}
+//	PUT /api/seasons/{year}/weeks/{week}/picks/{picker}   (PickSubmission)
+//	GET /api/seasons/{year}/weeks/{week}/standings
+//	GET /api/seasons/{year}/weeks/{week}/streaks
+//	GET /api/seasons/{year}/weeks/{week}/streaks/{picker}
+//	PUT /api/seasons/{year}/weeks/{week}/streaks/{picker} (StreakSubmission)
+//	GET /api/seasons/{year}/weeks/{week}/predictions[?model={model}]
+//	GET /api/seasons/{year}/weeks/{week}/missing[?remind=true]
+//
+// Requests that change picks must carry the picker's token, or a commissioner's, in an "Authorization: Bearer" header.
+// Other pickers' picks and streak picks are hidden until they lock; requests for them may carry a token so that pickers see their own and commissioners see everyone's.
+// The same data is served to people as a web UI, where pickers sign in with their token (see serveWeb).
+type Server struct {
+	store Store
+	auth  Authenticator
+
+	// now is the clock used to check pick locks.
+	now func() time.Time
+}
+
+// New creates a server of the data in store that authenticates pickers with auth.
+func New(store Store, auth Authenticator) *Server {
+	return &Server{store: store, auth: auth, now: time.Now}
+}
+
+// Handler returns an http.Handler that serves the API under APIPrefix and the web UI everywhere else.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(APIPrefix, s)
+	mux.HandleFunc("/", s.serveWeb)
+	return mux
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, APIPrefix), "/")
+	parts := strings.Split(path, "/")
+	if len(parts) == 0 || parts[0] != "seasons" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint %s", r.URL.Path))
+		return
+	}
+
+	req := &request{Request: r}
+	if len(parts) == 1 {
+		s.get(w, req, s.seasons)
+		return
+	}
+	var err error
+	if req.year, err = strconv.Atoi(parts[1]); err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid season '%s'", parts[1]))
+		return
+	}
+	switch {
+	case len(parts) == 2:
+		s.get(w, req, s.season)
+		return
+	case len(parts) == 3 && parts[2] == "weeks":
+		s.get(w, req, s.weeks)
+		return
+	case len(parts) < 5 || parts[2] != "weeks":
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint %s", r.URL.Path))
+		return
+	}
+	if req.week, err = strconv.Atoi(parts[3]); err != nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid week '%s'", parts[3]))
+		return
+	}
+	if len(parts) == 6 {
+		req.picker = parts[5]
+	}
+
+	switch {
+	case len(parts) == 5 && parts[4] == "slate":
+		s.get(w, req, s.slate)
+	case len(parts) == 5 && parts[4] == "standings":
+		s.get(w, req, s.standings)
+	case len(parts) == 5 && parts[4] == "predictions":
+		s.get(w, req, s.predictions)
+	case len(parts) == 5 && parts[4] == "missing":
+		s.get(w, req, s.missing)
+	case parts[4] == "picks" && len(parts) <= 6:
+		if r.Method == http.MethodPut || r.Method == http.MethodPost {
+			s.put(w, req, s.submitPicks)
+			return
+		}
+		s.get(w, req, s.picks)
+	case parts[4] == "streaks" && len(parts) <= 6:
+		if r.Method == http.MethodPut || r.Method == http.MethodPost {
+			s.put(w, req, s.submitStreakPick)
+			return
+		}
+		s.get(w, req, s.streaks)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint %s", r.URL.Path))
+	}
+}
+
+// request is an API request for the data of a season, week, or picker identified by its path.
+type request struct {
+	*http.Request
+	year   int
+	week   int
+	picker string
+
+	// me is the authenticated picker making the request, if any.
+	me firestore.Picker
+}
+
+// get handles a request for data. A token is optional: pickers who give one can see their own picks before they lock.
+func (s *Server) get(w http.ResponseWriter, req *request, handle func(*request) (interface{}, error)) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+	if token := bearerToken(req.Request); token != "" {
+		me, err := s.auth.Authenticate(req.Context(), token)
+		if err != nil {
+			writeError(w, errorStatus(err), err)
+			return
+		}
+		req.me = me
+	}
+	v, err := handle(req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// put authenticates the picker making the request before handling it. Pickers can only change their own picks.
+func (s *Server) put(w http.ResponseWriter, req *request, handle func(*request) (interface{}, error)) {
+	if req.picker == "" {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+	me, err := s.auth.Authenticate(req.Context(), bearerToken(req.Request))
+	if err == nil {
+		err = me.CheckPicksOf(req.picker)
+	}
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	req.me = me
+	v, err := handle(req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// errorStatus returns the HTTP status of an error returned while handling a request.
+func errorStatus(err error) int {
+	var notFound NotFoundError
+	var noWeek firestore.NoWeekError
+	var noPicker firestore.PickerNotFound
+	var bad RequestError
+	var noName firestore.NameNotFoundError
+	var locked firestore.PickLockedErrors
+	var unauthenticated firestore.UnauthenticatedError
+	var forbidden firestore.ForbiddenError
+	switch {
+	case errors.As(err, &unauthenticated):
+		return http.StatusUnauthorized
+	case errors.As(err, &forbidden):
+		return http.StatusForbidden
+	case errors.As(err, &notFound), errors.As(err, &noWeek), errors.As(err, &noPicker):
+		return http.StatusNotFound
+	case errors.As(err, &bad), errors.As(err, &noName):
+		return http.StatusBadRequest
+	case errors.As(err, &locked):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	if status == http.StatusInternalServerError {
+		log.Print(err)
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
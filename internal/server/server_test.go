@@ -0,0 +1,415 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func ref(coll, id string) *fs.DocumentRef {
+	return &fs.DocumentRef{ID: id, Path: coll + "/" + id}
+}
+
+// testServer serves a season of six teams and two pickers over three weeks:
+// week 1 is decided, week 2 has one game that has kicked off and two that have not, and week 3 has not been set up.
+func testServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	now := time.Now()
+	teamNames := map[string]string{"mich": "Michigan", "osu": "Ohio State", "iowa": "Iowa", "msu": "Michigan State", "psu": "Penn State", "nw": "Northwestern"}
+	ids := []string{"mich", "osu", "iowa", "msu", "psu", "nw"}
+	teams := make([]firestore.Team, len(ids))
+	teamRefs := make([]*fs.DocumentRef, len(ids))
+	tr := make(map[string]*fs.DocumentRef)
+	for i, id := range ids {
+		teams[i] = firestore.Team{School: teamNames[id], OtherNames: []string{teamNames[id]}, ShortNames: []string{id}}
+		teamRefs[i] = ref("teams", id)
+		tr[id] = teamRefs[i]
+	}
+	alice, bob := ref("pickers", "alice"), ref("pickers", "bob")
+
+	m := NewMemoryStore()
+	m.AddSeason(firestore.Season{Year: 2023, Pickers: map[string]*fs.DocumentRef{"Alice": alice, "Bob": bob}}, teams, teamRefs)
+
+	h1, a1, h2, a2 := 28, 21, 10, 17
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(m.AddWeek(2023, firestore.Week{Number: 1, FirstGameStart: now.Add(-7 * 24 * time.Hour)}, map[string]firestore.Game{
+		"g1": {HomeTeam: tr["mich"], AwayTeam: tr["osu"], StartTime: now.Add(-7 * 24 * time.Hour), HomePoints: &h1, AwayPoints: &a1},
+		"g2": {HomeTeam: tr["iowa"], AwayTeam: tr["msu"], StartTime: now.Add(-7 * 24 * time.Hour), HomePoints: &h2, AwayPoints: &a2},
+	}))
+	sg1, sg2 := ref("w1/games", "sg1"), ref("w1/games", "sg2")
+	must(m.SetSlate(2023, 1, []firestore.SlateGame{
+		{Row: 1, Game: ref("games", "g1"), Value: 1, HomeFavored: true},
+		{Row: 2, Game: ref("games", "g2"), Value: 5, HomeFavored: true, Superdog: true},
+	}, []*fs.DocumentRef{sg1, sg2}))
+	must(m.AddPicks(2023, 1,
+		firestore.Pick{SlateGame: sg1, PickedTeam: tr["osu"], Picker: alice},
+		firestore.Pick{SlateGame: sg1, PickedTeam: tr["mich"], Picker: bob},
+		firestore.Pick{SlateGame: sg2, PickedTeam: tr["msu"], Picker: bob},
+	))
+
+	must(m.AddWeek(2023, firestore.Week{Number: 2, FirstGameStart: now.Add(-time.Hour)}, map[string]firestore.Game{
+		"g3": {HomeTeam: tr["mich"], AwayTeam: tr["osu"], StartTime: now.Add(24 * time.Hour)},
+		"g4": {HomeTeam: tr["iowa"], AwayTeam: tr["msu"], StartTime: now.Add(-time.Hour)},
+		"g5": {HomeTeam: tr["psu"], AwayTeam: tr["nw"], StartTime: now.Add(24 * time.Hour)},
+	}))
+	sg3, sg4, sg5 := ref("w2/games", "sg3"), ref("w2/games", "sg4"), ref("w2/games", "sg5")
+	must(m.SetSlate(2023, 2, []firestore.SlateGame{
+		{Row: 1, Game: ref("games", "g3"), Value: 1, HomeFavored: true},
+		{Row: 2, Game: ref("games", "g4"), Value: 1, HomeFavored: false},
+		{Row: 3, Game: ref("games", "g5"), Value: 3, HomeFavored: true, Superdog: true},
+	}, []*fs.DocumentRef{sg3, sg4, sg5}))
+	must(m.AddPicks(2023, 2, firestore.Pick{SlateGame: sg4, PickedTeam: tr["iowa"], Picker: alice}))
+	must(m.SetStreakTeamsRemaining(2023, 2, firestore.StreakTeamsRemaining{
+		Picker:             alice,
+		TeamsRemaining:     []*fs.DocumentRef{tr["mich"], tr["osu"], tr["msu"], tr["psu"]},
+		PickTypesRemaining: []int{1, 2, 1},
+	}))
+	must(m.SetStreakPredictions(2023, 2, firestore.StreakPredictions{
+		Picker: alice,
+		PossiblePicks: []firestore.StreakPrediction{
+			{CumulativeProbability: 0.4, CumulativeSpread: 10, Weeks: []firestore.StreakWeek{{Pick: []*fs.DocumentRef{tr["psu"]}}}},
+			{CumulativeProbability: 0.6, CumulativeSpread: 12, Weeks: []firestore.StreakWeek{{Pick: []*fs.DocumentRef{tr["mich"]}}, {Pick: []*fs.DocumentRef{tr["osu"], tr["psu"]}}}},
+		},
+	}))
+	must(m.AddPredictions(2023, 2, "g3",
+		firestore.ModelPrediction{Model: ref("models", "linesag"), HomeTeam: tr["mich"], AwayTeam: tr["osu"], Spread: 3.5},
+		firestore.ModelPrediction{Model: ref("models", "linefpi"), HomeTeam: tr["osu"], AwayTeam: tr["mich"], Spread: 1},
+	))
+	must(m.AddWeek(2023, firestore.Week{Number: 3, FirstGameStart: now.Add(7 * 24 * time.Hour)}, map[string]firestore.Game{}))
+
+	tokens := testTokens{
+		"alice-token":   {LukeName: "Alice", Role: firestore.PickerRole},
+		"bob-token":     {LukeName: "Bob"},
+		"commish-token": {LukeName: "Commish", Role: firestore.CommissionerRole},
+	}
+	srv := httptest.NewServer(New(m, tokens).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testTokens authenticates pickers by their unhashed tokens.
+type testTokens map[string]firestore.Picker
+
+func (t testTokens) Authenticate(ctx context.Context, token string) (firestore.Picker, error) {
+	p, ok := t[token]
+	if !ok {
+		return p, firestore.UnauthenticatedError("unknown token")
+	}
+	return p, nil
+}
+
+// call makes a request of the server and decodes the JSON response into v, returning the status.
+func call(t *testing.T, srv *httptest.Server, method, path, token string, body interface{}, v interface{}) int {
+	t.Helper()
+	var b bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&b).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := http.NewRequest(method, srv.URL+path, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil && resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestReadEndpoints(t *testing.T) {
+	srv := testServer(t)
+
+	var seasons []Season
+	if s := call(t, srv, http.MethodGet, "/api/seasons", "", nil, &seasons); s != http.StatusOK {
+		t.Fatalf("seasons: status %d", s)
+	}
+	if len(seasons) != 1 || seasons[0].Year != 2023 || len(seasons[0].Pickers) != 2 || seasons[0].Pickers[0] != "Alice" {
+		t.Errorf("unexpected seasons %+v", seasons)
+	}
+
+	var weeks []Week
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks", "", nil, &weeks); s != http.StatusOK || len(weeks) != 3 {
+		t.Errorf("weeks: status %d, %+v", s, weeks)
+	}
+
+	var slate Slate
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/2/slate", "", nil, &slate); s != http.StatusOK {
+		t.Fatalf("slate: status %d", s)
+	}
+	if len(slate.Games) != 3 || slate.Games[0].Home.School != "Michigan" || slate.Games[0].Locked || !slate.Games[1].Locked || !slate.Games[2].Superdog {
+		t.Errorf("unexpected slate %+v", slate)
+	}
+
+	var standings []Standing
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/1/standings", "", nil, &standings); s != http.StatusOK {
+		t.Fatalf("standings: status %d", s)
+	}
+	want := []Standing{{Picker: "Bob", Rank: 1, Points: 6, WeekPoints: 6}, {Picker: "Alice", Rank: 2}}
+	if len(standings) != 2 || standings[0] != want[0] || standings[1] != want[1] {
+		t.Errorf("expected standings %v, got %v", want, standings)
+	}
+
+	var picks []Pick
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/1/picks/Bob", "", nil, &picks); s != http.StatusOK {
+		t.Fatalf("picks: status %d", s)
+	}
+	if len(picks) != 2 || picks[0].Team.ID != "mich" || picks[0].Points == nil || *picks[0].Points != 1 || *picks[1].Points != 5 {
+		t.Errorf("unexpected picks %+v", picks)
+	}
+
+	var preds []GamePredictions
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/2/predictions", "", nil, &preds); s != http.StatusOK {
+		t.Fatalf("predictions: status %d", s)
+	}
+	if len(preds) != 3 || len(preds[0].Predictions) != 2 || preds[0].Predictions[0] != (Prediction{Model: "linefpi", Spread: -1}) {
+		t.Errorf("expected spreads favoring the home team, got %+v", preds)
+	}
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/2/predictions?model=linesag", "", nil, &preds); s != http.StatusOK || len(preds[0].Predictions) != 1 {
+		t.Errorf("expected one model's predictions, got status %d, %+v", s, preds)
+	}
+
+	var streaks []Streak
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/2/streaks", "", nil, &streaks); s != http.StatusOK {
+		t.Fatalf("streaks: status %d", s)
+	}
+	if len(streaks) != 1 || streaks[0].Picker != "Alice" || streaks[0].Picked || len(streaks[0].TeamsRemaining) != 4 {
+		t.Errorf("unexpected streaks %+v", streaks)
+	}
+	if sug := streaks[0].Suggested; sug == nil || sug.Probability != 0.6 || len(sug.Weeks) != 2 || sug.Weeks[0][0].ID != "mich" {
+		t.Errorf("expected the most probable streak to be suggested, got %+v", sug)
+	}
+
+	for _, path := range []string{"/api/seasons/1999", "/api/seasons/2023/weeks/9/slate", "/api/seasons/2023/weeks/3/slate", "/api/seasons/2023/weeks/1/picks/Carol", "/api/teams"} {
+		if s := call(t, srv, http.MethodGet, path, "", nil, nil); s != http.StatusNotFound {
+			t.Errorf("%s: expected status 404, got %d", path, s)
+		}
+	}
+}
+
+func TestSubmitPicks(t *testing.T) {
+	srv := testServer(t)
+	path := "/api/seasons/2023/weeks/2/picks/Alice"
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		sub   PickSubmission
+		want  int
+	}{
+		{"no token", "", PickSubmission{Picks: []string{"Ohio State"}}, http.StatusUnauthorized},
+		{"unknown token", "carol-token", PickSubmission{Picks: []string{"Ohio State"}}, http.StatusUnauthorized},
+		{"other picker", "bob-token", PickSubmission{Picks: []string{"Ohio State"}}, http.StatusForbidden},
+		{"unknown team", "alice-token", PickSubmission{Picks: []string{"Notre Dame"}}, http.StatusBadRequest},
+		{"superdog as pick", "alice-token", PickSubmission{Picks: []string{"Northwestern"}}, http.StatusBadRequest},
+		{"favored superdog", "alice-token", PickSubmission{Superdog: "Penn State"}, http.StatusBadRequest},
+		{"locked game", "alice-token", PickSubmission{Picks: []string{"Michigan State"}}, http.StatusConflict},
+	} {
+		if s := call(t, srv, http.MethodPut, path, tc.token, tc.sub, nil); s != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.want, s)
+		}
+	}
+
+	// repeating the pick of a locked game does not change it
+	var picks []Pick
+	sub := PickSubmission{Picks: []string{"ohio st.", "Iowa"}, Superdog: "Northwestern"}
+	if s := call(t, srv, http.MethodPut, path, "alice-token", sub, &picks); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(picks) != 3 || picks[0].Team.ID != "osu" || picks[1].Team.ID != "iowa" || picks[2].Team.ID != "nw" {
+		t.Errorf("unexpected picks %+v", picks)
+	}
+
+	sub = PickSubmission{Picks: []string{"Michigan"}}
+	if s := call(t, srv, http.MethodPut, path, "alice-token", sub, &picks); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(picks) != 3 || picks[0].Team.ID != "mich" {
+		t.Errorf("expected pick to change, got %+v", picks)
+	}
+
+	// commissioners can change anyone's picks
+	sub = PickSubmission{Picks: []string{"Ohio State"}}
+	if s := call(t, srv, http.MethodPut, path, "commish-token", sub, &picks); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(picks) != 3 || picks[0].Team.ID != "osu" {
+		t.Errorf("expected commissioner to change pick, got %+v", picks)
+	}
+}
+
+func TestHiddenPicks(t *testing.T) {
+	srv := testServer(t)
+	path := "/api/seasons/2023/weeks/2/picks"
+	if s := call(t, srv, http.MethodPut, path+"/Alice", "alice-token", PickSubmission{Picks: []string{"Michigan", "Iowa"}}, nil); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+
+	// only the pick of the game that has kicked off is shown to others
+	for _, tc := range []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"no token", "", 1},
+		{"other picker", "bob-token", 1},
+		{"same picker", "alice-token", 2},
+		{"commissioner", "commish-token", 2},
+	} {
+		var picks []Pick
+		if s := call(t, srv, http.MethodGet, path, tc.token, nil, &picks); s != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tc.name, s)
+		}
+		if len(picks) != tc.want || picks[len(picks)-1].Team.ID != "iowa" {
+			t.Errorf("%s: expected %d picks, got %+v", tc.name, tc.want, picks)
+		}
+	}
+	if s := call(t, srv, http.MethodGet, path, "carol-token", nil, nil); s != http.StatusUnauthorized {
+		t.Errorf("expected status 401 for an unknown token, got %d", s)
+	}
+
+	path = "/api/seasons/2023/weeks/2/streaks/Alice"
+	if s := call(t, srv, http.MethodPut, path, "alice-token", StreakSubmission{Teams: []string{"Michigan"}}, nil); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	var streak Streak
+	if s := call(t, srv, http.MethodGet, path, "bob-token", nil, &streak); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if !streak.Picked || !streak.Hidden || len(streak.Pick) != 0 {
+		t.Errorf("expected the streak pick to be hidden from others, got %+v", streak)
+	}
+	var streaks []Streak
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/2/streaks", "alice-token", nil, &streaks); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(streaks) != 1 || streaks[0].Hidden || len(streaks[0].Pick) != 1 || streaks[0].Pick[0].ID != "mich" {
+		t.Errorf("expected the picker to see the streak pick, got %+v", streaks)
+	}
+}
+
+func TestStaticTokens(t *testing.T) {
+	tokens := StaticTokens{firestore.HashToken("alice-token"): "Alice"}
+	p, err := tokens.Authenticate(context.Background(), "alice-token")
+	if err != nil || p.LukeName != "Alice" || p.IsCommissioner() {
+		t.Errorf("expected Alice as a plain picker, got %+v, %v", p, err)
+	}
+	for _, token := range []string{"", "bob-token"} {
+		if _, err := tokens.Authenticate(context.Background(), token); !errors.As(err, new(firestore.UnauthenticatedError)) {
+			t.Errorf("token '%s': expected UnauthenticatedError, got %v", token, err)
+		}
+	}
+}
+
+func TestSubmitStreakPick(t *testing.T) {
+	srv := testServer(t)
+	path := "/api/seasons/2023/weeks/2/streaks/Alice"
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		teams []string
+		want  int
+	}{
+		{"other picker", "bob-token", []string{"Michigan"}, http.StatusForbidden},
+		{"not remaining", "alice-token", []string{"Iowa"}, http.StatusBadRequest},
+		{"not playing", "alice-token", []string{"Notre Dame"}, http.StatusBadRequest},
+		{"no picks of type", "alice-token", []string{"Michigan", "Ohio State", "Penn State"}, http.StatusBadRequest},
+		{"locked game", "alice-token", []string{"Michigan State"}, http.StatusConflict},
+	} {
+		if s := call(t, srv, http.MethodPut, path, tc.token, StreakSubmission{Teams: tc.teams}, nil); s != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.name, tc.want, s)
+		}
+	}
+
+	var streak Streak
+	if s := call(t, srv, http.MethodPut, path, "alice-token", StreakSubmission{Teams: []string{"Michigan"}}, &streak); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if !streak.Picked || len(streak.Pick) != 1 || streak.Pick[0].ID != "mich" {
+		t.Errorf("expected Michigan to be picked, got %+v", streak)
+	}
+
+	// changing the pick starts again from the teams remaining going into the week
+	if s := call(t, srv, http.MethodPut, path, "alice-token", StreakSubmission{Teams: []string{"Ohio State", "Penn State"}}, &streak); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(streak.Pick) != 2 || streak.Pick[0].ID != "osu" || streak.Pick[1].ID != "psu" {
+		t.Errorf("expected Ohio State and Penn State to be picked, got %+v", streak)
+	}
+
+	var picked Streak
+	if s := call(t, srv, http.MethodGet, "/api/seasons/2023/weeks/3/streaks/Alice", "", nil, &picked); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(picked.TeamsRemaining) != 2 || picked.PickTypesRemaining[2] != 0 || picked.PickTypesRemaining[1] != 2 {
+		t.Errorf("unexpected streak going into week 3: %+v", picked)
+	}
+
+	// a bye locks when the week's first game kicks off
+	if s := call(t, srv, http.MethodPut, path, "alice-token", StreakSubmission{}, nil); s != http.StatusConflict {
+		t.Errorf("expected a bye after the first game to be locked, got status %d", s)
+	}
+}
+
+func TestMissingPicks(t *testing.T) {
+	srv := testServer(t)
+	path := "/api/seasons/2023/weeks/2/missing"
+
+	var missing []MissingPicks
+	if s := call(t, srv, http.MethodGet, path+"?remind=true", "", nil, &missing); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(missing) != 2 {
+		t.Fatalf("expected Alice and Bob to be missing picks, got %+v", missing)
+	}
+	alice, bob := missing[0], missing[1]
+	if alice.Picker != "Alice" || len(alice.Games) != 1 || alice.Games[0] != "sg3" || !alice.NoSuperdog || !alice.NoStreakPick {
+		t.Errorf("unexpected missing picks of Alice %+v", alice)
+	}
+	if !strings.Contains(alice.Reminder, "Hi Alice") || !strings.Contains(alice.Reminder, "Ohio State @ Michigan") {
+		t.Errorf("unexpected reminder for Alice:\n%s", alice.Reminder)
+	}
+	if bob.Picker != "Bob" || len(bob.Games) != 2 || !bob.NoSuperdog || bob.NoStreakPick {
+		t.Errorf("unexpected missing picks of Bob %+v", bob)
+	}
+
+	sub := PickSubmission{Picks: []string{"Michigan"}, Superdog: "Northwestern"}
+	if s := call(t, srv, http.MethodPut, "/api/seasons/2023/weeks/2/picks/Alice", "alice-token", sub, nil); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if s := call(t, srv, http.MethodPut, "/api/seasons/2023/weeks/2/streaks/Alice", "alice-token", StreakSubmission{Teams: []string{"Michigan"}}, nil); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	var after []MissingPicks
+	if s := call(t, srv, http.MethodGet, path, "", nil, &after); s != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", s)
+	}
+	if len(after) != 1 || after[0].Picker != "Bob" || after[0].Reminder != "" {
+		t.Errorf("expected only Bob to be missing picks, without a reminder, got %+v", after)
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Store is where the server reads and writes the pool's data.
+// FirestoreStore keeps it in Firestore, and MemoryStore keeps it in memory for tests and local development.
+type Store interface {
+	// Seasons returns every season.
+	Seasons(ctx context.Context) ([]firestore.Season, error)
+
+	// Season returns the season that begins in the given year.
+	Season(ctx context.Context, year int) (firestore.Season, error)
+
+	// Teams returns the teams of a season.
+	Teams(ctx context.Context, year int) ([]firestore.Team, []*fs.DocumentRef, error)
+
+	// Weeks returns the weeks of a season in order.
+	Weeks(ctx context.Context, year int) ([]firestore.Week, error)
+
+	// Slate returns a week, its games, and its most recent slate. A week without a slate has no slate games.
+	Slate(ctx context.Context, year, week int) (WeekSlate, error)
+
+	// Picks returns every picker's picks of a week.
+	Picks(ctx context.Context, year, week int) ([]firestore.Pick, error)
+
+	// Results returns the scored results of the weeks of a season before the given week.
+	Results(ctx context.Context, year, before int) ([]firestore.WeekResult, error)
+
+	// Streaks returns the Beat the Streak teams every picker has remaining before and after their pick of a week.
+	Streaks(ctx context.Context, year, week int) (WeekStreaks, error)
+
+	// StreakPicks returns the Beat the Streak picks of a week recorded as streak picks, as pickem4me records them.
+	// Picks made with btstool or the server are instead recorded in the teams remaining of the following week (see Streaks).
+	StreakPicks(ctx context.Context, year, week int) ([]firestore.StreakPick, error)
+
+	// StreakPredictions returns the most recent Beat the Streak predictions of a week's pickers, keyed by picker ID. Pickers without predictions have none.
+	StreakPredictions(ctx context.Context, year, week int) (map[string]firestore.StreakPredictions, error)
+
+	// Predictions returns the model predictions of the games on a week's slate, keyed by game ID.
+	Predictions(ctx context.Context, year, week int) (map[string][]firestore.ModelPrediction, error)
+
+	// SubmitPicks makes or changes a picker's picks of a week on behalf of the authenticated picker by.
+	SubmitPicks(ctx context.Context, year, week int, picker string, sub PickSubmission, by firestore.Picker) error
+
+	// SubmitStreakPick makes or changes a picker's Beat the Streak pick of a week on behalf of the authenticated picker by.
+	SubmitStreakPick(ctx context.Context, year, week int, picker string, sub StreakSubmission, by firestore.Picker) error
+}
+
+// WeekSlate is a week, its games, and its most recent slate.
+type WeekSlate struct {
+	Week firestore.Week
+
+	SlateGames []firestore.SlateGame
+	SlateRefs  []*fs.DocumentRef
+
+	// Games are the week's games keyed by game ID, whether or not they are on the slate.
+	Games map[string]firestore.Game
+}
+
+// WeekStreaks are the Beat the Streak teams the pickers have remaining in a week, keyed by picker ID.
+type WeekStreaks struct {
+	// Remaining are the teams the pickers had remaining going into the week.
+	Remaining map[string]firestore.StreakTeamsRemaining
+
+	// Picked are the teams the pickers have remaining going into the following week. Pickers who have not picked yet have none.
+	Picked map[string]firestore.StreakTeamsRemaining
+}
+
+// PickSubmission is a picker's picks of a week by team name.
+type PickSubmission struct {
+	// Picks are the teams picked to win (or cover the spread) in games on the slate that are not superdog games.
+	Picks []string `json:"picks"`
+
+	// Superdog is the underdog picked in one of the slate's superdog games. No superdog is picked if it is empty.
+	Superdog string `json:"superdog"`
+}
+
+// StreakSubmission is a picker's Beat the Streak pick of a week by team name.
+type StreakSubmission struct {
+	// Teams are the teams picked to win. An empty pick is a bye.
+	Teams []string `json:"teams"`
+}
+
+// NotFoundError is returned when a season, week, or picker does not exist.
+type NotFoundError string
+
+func (e NotFoundError) Error() string {
+	return string(e)
+}
+
+func seasonNotFound(year int) NotFoundError {
+	return NotFoundError(fmt.Sprintf("no season %d", year))
+}
@@ -0,0 +1,501 @@
+package server
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+//go:embed templates/*.html.tmpl
+var templates embed.FS
+
+// tokenCookie is the cookie that holds the token of the picker signed in to the web UI.
+const tokenCookie = "b1gpickem_token"
+
+// currentWeekDays is how many days after the first game of a week the web UI keeps showing it as the current week.
+const currentWeekDays = 4
+
+// webFuncs are the functions available to the web UI's templates.
+var webFuncs = template.FuncMap{
+	"team": func(t Team, rank int) string {
+		if rank > 0 {
+			return fmt.Sprintf("#%d %s", rank, t.School)
+		}
+		return t.School
+	},
+	"spread": func(x float64) string { return fmt.Sprintf("%+0.1f", x) },
+	"pct":    func(x float64) string { return fmt.Sprintf("%0.1f%%", x*100) },
+	"kickoff": func(t time.Time) string {
+		if t.IsZero() {
+			return "TBD"
+		}
+		return t.Local().Format("Mon Jan 2 3:04 PM MST")
+	},
+	"add": func(a, b int) int { return a + b },
+	"teams": func(ts []Team) string {
+		if len(ts) == 0 {
+			return "Bye"
+		}
+		names := make([]string, len(ts))
+		for i, t := range ts {
+			names[i] = t.School
+		}
+		return strings.Join(names, ", ")
+	},
+}
+
+var pages = template.Must(template.New("pages").Funcs(webFuncs).ParseFS(templates, "templates/*.html.tmpl"))
+
+// page is what every page of the web UI shows.
+type page struct {
+	Title string
+
+	// Picker is the LukeName of the signed-in picker, if any.
+	Picker string
+
+	Season int
+	Week   int
+	Weeks  []Week
+
+	Message string
+	Error   string
+}
+
+// weekPage is the slate of a week with model lines, and the signed-in picker's picks.
+type weekPage struct {
+	page
+	Slate   Slate
+	Games   []gameView
+	CanPick bool
+}
+
+// gameView is a slate game with its model lines and the signed-in picker's pick.
+type gameView struct {
+	SlateGame
+	Lines []Prediction
+
+	// Picked is the ID of the team picked, if any.
+	Picked string
+}
+
+// Underdog is the team that is not favored.
+func (g gameView) Underdog() Team {
+	if g.HomeFavored {
+		return g.Away
+	}
+	return g.Home
+}
+
+// streakPage is the signed-in picker's Beat the Streak streak.
+type streakPage struct {
+	page
+	Streak  Streak
+	Teams   []streakOption
+	CanPick bool
+}
+
+// streakOption is a team remaining in a streak.
+type streakOption struct {
+	Team
+	Playing bool
+	Locked  bool
+	Picked  bool
+}
+
+// standingsPage is the season standings through a week.
+type standingsPage struct {
+	page
+	Standings []Standing
+}
+
+// serveWeb serves the web UI.
+//
+//	GET  /
+//	GET  /login, POST /login, POST /logout
+//	GET  /seasons/{year}/weeks/{week}
+//	POST /seasons/{year}/weeks/{week}/picks
+//	GET  /seasons/{year}/weeks/{week}/streak
+//	POST /seasons/{year}/weeks/{week}/streak
+//	GET  /seasons/{year}/weeks/{week}/standings
+func (s *Server) serveWeb(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.URL.Path == "/":
+		s.webIndex(w, r)
+		return
+	case len(parts) == 1 && parts[0] == "login":
+		s.webLogin(w, r)
+		return
+	case len(parts) == 1 && parts[0] == "logout" && r.Method == http.MethodPost:
+		http.SetCookie(w, &http.Cookie{Name: tokenCookie, Value: "", Path: "/", MaxAge: -1})
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	case len(parts) < 4 || parts[0] != "seasons" || parts[2] != "weeks":
+		http.NotFound(w, r)
+		return
+	}
+
+	req := &request{Request: r}
+	var err error
+	if req.year, err = strconv.Atoi(parts[1]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if req.week, err = strconv.Atoi(parts[3]); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	req.me = s.webPicker(r)
+	req.picker = req.me.LukeName
+
+	action := ""
+	if len(parts) == 5 {
+		action = parts[4]
+	}
+	post := r.Method == http.MethodPost
+	switch {
+	case len(parts) > 5:
+		http.NotFound(w, r)
+	case action == "" && !post:
+		s.webWeek(w, req)
+	case action == "picks" && post:
+		s.webSubmitPicks(w, req)
+	case action == "streak" && !post:
+		s.webStreak(w, req)
+	case action == "streak" && post:
+		s.webSubmitStreakPick(w, req)
+	case action == "standings" && !post:
+		s.webStandings(w, req)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// webPicker returns the picker signed in to the web UI, or a picker with no LukeName if no one is signed in.
+func (s *Server) webPicker(r *http.Request) firestore.Picker {
+	c, err := r.Cookie(tokenCookie)
+	if err != nil {
+		return firestore.Picker{}
+	}
+	picker, err := s.auth.Authenticate(r.Context(), c.Value)
+	if err != nil {
+		return firestore.Picker{}
+	}
+	return picker
+}
+
+// render executes a page template, showing errors as plain text.
+func render(w http.ResponseWriter, status int, name string, data interface{}) {
+	var sb strings.Builder
+	if err := pages.ExecuteTemplate(&sb, name, data); err != nil {
+		log.Printf("failed to render %s: %v", name, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprint(w, sb.String())
+}
+
+// renderError shows an error that prevents a page from being shown.
+func renderError(w http.ResponseWriter, p page, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Print(err)
+	}
+	p.Error = err.Error()
+	render(w, status, "error", p)
+}
+
+// newPage fills in what every page of a week shows.
+func (s *Server) newPage(req *request, title string) (page, error) {
+	q := req.URL.Query()
+	p := page{Title: title, Picker: req.picker, Season: req.year, Week: req.week, Message: q.Get("msg"), Error: q.Get("error")}
+	season, err := s.store.Season(req.Context(), req.year)
+	if err != nil {
+		return p, err
+	}
+	weeks, err := s.store.Weeks(req.Context(), req.year)
+	if err != nil {
+		return p, err
+	}
+	p.Weeks = make([]Week, len(weeks))
+	for i, wk := range weeks {
+		p.Weeks[i] = newWeek(wk, season.PickLock)
+	}
+	if _, ok := season.Pickers[req.picker]; !ok {
+		// signed-in pickers not playing this season can only look
+		p.Picker = ""
+	}
+	return p, nil
+}
+
+// redirect returns to a page of the week with a message or an error.
+func redirect(w http.ResponseWriter, req *request, page string, err error) {
+	path := fmt.Sprintf("/seasons/%d/weeks/%d%s", req.year, req.week, page)
+	v := url.Values{}
+	if err != nil {
+		v.Set("error", err.Error())
+	} else {
+		v.Set("msg", "Saved.")
+	}
+	http.Redirect(w, req.Request, path+"?"+v.Encode(), http.StatusSeeOther)
+}
+
+func (s *Server) webIndex(w http.ResponseWriter, r *http.Request) {
+	seasons, err := s.store.Seasons(r.Context())
+	if err != nil {
+		renderError(w, page{Title: "B1G Pick 'Em"}, err)
+		return
+	}
+	if len(seasons) == 0 {
+		renderError(w, page{Title: "B1G Pick 'Em"}, NotFoundError("no seasons"))
+		return
+	}
+	latest := seasons[0]
+	for _, season := range seasons {
+		if season.Year > latest.Year {
+			latest = season
+		}
+	}
+	weeks, err := s.store.Weeks(r.Context(), latest.Year)
+	if err != nil {
+		renderError(w, page{Title: "B1G Pick 'Em"}, err)
+		return
+	}
+	if len(weeks) == 0 {
+		renderError(w, page{Title: "B1G Pick 'Em"}, NotFoundError(fmt.Sprintf("no weeks in season %d", latest.Year)))
+		return
+	}
+	week := currentWeek(weeks, s.now())
+	http.Redirect(w, r, fmt.Sprintf("/seasons/%d/weeks/%d", latest.Year, week.Number), http.StatusSeeOther)
+}
+
+// currentWeek returns the first week that has not started or started within the last few days, or the last week if all weeks have passed.
+func currentWeek(weeks []firestore.Week, now time.Time) firestore.Week {
+	for _, w := range weeks {
+		if w.FirstGameStart.Add(currentWeekDays * 24 * time.Hour).After(now) {
+			return w
+		}
+	}
+	return weeks[len(weeks)-1]
+}
+
+func (s *Server) webLogin(w http.ResponseWriter, r *http.Request) {
+	p := page{Title: "Sign in", Picker: s.webPicker(r).LukeName}
+	if r.Method != http.MethodPost {
+		render(w, http.StatusOK, "login", p)
+		return
+	}
+	token := strings.TrimSpace(r.PostFormValue("token"))
+	if _, err := s.auth.Authenticate(r.Context(), token); err != nil {
+		p.Error = err.Error()
+		render(w, errorStatus(err), "login", p)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: tokenCookie, Value: token, Path: "/", HttpOnly: true, Secure: r.TLS != nil, SameSite: http.SameSiteStrictMode})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (s *Server) webWeek(w http.ResponseWriter, req *request) {
+	p, err := s.newPage(req, fmt.Sprintf("%d Week %d", req.year, req.week))
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	season, n, err := s.seasonNames(&request{Request: req.Request, year: req.year, week: req.week})
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	ws, err := s.weekSlate(req)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	preds, err := s.store.Predictions(req.Context(), req.year, req.week)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	picks, err := s.store.Picks(req.Context(), req.year, req.week)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+
+	wp := weekPage{page: p, Slate: newSlate(req.year, ws, season.PickLock, n, s.now()), CanPick: p.Picker != ""}
+	lines := make(map[string][]Prediction)
+	for _, gp := range newPredictions(ws, preds, "") {
+		lines[gp.Game] = gp.Predictions
+	}
+	picked := make(map[string]string)
+	if p.Picker != "" {
+		for _, pick := range newPicks(ws, picks, season.Pickers[p.Picker].ID, n) {
+			if pick.Team != nil {
+				picked[pick.Game] = pick.Team.ID
+			}
+		}
+	}
+	for _, g := range wp.Slate.Games {
+		wp.Games = append(wp.Games, gameView{SlateGame: g, Lines: lines[g.ID], Picked: picked[g.ID]})
+	}
+	render(w, http.StatusOK, "week", wp)
+}
+
+// submitName returns a name of the team with the given ID that resolves back to it, so picks made by team ID can be submitted by name.
+func submitName(teams *firestore.TeamResolver, id string) (string, error) {
+	t, ok := teams.Team(&fs.DocumentRef{ID: id})
+	if !ok {
+		return "", RequestError(fmt.Sprintf("unknown team '%s'", id))
+	}
+	for _, name := range append(t.OtherNames, t.Names()...) {
+		if ref, ok := teams.Lookup(name, firestore.OtherName); ok && ref.ID == id {
+			return name, nil
+		}
+	}
+	return "", RequestError(fmt.Sprintf("team '%s' has no name to pick it by", id))
+}
+
+func (s *Server) webSubmitPicks(w http.ResponseWriter, req *request) {
+	if req.picker == "" {
+		http.Redirect(w, req.Request, "/login", http.StatusSeeOther)
+		return
+	}
+	teams, err := s.teamResolver(req)
+	if err != nil {
+		redirect(w, req, "", err)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		redirect(w, req, "", RequestError(err.Error()))
+		return
+	}
+	var sub PickSubmission
+	for key, values := range req.PostForm {
+		if !strings.HasPrefix(key, "game-") || len(values) == 0 || values[0] == "" {
+			continue
+		}
+		name, err := submitName(teams, values[0])
+		if err != nil {
+			redirect(w, req, "", err)
+			return
+		}
+		sub.Picks = append(sub.Picks, name)
+	}
+	if id := req.PostFormValue("superdog"); id != "" {
+		if sub.Superdog, err = submitName(teams, id); err != nil {
+			redirect(w, req, "", err)
+			return
+		}
+	}
+	_, err = s.makePicks(req, sub)
+	redirect(w, req, "", err)
+}
+
+func (s *Server) webStreak(w http.ResponseWriter, req *request) {
+	p, err := s.newPage(req, fmt.Sprintf("%d Week %d Beat the Streak", req.year, req.week))
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	if p.Picker == "" {
+		http.Redirect(w, req.Request, "/login", http.StatusSeeOther)
+		return
+	}
+	season, n, err := s.seasonNames(req)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	ws, err := s.store.Slate(req.Context(), req.year, req.week)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	streaks, err := s.store.Streaks(req.Context(), req.year, req.week)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	sps, err := s.store.StreakPredictions(req.Context(), req.year, req.week)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	picker := season.Pickers[req.picker]
+	remaining, picked, err := pickerStreak(streaks, picker)
+	if err != nil {
+		renderError(w, p, NotFoundError(fmt.Sprintf("%s has no streak in week %d", req.picker, req.week)))
+		return
+	}
+
+	sp := streakPage{page: p, Streak: newStreak(n, remaining, picked, pickerPredictions(sps, picker)), CanPick: true}
+	pickedIDs := make(map[string]bool)
+	for _, t := range sp.Streak.Pick {
+		pickedIDs[t.ID] = true
+	}
+	games := ws.games()
+	now := s.now()
+	for _, ref := range remaining.TeamsRemaining {
+		_, locked := season.PickLock.StreakLocked(ws.Week, games, []*fs.DocumentRef{ref}, now)
+		sp.Teams = append(sp.Teams, streakOption{Team: n.team(ref), Playing: playing(ws.Games, ref), Locked: locked, Picked: pickedIDs[ref.ID]})
+	}
+	render(w, http.StatusOK, "streak", sp)
+}
+
+func (s *Server) webSubmitStreakPick(w http.ResponseWriter, req *request) {
+	if req.picker == "" {
+		http.Redirect(w, req.Request, "/login", http.StatusSeeOther)
+		return
+	}
+	teams, err := s.teamResolver(req)
+	if err != nil {
+		redirect(w, req, "/streak", err)
+		return
+	}
+	if err := req.ParseForm(); err != nil {
+		redirect(w, req, "/streak", RequestError(err.Error()))
+		return
+	}
+	var sub StreakSubmission
+	if req.PostFormValue("bye") == "" {
+		for _, id := range req.PostForm["team"] {
+			name, err := submitName(teams, id)
+			if err != nil {
+				redirect(w, req, "/streak", err)
+				return
+			}
+			sub.Teams = append(sub.Teams, name)
+		}
+		if len(sub.Teams) == 0 {
+			redirect(w, req, "/streak", RequestError("pick at least one team, or take a bye"))
+			return
+		}
+	}
+	_, err = s.makeStreakPick(req, sub)
+	redirect(w, req, "/streak", err)
+}
+
+func (s *Server) webStandings(w http.ResponseWriter, req *request) {
+	p, err := s.newPage(req, fmt.Sprintf("%d Standings through Week %d", req.year, req.week))
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	standings, err := s.standings(req)
+	if err != nil {
+		renderError(w, p, err)
+		return
+	}
+	render(w, http.StatusOK, "standings", standingsPage{page: p, Standings: standings.([]Standing)})
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"html"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// browser is a client of the web UI that keeps cookies and does not follow redirects.
+func browser(t *testing.T, srv *httptest.Server) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{
+		Jar:           jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+// visit makes a request of the web UI and returns the status, the redirect location, and the unescaped body.
+func visit(t *testing.T, c *http.Client, srv *httptest.Server, method, path string, form url.Values) (int, string, string) {
+	t.Helper()
+	var resp *http.Response
+	var err error
+	if method == http.MethodPost {
+		resp, err = c.PostForm(srv.URL+path, form)
+	} else {
+		resp, err = c.Get(srv.URL + path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, resp.Header.Get("Location"), html.UnescapeString(string(b))
+}
+
+func TestWebPages(t *testing.T) {
+	srv := testServer(t)
+	c := browser(t, srv)
+
+	if s, loc, _ := visit(t, c, srv, http.MethodGet, "/", nil); s != http.StatusSeeOther || loc != "/seasons/2023/weeks/2" {
+		t.Errorf("expected / to redirect to the current week, got %d %s", s, loc)
+	}
+
+	s, _, body := visit(t, c, srv, http.MethodGet, "/seasons/2023/weeks/2", nil)
+	if s != http.StatusOK {
+		t.Fatalf("week: status %d", s)
+	}
+	for _, want := range []string{"Ohio State", "linesag +3.5", "linefpi -1.0", "Sign in", "disabled"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected week page to contain %q", want)
+		}
+	}
+
+	if s, _, _ := visit(t, c, srv, http.MethodGet, "/seasons/2023/weeks/2/standings", nil); s != http.StatusOK {
+		t.Errorf("standings: status %d", s)
+	}
+	if s, _, _ := visit(t, c, srv, http.MethodGet, "/seasons/2023/weeks/3", nil); s != http.StatusNotFound {
+		t.Errorf("expected a week without a slate to be not found, got %d", s)
+	}
+	if s, loc, _ := visit(t, c, srv, http.MethodGet, "/seasons/2023/weeks/2/streak", nil); s != http.StatusSeeOther || loc != "/login" {
+		t.Errorf("expected the streak page to require signing in, got %d %s", s, loc)
+	}
+}
+
+func TestWebPicks(t *testing.T) {
+	srv := testServer(t)
+	c := browser(t, srv)
+
+	if s, _, _ := visit(t, c, srv, http.MethodPost, "/login", url.Values{"token": {"wrong"}}); s != http.StatusUnauthorized {
+		t.Errorf("expected a wrong token to be refused, got %d", s)
+	}
+	if s, _, _ := visit(t, c, srv, http.MethodPost, "/login", url.Values{"token": {"alice-token"}}); s != http.StatusSeeOther {
+		t.Fatalf("login: status %d", s)
+	}
+
+	week := "/seasons/2023/weeks/2"
+	s, loc, _ := visit(t, c, srv, http.MethodPost, week+"/picks", url.Values{"game-sg3": {"osu"}, "superdog": {"nw"}})
+	if s != http.StatusSeeOther || !strings.Contains(loc, "msg=") {
+		t.Fatalf("expected picks to be saved, got %d %s", s, loc)
+	}
+	_, _, body := visit(t, c, srv, http.MethodGet, week, nil)
+	for _, want := range []string{`value="osu" checked`, `value="nw" checked`, `value="iowa" checked`, "Signed in as Alice"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected week page to contain %q", want)
+		}
+	}
+
+	// the underdog of a superdog game is the only team that can be picked in it
+	if _, loc, _ := visit(t, c, srv, http.MethodPost, week+"/picks", url.Values{"superdog": {"psu"}}); !strings.Contains(loc, "error=") {
+		t.Errorf("expected picking the superdog favorite to fail, got %s", loc)
+	}
+	// a game that has kicked off cannot be changed
+	if _, loc, _ := visit(t, c, srv, http.MethodPost, week+"/picks", url.Values{"game-sg4": {"msu"}}); !strings.Contains(loc, "error=") {
+		t.Errorf("expected changing a locked pick to fail, got %s", loc)
+	}
+
+	s, _, body = visit(t, c, srv, http.MethodGet, week+"/streak", nil)
+	if s != http.StatusOK {
+		t.Fatalf("streak: status %d", s)
+	}
+	for _, want := range []string{"No pick yet", "Suggested streak", "60.0%", "Ohio State, Penn State"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected streak page to contain %q", want)
+		}
+	}
+	if _, loc, _ := visit(t, c, srv, http.MethodPost, week+"/streak", url.Values{"team": {"osu", "psu"}}); !strings.Contains(loc, "msg=") {
+		t.Fatalf("expected streak pick to be saved, got %s", loc)
+	}
+	_, _, body = visit(t, c, srv, http.MethodGet, week+"/streak", nil)
+	if !strings.Contains(body, "This week: Ohio State, Penn State") {
+		t.Errorf("expected streak page to show the pick")
+	}
+
+	if s, _, _ := visit(t, c, srv, http.MethodPost, "/logout", nil); s != http.StatusSeeOther {
+		t.Errorf("logout: status %d", s)
+	}
+	if _, loc, _ := visit(t, c, srv, http.MethodPost, week+"/picks", url.Values{"game-sg3": {"mich"}}); loc != "/login" {
+		t.Errorf("expected picks to require signing in after signing out, got %s", loc)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
 )
 
 type Context struct {
@@ -13,6 +14,12 @@ type Context struct {
 	Force  bool
 	DryRun bool
 
+	// Override is the commissioner's reason for changing streak picks after they locked. Locked picks cannot be changed without a reason.
+	Override string
+
+	// Actor is the authenticated picker making the picks, as recorded with any lock override.
+	Actor firestore.Picker
+
 	Season int
 	Week   int
 	Picks  map[string]string
@@ -1,10 +1,11 @@
 package btspick
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
-	"sync"
+	"time"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
@@ -27,6 +28,16 @@ func MakePicks(ctx *Context) error {
 		return fmt.Errorf("MakePicks: failed to get following week: %w", err)
 	}
 
+	teams, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("MakePicks: failed to get teams: %w", err)
+	}
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("MakePicks: failed to get games of week %d: %w", week.Number, err)
+	}
+	wt := weekTeams{resolver: teams, games: games, byMatchup: firestore.NewGameRefsByMatchup(games, gameRefs)}
+
 	for picker, picks := range ctx.Picks {
 		// Make sure the picker is picking this season
 		var pickerRef *fs.DocumentRef
@@ -36,7 +47,7 @@ func MakePicks(ctx *Context) error {
 		}
 
 		picksArr := strings.Split(picks, ",")
-		err = makeStreakPick(ctx, seasonRef, weekRef, nextWeekRef, pickerRef, picksArr)
+		err = makeStreakPick(ctx, season.PickLock, week, wt, seasonRef, weekRef, nextWeekRef, pickerRef, picksArr)
 		if err != nil {
 			return fmt.Errorf("MakePicks: unable to make streak pick of teams %v for picker '%s': %w", picks, picker, err)
 		}
@@ -45,15 +56,17 @@ func MakePicks(ctx *Context) error {
 	return nil
 }
 
-var teamRefsByOtherName firestore.TeamRefsByName
-var teamsOnce sync.Once
-
-var gameRefsByMatchup firestore.GameRefsByMatchup
-var gamesOnce sync.Once
+// weekTeams are the teams of a season and the games they play in the week being picked.
+type weekTeams struct {
+	resolver  *firestore.TeamResolver
+	games     []firestore.Game
+	byMatchup firestore.GameRefsByMatchup
+}
 
 // Delete team with names in `teamNames` from the list of remaining teams in week `weekTo` for picker with short name `pickerName`.
-// Return an error if `picker` cannot pick all of the teams in `teamNames` for whatever reason.
-func makeStreakPick(ctx *Context, season, weekFrom, weekTo, picker *fs.DocumentRef, teamNames []string) error {
+// Return an error if `picker` cannot pick all of the teams in `teamNames` for whatever reason,
+// including if the pick, or the pick it replaces, is locked and `ctx.Override` gives no reason to override the lock.
+func makeStreakPick(ctx *Context, lock firestore.PickLock, week firestore.Week, wt weekTeams, season, weekFrom, weekTo, picker *fs.DocumentRef, teamNames []string) error {
 
 	str, _, err := firestore.GetStreakTeamsRemaining(ctx, season, weekFrom, picker)
 	if err != nil {
@@ -77,33 +90,16 @@ func makeStreakPick(ctx *Context, season, weekFrom, weekTo, picker *fs.DocumentR
 	}
 	str.PickTypesRemaining[nPicks]--
 
-	teamsOnce.Do(func() {
-		teams, teamRefs, err := firestore.GetTeams(ctx, season)
+	teamRefs := make([]*fs.DocumentRef, len(teamNames))
+	for i, teamName := range teamNames {
+		teamRef, err := wt.resolver.Resolve(teamName, firestore.OtherName)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("makeStreakPick: team not found in season '%s': %w", season.ID, err)
 		}
-		var err2 *firestore.DuplicateTeamNameError
-		teamRefsByOtherName, err2 = firestore.NewTeamRefsByOtherName(teams, teamRefs)
-		if err2 != nil {
-			panic(err2)
-		}
-	})
-	gamesOnce.Do(func() {
-		games, gameRefs, err := firestore.GetGames(ctx, weekFrom)
-		if err != nil {
-			panic(err)
-		}
-		gameRefsByMatchup = firestore.NewGameRefsByMatchup(games, gameRefs)
-	})
-	for _, teamName := range teamNames {
-		var teamRef *fs.DocumentRef
-		var ok bool
-		if teamRef, ok = teamRefsByOtherName[teamName]; !ok {
-			return fmt.Errorf("makeStreakPick: team with other name '%s' not found in season '%s'", teamName, season.ID)
-		}
-		if _, ok = gameRefsByMatchup.LookupTeam(teamRef.ID); !ok {
+		if _, ok := wt.byMatchup.LookupTeam(teamRef.ID); !ok {
 			return fmt.Errorf("makeStreakPick: team with other name '%s' not playing in week '%s'", teamName, weekFrom.ID)
 		}
+		teamRefs[i] = teamRef
 		var found bool
 		for i, ref := range str.TeamsRemaining {
 			if ref.ID == teamRef.ID {
@@ -120,18 +116,74 @@ func makeStreakPick(ctx *Context, season, weekFrom, weekTo, picker *fs.DocumentR
 		}
 	}
 
+	lockTime, locked := lock.StreakLocked(week, wt.games, teamRefs, time.Now())
+	if !locked {
+		// the pick being replaced, if any, may have locked already
+		lockTime, locked, err = previousPickLocked(ctx, lock, week, wt.games, season, weekFrom, weekTo, picker)
+		if err != nil {
+			return fmt.Errorf("makeStreakPick: unable to check lock of previous pick: %w", err)
+		}
+	}
+	var override *firestore.LockOverride
+	if locked {
+		l := firestore.PickLockedError{What: fmt.Sprintf("%s streak pick", picker.ID), Locked: lockTime}
+		if ctx.Override == "" {
+			return fmt.Errorf("makeStreakPick: %w", firestore.PickLockedErrors{l})
+		}
+		log.Printf("Overriding lock: %s", l)
+		o := firestore.NewLockOverride(picker, []firestore.PickLockedError{l}, ctx.Override, "btstool pick", ctx.Actor)
+		override = &o
+	}
+
 	// Update remaining picks in next week's collection
-	col := weekTo.Collection("streak-teams-remaining")
+	col := weekTo.Collection(firestore.STREAK_TEAMS_REMAINING_COLLECTION)
 	newRef := col.Doc(picker.ID)
 	if ctx.DryRun {
 		log.Print("DRY RUN: would write the following to datastore:")
 		log.Printf("%s -> %v\n", newRef.Path, str)
+		if override != nil {
+			log.Printf("%s -> %+v", weekFrom.Collection(firestore.LOCK_OVERRIDES_COLLECTION).Path, *override)
+		}
 		return nil
 	}
-	if ctx.Force {
-		_, err = newRef.Set(ctx, &str)
-	} else {
-		_, err = newRef.Create(ctx, &str)
+	return ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
+		var err error
+		if ctx.Force {
+			err = t.Set(newRef, &str)
+		} else {
+			err = t.Create(newRef, &str)
+		}
+		if err != nil || override == nil {
+			return err
+		}
+		return firestore.RecordLockOverride(t, weekFrom, *override)
+	})
+}
+
+// previousPickLocked reports whether the streak pick already made for the picker in weekFrom is locked, and when it locks.
+// The previous pick is the teams remaining going into weekFrom that are no longer remaining going into weekTo.
+func previousPickLocked(ctx *Context, lock firestore.PickLock, week firestore.Week, games []firestore.Game, season, weekFrom, weekTo, picker *fs.DocumentRef) (time.Time, bool, error) {
+	picked, _, err := firestore.GetStreakTeamsRemaining(ctx, season, weekTo, picker)
+	if _, ok := err.(firestore.NoStreakTeamsRemaining); ok {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	before, _, err := firestore.GetStreakTeamsRemaining(ctx, season, weekFrom, picker)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	remaining := make(map[string]bool)
+	for _, ref := range picked.TeamsRemaining {
+		remaining[ref.ID] = true
+	}
+	previous := make([]*fs.DocumentRef, 0)
+	for _, ref := range before.TeamsRemaining {
+		if !remaining[ref.ID] {
+			previous = append(previous, ref)
+		}
 	}
-	return err
+	t, locked := lock.StreakLocked(week, games, previous, time.Now())
+	return t, locked, nil
 }
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
@@ -26,37 +25,16 @@ func AddTeams(ctx *Context) error {
 	if err != nil {
 		return fmt.Errorf("AddTeams: failed to get teams: %w", err)
 	}
-	var lookup firestore.TeamRefsByName
-	var err2 *firestore.DuplicateTeamNameError
-	for {
-		lookup, err2 = firestore.NewTeamRefsByOtherName(teams, teamRefs)
-		if err2 == nil {
-			break
-		}
-
-		updateNames, err := editteams.SurveyReplaceName(teams, teamRefs, err2.Name, err2.Teams, err2.Refs, firestore.OtherName)
-		if err != nil {
-			panic(err)
-		}
-
-		for ref, t := range updateNames {
-			fmt.Printf("Updating %s to eliminate %s (names now [%s])\n", ref.ID, err2.Name, strings.Join(t.OtherNames, ", "))
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	editContext := &editteams.Context{
+		Context:         ctx.Context,
+		Force:           ctx.Force,
+		DryRun:          ctx.DryRun,
+		FirestoreClient: ctx.FirestoreClient,
+		Season:          ctx.Season,
+	}
+	lookup, err := editteams.NewTeamResolver(editContext, nil, teams, teamRefs)
+	if err != nil {
+		return fmt.Errorf("AddTeams: failed to make team resolver: %w", err)
 	}
 
 	teamsToAdd := make(map[string]*fs.DocumentRef)
@@ -66,9 +44,9 @@ func AddTeams(ctx *Context) error {
 		}
 	}
 	for _, name := range ctx.TeamNames {
-		ref, found := lookup[name]
-		if !found {
-			return fmt.Errorf("AddTeams: failed to find team with other name '%s'", name)
+		ref, err := lookup.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("AddTeams: %w", err)
 		}
 		teamsToAdd[ref.ID] = ref
 	}
@@ -14,23 +14,19 @@ func RmTeams(ctx *Context) error {
 	if err != nil {
 		return fmt.Errorf("RmTeams: failed to get season %d: %w", ctx.Season, err)
 	}
-	teams, teamRefs, err := firestore.GetTeams(ctx.Context, seasonRef)
+	lookup, err := firestore.GetTeamResolver(ctx.Context, seasonRef)
 	if err != nil {
 		return fmt.Errorf("RmTeams: failed to get teams: %w", err)
 	}
-	lookup, err := firestore.NewTeamRefsByOtherName(teams, teamRefs)
-	if err != nil {
-		panic(err)
-	}
 
 	teamsToKeep := make(map[string]*fs.DocumentRef)
 	for _, ref := range season.StreakTeams {
 		teamsToKeep[ref.ID] = ref
 	}
 	for _, name := range ctx.TeamNames {
-		ref, found := lookup[name]
-		if !found {
-			return fmt.Errorf("RmTeams: failed to find team with other name '%s'", name)
+		ref, err := lookup.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("RmTeams: %w", err)
 		}
 		delete(teamsToKeep, ref.ID)
 	}
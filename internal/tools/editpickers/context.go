@@ -17,6 +17,9 @@ type Context struct {
 	ID              string
 	Season          int
 	KeepSeasons     bool
+
+	// Role is the role SetRole gives the picker.
+	Role firestore.Role
 }
 
 func NewContext(ctx context.Context) *Context {
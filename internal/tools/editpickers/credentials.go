@@ -0,0 +1,92 @@
+package editpickers
+
+import (
+	"fmt"
+	"log"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// onePicker looks up the single picker the context names.
+func onePicker(ctx *Context, fn string) (firestore.Picker, *fs.DocumentRef, error) {
+	if len(ctx.Pickers) != 1 {
+		return firestore.Picker{}, nil, fmt.Errorf("%s: expected one picker, got %d", fn, len(ctx.Pickers))
+	}
+	picker, ref, err := firestore.GetPickerByLukeName(ctx, ctx.FirestoreClient, ctx.Pickers[0].LukeName)
+	if err != nil {
+		return picker, nil, fmt.Errorf("%s: failed to get picker '%s': %w", fn, ctx.Pickers[0].LukeName, err)
+	}
+	return picker, ref, nil
+}
+
+// AddToken makes a new API token for a picker and stores its hash. The token is printed once and cannot be recovered.
+func AddToken(ctx *Context) error {
+	picker, ref, err := onePicker(ctx, "AddToken")
+	if err != nil {
+		return err
+	}
+	token, err := firestore.NewToken()
+	if err != nil {
+		return fmt.Errorf("AddToken: %w", err)
+	}
+	hash := firestore.HashToken(token)
+
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would add a token to picker '%s' at %s", picker.LukeName, ref.Path)
+		return nil
+	}
+
+	_, err = ref.Update(ctx, []fs.Update{{Path: "token_hashes", Value: fs.ArrayUnion(hash)}})
+	if err != nil {
+		return fmt.Errorf("AddToken: failed to add token to picker '%s': %w", picker.LukeName, err)
+	}
+	fmt.Printf("Token for %s (give this to the picker; it will not be shown again): %s\n", picker.LukeName, token)
+	return nil
+}
+
+// RevokeTokens removes every API token of a picker.
+func RevokeTokens(ctx *Context) error {
+	picker, ref, err := onePicker(ctx, "RevokeTokens")
+	if err != nil {
+		return err
+	}
+
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would revoke %d tokens of picker '%s' at %s", len(picker.TokenHashes), picker.LukeName, ref.Path)
+		return nil
+	}
+	if !ctx.Force {
+		return fmt.Errorf("RevokeTokens: revoking tokens locks the picker out: use force flag to force revoke")
+	}
+
+	_, err = ref.Update(ctx, []fs.Update{{Path: "token_hashes", Value: fs.Delete}})
+	if err != nil {
+		return fmt.Errorf("RevokeTokens: failed to revoke tokens of picker '%s': %w", picker.LukeName, err)
+	}
+	log.Printf("Revoked %d tokens of picker '%s'", len(picker.TokenHashes), picker.LukeName)
+	return nil
+}
+
+// SetRole gives a picker a role.
+func SetRole(ctx *Context) error {
+	picker, ref, err := onePicker(ctx, "SetRole")
+	if err != nil {
+		return err
+	}
+	if ctx.Role == "" {
+		return fmt.Errorf("SetRole: no role given")
+	}
+
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would change role of picker '%s' at %s from '%s' to '%s'", picker.LukeName, ref.Path, picker.Role, ctx.Role)
+		return nil
+	}
+
+	_, err = ref.Update(ctx, []fs.Update{{Path: "role", Value: string(ctx.Role)}})
+	if err != nil {
+		return fmt.Errorf("SetRole: failed to set role of picker '%s': %w", picker.LukeName, err)
+	}
+	log.Printf("Picker '%s' is now a %s", picker.LukeName, ctx.Role)
+	return nil
+}
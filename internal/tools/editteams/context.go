@@ -19,6 +19,12 @@ type Context struct {
 	Team   firestore.Team
 	Season int
 	Append bool
+
+	// Name is a team name to find.
+	Name string
+
+	// AliasFile is the pending aliases report to approve.
+	AliasFile string
 }
 
 func NewContext(ctx context.Context) *Context {
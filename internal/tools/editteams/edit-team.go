@@ -133,21 +133,17 @@ func EditTeam(ctx *Context) error {
 	return err
 }
 
-func SurveyAddName(teams []firestore.Team, teamRefs []*fs.DocumentRef, name string, nameType firestore.NameType) (firestore.Team, *fs.DocumentRef, error) {
+// SurveyAddName asks the user which team should be known by a name that was not found, listing the teams with the most similar names first.
+func SurveyAddName(tr *firestore.TeamResolver, name string, nameType firestore.NameType) (*fs.DocumentRef, error) {
 	fmt.Printf("An error occurred when looking up a team.\nThe name \"%s\" is not a recognized %s name.\nYou must add the name to an existing team to correct this before continuing.", name, nameType)
 
-	teamsByName := make(map[string]firestore.Team)
-	teamRefsByName := make(map[string]*fs.DocumentRef)
-	teamNames := []string{}
-	teamNameOnly := []string{}
-	for i, team := range teams {
-		dispName := fmt.Sprintf("(%s) %s", teamRefs[i].ID, team)
-		teamsByName[dispName] = team
-		teamRefsByName[dispName] = teamRefs[i]
-		teamNames = append(teamNames, dispName)
-		teamNameOnly = append(teamNameOnly, team.String())
+	candidates := tr.Candidates(name, 0)
+	refsByName := make(map[string]*fs.DocumentRef)
+	teamNames := make([]string, len(candidates))
+	for i, c := range candidates {
+		teamNames[i] = c.String()
+		refsByName[teamNames[i]] = c.Ref
 	}
-	sort.Sort(ByOther[string, string]{teamNames, teamNameOnly})
 	q1 := &survey.Select{
 		Message: fmt.Sprintf("Which team corresponds to the %s name '%s'?", nameType, name),
 		Options: teamNames,
@@ -155,23 +151,12 @@ func SurveyAddName(teams []firestore.Team, teamRefs []*fs.DocumentRef, name stri
 	var a1 string
 	err := survey.AskOne(q1, &a1)
 	if err != nil {
-		return firestore.Team{}, nil, err
-	}
-
-	t := teamsByName[a1]
-	ref := teamRefsByName[a1]
-	switch nameType {
-	case firestore.ShortName:
-		t.ShortNames = append(t.ShortNames, name)
-	case firestore.OtherName:
-		t.OtherNames = append(t.OtherNames, name)
-	default:
-		return t, ref, errors.New("name type not recognized")
+		return nil, err
 	}
-
-	return t, ref, nil
+	return refsByName[a1], nil
 }
 
+// SurveyReplaceName asks the user which of the teams sharing a name should be renamed or lose the name, returning the teams that changed.
 func SurveyReplaceName(teams []firestore.Team, teamRefs []*fs.DocumentRef, errName string, errTeams []firestore.Team, errRefs []*fs.DocumentRef, nameType firestore.NameType) (map[*fs.DocumentRef]firestore.Team, error) {
 	fmt.Printf("An error occurred when creating a team lookup map.\nThe name \"%s\" is used by %d teams.\nYou must update the names used by the teams to correct this before continuing.", errName, len(errTeams))
 	teamsByName := make(map[string]firestore.Team)
@@ -209,7 +194,7 @@ func SurveyReplaceName(teams []firestore.Team, teamRefs []*fs.DocumentRef, errNa
 			return nil
 		}))
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		t := teamsByName[updateTeam]
 		if nameType == firestore.ShortName {
@@ -233,7 +218,7 @@ func SurveyReplaceName(teams []firestore.Team, teamRefs []*fs.DocumentRef, errNa
 				}
 			}
 		} else {
-			panic(errors.New("unrecognized name type"))
+			return nil, fmt.Errorf("unrecognized name type %s", nameType)
 		}
 		updateNames[teamRefsByName[updateTeam]] = t
 	}
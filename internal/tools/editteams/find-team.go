@@ -0,0 +1,36 @@
+package editteams
+
+import (
+	"fmt"
+
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// nFindCandidates is the number of candidates printed by FindTeam when a name is not recognized.
+const nFindCandidates = 5
+
+// FindTeam prints the team known by the name ctx.Name in ctx.Season, or the teams with the most similar names if no team is known by it.
+func FindTeam(ctx *Context) error {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("FindTeam: failed to get season: %w", err)
+	}
+	tr, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("FindTeam: failed to get teams: %w", err)
+	}
+
+	for _, nameType := range []firestore.NameType{firestore.ShortName, firestore.OtherName} {
+		if ref, ok := tr.Lookup(ctx.Name, nameType); ok {
+			team, _ := tr.Team(ref)
+			fmt.Printf("%s name '%s' -> %s: %s\n", nameType, ctx.Name, ref.ID, team)
+			return nil
+		}
+	}
+
+	fmt.Printf("No team is known by '%s'. Most similar:\n", ctx.Name)
+	for _, c := range tr.Candidates(ctx.Name, nFindCandidates) {
+		fmt.Println(c)
+	}
+	return nil
+}
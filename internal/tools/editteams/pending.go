@@ -0,0 +1,173 @@
+package editteams
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// AliasStatus describes how a pending alias was resolved.
+type AliasStatus string
+
+const (
+	// AliasMatched means the name was matched to a team with confidence and used for the run that found it.
+	AliasMatched AliasStatus = "matched"
+
+	// AliasUnresolved means no team matched the name with confidence.
+	AliasUnresolved AliasStatus = "unresolved"
+
+	// AliasDuplicate means the name is used by more than one team.
+	AliasDuplicate AliasStatus = "duplicate"
+)
+
+// PendingCandidate is a team that might be known by a pending alias.
+type PendingCandidate struct {
+	TeamID string  `json:"team_id"`
+	Team   string  `json:"team"`
+	Score  float64 `json:"score,omitempty"`
+}
+
+// String implements the Stringer interface.
+func (c PendingCandidate) String() string {
+	return fmt.Sprintf("(%s) %s [%0.3f]", c.TeamID, c.Team, c.Score)
+}
+
+func newPendingCandidate(c firestore.NameCandidate) PendingCandidate {
+	return PendingCandidate{TeamID: c.Ref.ID, Team: c.Team.String(), Score: c.Score}
+}
+
+// PendingAlias is a team name found while running without prompts that needs a human to approve or fix it.
+// To approve an alias, set TeamID to the team that should be known by Name (if it is not already set) and set Approved to true,
+// then run ApproveAliases.
+type PendingAlias struct {
+	Season   int                `json:"season"`
+	Name     string             `json:"name"`
+	NameType firestore.NameType `json:"name_type"`
+	Status   AliasStatus        `json:"status"`
+
+	// TeamID is the ID of the team matched to the name, if any.
+	TeamID string `json:"team_id,omitempty"`
+
+	// Score is the similarity of the name to the matched team's closest name.
+	Score float64 `json:"score,omitempty"`
+
+	// Candidates are the best-scoring teams for the name, or the teams that share a duplicate name.
+	Candidates []PendingCandidate `json:"candidates,omitempty"`
+
+	// Seen is the last time the name was encountered.
+	Seen time.Time `json:"seen"`
+
+	// Approved is set by a human to mark the alias ready to be written to Firestore.
+	Approved bool `json:"approved"`
+}
+
+func (a PendingAlias) key() string {
+	return fmt.Sprintf("%d/%s/%s", a.Season, a.NameType, a.Name)
+}
+
+// PendingAliases is a report of pending aliases, sorted by season, name type, and name.
+type PendingAliases []PendingAlias
+
+// Merge adds an alias to the report, replacing any alias for the same season, name type, and name.
+// A human's approval and choice of team are kept if the alias was already in the report.
+func (p PendingAliases) Merge(alias PendingAlias) PendingAliases {
+	if alias.Seen.IsZero() {
+		alias.Seen = time.Now()
+	}
+	out := make(PendingAliases, 0, len(p)+1)
+	for _, a := range p {
+		if a.key() != alias.key() {
+			out = append(out, a)
+			continue
+		}
+		if a.Approved {
+			alias.Approved = true
+			alias.TeamID = a.TeamID
+		}
+	}
+	out = append(out, alias)
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Season != out[j].Season {
+			return out[i].Season < out[j].Season
+		}
+		if out[i].NameType != out[j].NameType {
+			return out[i].NameType < out[j].NameType
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// ReadPendingAliases reads a pending aliases report. A missing file is an empty report.
+func ReadPendingAliases(fileName string) (PendingAliases, error) {
+	b, err := os.ReadFile(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return PendingAliases{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ReadPendingAliases: failed to read '%s': %w", fileName, err)
+	}
+	var p PendingAliases
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("ReadPendingAliases: failed to decode '%s': %w", fileName, err)
+	}
+	return p, nil
+}
+
+// WritePendingAliases writes a pending aliases report as indented JSON.
+func WritePendingAliases(fileName string, p PendingAliases) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("WritePendingAliases: failed to encode: %w", err)
+	}
+	if err := os.WriteFile(fileName, b, 0644); err != nil {
+		return fmt.Errorf("WritePendingAliases: failed to write '%s': %w", fileName, err)
+	}
+	return nil
+}
+
+// ApproveAliases adds the approved aliases in the pending aliases report ctx.AliasFile to their teams in ctx.Season
+// and removes them from the report.
+func ApproveAliases(ctx *Context) error {
+	pending, err := ReadPendingAliases(ctx.AliasFile)
+	if err != nil {
+		return fmt.Errorf("ApproveAliases: %w", err)
+	}
+
+	remaining := make(PendingAliases, 0, len(pending))
+	for _, a := range pending {
+		if !a.Approved || a.Season != ctx.Season {
+			remaining = append(remaining, a)
+			continue
+		}
+		if a.TeamID == "" {
+			return fmt.Errorf("ApproveAliases: %s name '%s' is approved but has no team_id", a.NameType, a.Name)
+		}
+
+		ref := ctx.FirestoreClient.Collection(firestore.SEASONS_COLLECTION).Doc(strconv.Itoa(ctx.Season)).Collection(firestore.TEAMS_COLLECTION).Doc(a.TeamID)
+		if ctx.DryRun {
+			log.Printf("DRY RUN: would add %s name %s to %s", a.NameType, a.Name, ref.Path)
+			continue
+		}
+		if err := firestore.AddTeamAlias(ctx, ref, a.Name, a.NameType); err != nil {
+			return fmt.Errorf("ApproveAliases: failed to add %s name '%s' to team %s: %w", a.NameType, a.Name, a.TeamID, err)
+		}
+		log.Printf("Added %s name %s to %s", a.NameType, a.Name, ref.Path)
+	}
+
+	if ctx.DryRun {
+		return nil
+	}
+	if err := WritePendingAliases(ctx.AliasFile, remaining); err != nil {
+		return fmt.Errorf("ApproveAliases: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package editteams
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// NameResolver decides what to do about team names that are missing from or duplicated in a team lookup.
+type NameResolver interface {
+	// AddName picks the team in season ctx.Season that should be known by a name the team resolver does not recognize.
+	AddName(ctx *Context, tr *firestore.TeamResolver, name string, nameType firestore.NameType) (*fs.DocumentRef, error)
+
+	// ReplaceName renames or removes a name shared by multiple teams in season ctx.Season, returning the teams that changed.
+	ReplaceName(ctx *Context, teams []firestore.Team, teamRefs []*fs.DocumentRef, errName string, errTeams []firestore.Team, errRefs []*fs.DocumentRef, nameType firestore.NameType) (map[*fs.DocumentRef]firestore.Team, error)
+
+	// Persist reports whether names picked by AddName or changed by ReplaceName should be written to Firestore.
+	Persist() bool
+}
+
+// SurveyResolver asks the user on the terminal how to resolve each name.
+type SurveyResolver struct{}
+
+// AddName implements NameResolver.
+func (SurveyResolver) AddName(ctx *Context, tr *firestore.TeamResolver, name string, nameType firestore.NameType) (*fs.DocumentRef, error) {
+	return SurveyAddName(tr, name, nameType)
+}
+
+// ReplaceName implements NameResolver.
+func (SurveyResolver) ReplaceName(ctx *Context, teams []firestore.Team, teamRefs []*fs.DocumentRef, errName string, errTeams []firestore.Team, errRefs []*fs.DocumentRef, nameType firestore.NameType) (map[*fs.DocumentRef]firestore.Team, error) {
+	return SurveyReplaceName(teams, teamRefs, errName, errTeams, errRefs, nameType)
+}
+
+// Persist implements NameResolver. Names chosen by the user are written to Firestore.
+func (SurveyResolver) Persist() bool { return true }
+
+// ambiguityMargin is how much better the best fuzzy match must score than the runner-up to be accepted.
+const ambiguityMargin = .05
+
+// nPendingCandidates is the number of candidate teams reported for each pending alias.
+const nPendingCandidates = 3
+
+// FuzzyResolver resolves names without prompting by matching them against teams' schools, short names, and other names.
+// Matches are used for the current run only: every match, and every name that could not be matched, is recorded in a pending aliases file
+// for a human to approve later with ApproveAliases.
+type FuzzyResolver struct {
+	// Threshold is the minimum similarity (from 0 to 1) needed to accept a match.
+	Threshold float64
+
+	// PendingFile is the JSON file where pending aliases are recorded. If empty, pending aliases are not recorded.
+	PendingFile string
+}
+
+// AddName implements NameResolver.
+// The best-scoring team is accepted if it scores at least the threshold and clearly better than the runner-up.
+func (f FuzzyResolver) AddName(ctx *Context, tr *firestore.TeamResolver, name string, nameType firestore.NameType) (*fs.DocumentRef, error) {
+	candidates := tr.Candidates(name, 0)
+	alias := PendingAlias{Season: ctx.Season, Name: name, NameType: nameType, Status: AliasUnresolved}
+	for i := 0; i < len(candidates) && i < nPendingCandidates; i++ {
+		alias.Candidates = append(alias.Candidates, newPendingCandidate(candidates[i]))
+	}
+
+	best, ok := acceptCandidate(candidates, f.Threshold)
+	if !ok {
+		if err := f.record(alias); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no team matches %s name '%s' with similarity of at least %0.3f (candidates: %v)", nameType, name, f.Threshold, alias.Candidates)
+	}
+
+	alias.Status = AliasMatched
+	alias.TeamID = best.Ref.ID
+	alias.Score = best.Score
+	if err := f.record(alias); err != nil {
+		return nil, err
+	}
+	log.Printf("Matched %s name '%s' to %s (similarity %0.3f with '%s')", nameType, name, best.Ref.ID, best.Score, best.MatchedName)
+	return best.Ref, nil
+}
+
+// ReplaceName implements NameResolver.
+// A name shared by multiple teams cannot be resolved automatically, so it is recorded and an error is returned.
+func (f FuzzyResolver) ReplaceName(ctx *Context, teams []firestore.Team, teamRefs []*fs.DocumentRef, errName string, errTeams []firestore.Team, errRefs []*fs.DocumentRef, nameType firestore.NameType) (map[*fs.DocumentRef]firestore.Team, error) {
+	alias := PendingAlias{Season: ctx.Season, Name: errName, NameType: nameType, Status: AliasDuplicate}
+	ids := make([]string, len(errRefs))
+	for i, ref := range errRefs {
+		alias.Candidates = append(alias.Candidates, PendingCandidate{TeamID: ref.ID, Team: errTeams[i].String()})
+		ids[i] = ref.ID
+	}
+	if err := f.record(alias); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%s name '%s' is used by multiple teams (%s): edit the teams to remove the duplicate", nameType, errName, strings.Join(ids, ", "))
+}
+
+// Persist implements NameResolver. Fuzzy matches are not written to Firestore until approved.
+func (FuzzyResolver) Persist() bool { return false }
+
+func (f FuzzyResolver) record(alias PendingAlias) error {
+	if f.PendingFile == "" {
+		return nil
+	}
+	pending, err := ReadPendingAliases(f.PendingFile)
+	if err != nil {
+		return err
+	}
+	pending = pending.Merge(alias)
+	if err := WritePendingAliases(f.PendingFile, pending); err != nil {
+		return err
+	}
+	return nil
+}
+
+// acceptCandidate returns the best candidate if it scores at least threshold and clearly beats the runner-up.
+func acceptCandidate(candidates []firestore.NameCandidate, threshold float64) (firestore.NameCandidate, bool) {
+	if len(candidates) == 0 || candidates[0].Score < threshold {
+		return firestore.NameCandidate{}, false
+	}
+	if len(candidates) > 1 && candidates[0].Score-candidates[1].Score < ambiguityMargin {
+		return firestore.NameCandidate{}, false
+	}
+	return candidates[0], true
+}
+
+// NewTeamResolver indexes the names of teams, using the resolver to fix names shared by more than one team first.
+// A nil resolver asks the user on the terminal.
+func NewTeamResolver(ctx *Context, r NameResolver, teams []firestore.Team, teamRefs []*fs.DocumentRef) (*firestore.TeamResolver, error) {
+	for {
+		tr, dupErr := firestore.NewTeamResolver(teams, teamRefs)
+		if dupErr == nil {
+			return tr, nil
+		}
+		if err := ResolveDuplicate(ctx, r, teams, teamRefs, dupErr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ResolveDuplicate uses the resolver to fix a name shared by more than one team.
+// Changed teams are updated in teams and, if the resolver says so, written to Firestore.
+func ResolveDuplicate(ctx *Context, r NameResolver, teams []firestore.Team, teamRefs []*fs.DocumentRef, dupErr *firestore.DuplicateTeamNameError) error {
+	if r == nil {
+		r = SurveyResolver{}
+	}
+	updateNames, err := r.ReplaceName(ctx, teams, teamRefs, dupErr.Name, dupErr.Teams, dupErr.Refs, dupErr.NameType)
+	if err != nil {
+		return fmt.Errorf("failed to resolve duplicate %s name '%s': %w", dupErr.NameType, dupErr.Name, err)
+	}
+
+	for ref, t := range updateNames {
+		if r.Persist() {
+			fmt.Printf("Updating %s to eliminate %s name %s\n", ref.ID, dupErr.NameType, dupErr.Name)
+			editContext := &Context{
+				Context:         ctx.Context,
+				Force:           ctx.Force,
+				DryRun:          ctx.DryRun,
+				FirestoreClient: ctx.FirestoreClient,
+				ID:              ref.ID,
+				Team:            t,
+				Season:          ctx.Season,
+				Append:          false,
+			}
+			if err := EditTeam(editContext); err != nil {
+				return err
+			}
+		}
+		for i, r := range teamRefs {
+			if r.ID == ref.ID {
+				teams[i] = t
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveName looks up a team by name, using the resolver to pick a team if the team resolver does not recognize the name.
+// A picked name is learned by the team resolver and, if the resolver says so, added to the team in Firestore.
+func ResolveName(ctx *Context, r NameResolver, tr *firestore.TeamResolver, name string, nameType firestore.NameType) (*fs.DocumentRef, error) {
+	if ref, ok := tr.Lookup(name, nameType); ok {
+		return ref, nil
+	}
+	if r == nil {
+		r = SurveyResolver{}
+	}
+	ref, err := r.AddName(ctx, tr, name, nameType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s name '%s': %w", nameType, name, err)
+	}
+
+	if !r.Persist() || ctx.DryRun {
+		if ctx.DryRun {
+			log.Printf("DRY RUN: would add %s name %s to %s", nameType, name, ref.Path)
+		}
+		if err := tr.Learn(ref, name, nameType); err != nil {
+			return nil, fmt.Errorf("failed to learn %s name '%s': %w", nameType, name, err)
+		}
+		return ref, nil
+	}
+
+	fmt.Printf("Updating %s to add %s name %s\n", ref.ID, nameType, name)
+	if err := tr.AddAlias(ctx, ref, name, nameType); err != nil {
+		return nil, fmt.Errorf("failed to add %s name '%s' to %s: %w", nameType, name, ref.ID, err)
+	}
+	return ref, nil
+}
+
+// ResolverFlags are the command line flags that select how team names that are missing or duplicated are resolved.
+type ResolverFlags struct {
+	NonInteractive bool    `help:"Resolve unknown team names by fuzzy matching instead of prompting. Matches are not saved to the database, but are recorded in the pending aliases file for approval."`
+	MatchThreshold float64 `help:"Minimum similarity (0 to 1) needed to accept a fuzzy match of a team name." default:"0.85"`
+	PendingAliases string  `help:"JSON file where fuzzy matches and unresolved team names are recorded when running non-interactively." default:"pending-aliases.json" type:"path"`
+}
+
+// Resolver returns the NameResolver the flags select.
+func (r ResolverFlags) Resolver() NameResolver {
+	if !r.NonInteractive {
+		return SurveyResolver{}
+	}
+	return FuzzyResolver{Threshold: r.MatchThreshold, PendingFile: r.PendingAliases}
+}
@@ -0,0 +1,111 @@
+package editteams
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func testTeams() ([]firestore.Team, []*fs.DocumentRef) {
+	teams := []firestore.Team{
+		{School: "Michigan", ShortNames: []string{"UM"}},
+		{School: "Michigan State", ShortNames: []string{"MSU"}},
+		{School: "Ohio State", ShortNames: []string{"OSU"}},
+	}
+	refs := []*fs.DocumentRef{{ID: "mich"}, {ID: "msu"}, {ID: "osu"}}
+	return teams, refs
+}
+
+func TestFuzzyResolver(t *testing.T) {
+	teams, refs := testTeams()
+	pendingFile := filepath.Join(t.TempDir(), "pending.json")
+	ctx := &Context{Context: context.Background(), Season: 2022}
+	r := FuzzyResolver{Threshold: .85, PendingFile: pendingFile}
+
+	tr, err := NewTeamResolver(ctx, r, teams, refs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Normalized names are found without fuzzy matching and are not recorded.
+	if ref, err := ResolveName(ctx, r, tr, "Ohio St.", firestore.ShortName); err != nil || ref.ID != "osu" {
+		t.Errorf("expected 'Ohio St.' to resolve to osu, got %v, %v", ref, err)
+	}
+
+	ref, err := ResolveName(ctx, r, tr, "Ohio Stat", firestore.ShortName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.ID != "osu" {
+		t.Errorf("expected 'Ohio Stat' to resolve to osu, got %s", ref.ID)
+	}
+	if learned, ok := tr.Lookup("Ohio Stat", firestore.ShortName); !ok || learned.ID != "osu" {
+		t.Errorf("expected matched name to be learned")
+	}
+	if len(teams[2].ShortNames) != 1 {
+		t.Errorf("expected caller's teams to be unchanged, got %v", teams[2].ShortNames)
+	}
+
+	if _, err := ResolveName(ctx, r, tr, "Minnesota", firestore.ShortName); err == nil {
+		t.Error("expected 'Minnesota' to be unresolved")
+	}
+
+	pending, err := ReadPendingAliases(pendingFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("expected 2 pending aliases, got %v", pending)
+	}
+	if pending[0].Name != "Minnesota" || pending[0].Status != AliasUnresolved || len(pending[0].Candidates) != nPendingCandidates {
+		t.Errorf("expected unresolved 'Minnesota' with candidates, got %v", pending[0])
+	}
+	if pending[1].Name != "Ohio Stat" || pending[1].Status != AliasMatched || pending[1].TeamID != "osu" || pending[1].Season != 2022 {
+		t.Errorf("expected matched 'Ohio Stat', got %v", pending[1])
+	}
+}
+
+func TestAcceptCandidate(t *testing.T) {
+	candidates := []firestore.NameCandidate{{Score: .9}, {Score: .88}, {Score: .2}}
+	if _, ok := acceptCandidate(candidates, .85); ok {
+		t.Error("expected near tie to be rejected")
+	}
+	candidates[1].Score = .8
+	if _, ok := acceptCandidate(candidates, .85); !ok {
+		t.Error("expected clear winner to be accepted")
+	}
+	if _, ok := acceptCandidate(candidates, .95); ok {
+		t.Error("expected candidate below threshold to be rejected")
+	}
+	if _, ok := acceptCandidate(nil, 0); ok {
+		t.Error("expected no candidates to be rejected")
+	}
+}
+
+func TestFuzzyResolverDuplicate(t *testing.T) {
+	teams, refs := testTeams()
+	teams[0].ShortNames = append(teams[0].ShortNames, "MSU")
+	ctx := &Context{Context: context.Background(), Season: 2022}
+	if _, err := NewTeamResolver(ctx, FuzzyResolver{Threshold: .85}, teams, refs); err == nil {
+		t.Error("expected duplicate short name to be an error")
+	}
+}
+
+func TestPendingAliasesMerge(t *testing.T) {
+	var p PendingAliases
+	p = p.Merge(PendingAlias{Season: 2022, Name: "b", Status: AliasUnresolved})
+	p = p.Merge(PendingAlias{Season: 2022, Name: "a", Status: AliasMatched, TeamID: "x"})
+	p[1].Approved = true
+	p[1].TeamID = "y"
+	p = p.Merge(PendingAlias{Season: 2022, Name: "b", Status: AliasUnresolved})
+
+	if len(p) != 2 || p[0].Name != "a" || p[1].Name != "b" {
+		t.Fatalf("expected aliases a and b in order, got %v", p)
+	}
+	if !p[1].Approved || p[1].TeamID != "y" {
+		t.Errorf("expected approval to survive merge, got %v", p[1])
+	}
+}
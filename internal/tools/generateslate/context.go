@@ -0,0 +1,48 @@
+package generateslate
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+
+	Force  bool
+	DryRun bool
+
+	FirestoreClient *fs.Client
+
+	Season int
+	Week   int
+
+	// Output is the path of the generated slate workbook.
+	Output string
+
+	// Rankings is the path to a CSV file of "rank,team" rows giving the week's poll. Teams are looked up by other name.
+	// If empty, the most recent stored ranking of Poll is used.
+	Rankings string
+
+	// Poll is the name of the stored poll to use if Rankings is empty. If there is no such ranking either, only games involving Teams are put on the slate.
+	Poll string
+
+	// Teams are the names of teams whose games are always put on the slate, ranked or not.
+	Teams []string
+
+	// Model is the short name of the model whose predictions are used to suggest noisy spreads and superdogs.
+	Model string
+
+	// NoisySpreadMin is the smallest predicted spread for which a noisy spread is suggested.
+	NoisySpreadMin float64
+
+	// SuperdogMinSpread is the smallest predicted spread for which a game is suggested as a superdog.
+	SuperdogMinSpread float64
+
+	// NSuperdogs is the number of superdogs to suggest.
+	NSuperdogs int
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
@@ -0,0 +1,426 @@
+package generateslate
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	gfs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/tealeg/xlsx"
+)
+
+// unranked is the rank given to unranked teams when looking for the game of the week.
+const unranked = 26
+
+// Candidate is a game that might be put on the slate.
+type Candidate struct {
+	Game firestore.Game
+	Ref  *gfs.DocumentRef
+
+	// HomeRank and AwayRank are the teams' ranks, or 0 if unranked.
+	HomeRank int
+	AwayRank int
+
+	// Featured is true if the game involves a team that is always put on the slate.
+	Featured bool
+
+	// Spread is the predicted number of points in favor of the game's home team. It is only meaningful if HasSpread is true.
+	Spread    float64
+	HasSpread bool
+
+	// HomeShort and AwayShort are the short names written in game cells.
+	HomeShort string
+	AwayShort string
+
+	// HomeOther and AwayOther are the other names written in superdog cells.
+	HomeOther string
+	AwayOther string
+}
+
+// onSlate reports whether the game belongs on the slate.
+func (c Candidate) onSlate() bool {
+	return c.Featured || c.HomeRank > 0 || c.AwayRank > 0
+}
+
+func (c Candidate) rankSum() int {
+	h := c.HomeRank
+	if h == 0 {
+		h = unranked
+	}
+	a := c.AwayRank
+	if a == 0 {
+		a = unranked
+	}
+	return h + a
+}
+
+// SuggestedGame is a straight-up game on the slate.
+type SuggestedGame struct {
+	Candidate
+
+	GOTW bool
+
+	// NoisySpread is the suggested noisy spread in favor of the favorite, or 0 if the game is picked straight up.
+	NoisySpread int
+
+	HomeFavored bool
+}
+
+// SuggestedDog is a superdog game on the slate.
+type SuggestedDog struct {
+	Candidate
+
+	// Value is the suggested number of points earned by correctly picking the underdog.
+	Value int
+
+	HomeFavored bool
+}
+
+// Suggestion is a suggested slate.
+type Suggestion struct {
+	Games     []SuggestedGame
+	Superdogs []SuggestedDog
+}
+
+// Suggest picks the games for a slate from the candidates.
+// Games involving a ranked or featured team are put on the slate in order of kickoff, and the game between the best-ranked pair of teams is the game of the week.
+// Up to nDogs of the other games with a predicted spread of at least dogMin are made superdogs, closest games first, valued at half the spread.
+// No game is both picked straight up and a superdog, because a team may appear in only one slate game.
+// A noisy spread is suggested for every remaining game with a predicted spread of at least noisyMin.
+func Suggest(candidates []Candidate, noisyMin float64, dogMin float64, nDogs int) Suggestion {
+	selected := make([]Candidate, 0)
+	for _, c := range candidates {
+		if c.onSlate() {
+			selected = append(selected, c)
+		}
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Game.StartTime.Before(selected[j].Game.StartTime) })
+
+	gotw := -1
+	for i, c := range selected {
+		if gotw < 0 || c.rankSum() < selected[gotw].rankSum() {
+			gotw = i
+		}
+	}
+
+	dogs := make([]int, 0)
+	for i, c := range selected {
+		if i != gotw && c.HasSpread && c.Spread != 0 && math.Abs(c.Spread) >= dogMin {
+			dogs = append(dogs, i)
+		}
+	}
+	sort.SliceStable(dogs, func(i, j int) bool {
+		return math.Abs(selected[dogs[i]].Spread) < math.Abs(selected[dogs[j]].Spread)
+	})
+	if len(dogs) > nDogs {
+		dogs = dogs[:nDogs]
+	}
+	isDog := make(map[int]bool)
+	for _, i := range dogs {
+		isDog[i] = true
+	}
+
+	var s Suggestion
+	for i, c := range selected {
+		if isDog[i] {
+			value := int(math.Round(math.Abs(c.Spread) / 2))
+			if value < 1 {
+				value = 1
+			}
+			s.Superdogs = append(s.Superdogs, SuggestedDog{Candidate: c, Value: value, HomeFavored: c.Spread > 0})
+			continue
+		}
+		g := SuggestedGame{Candidate: c, GOTW: i == gotw, HomeFavored: c.Spread > 0}
+		if c.HasSpread && noisyMin > 0 && math.Abs(c.Spread) >= noisyMin {
+			g.NoisySpread = int(math.Round(math.Abs(c.Spread)))
+		}
+		s.Games = append(s.Games, g)
+	}
+	return s
+}
+
+func rankedName(name string, rank int) string {
+	if rank > 0 {
+		return fmt.Sprintf("#%d %s", rank, name)
+	}
+	return name
+}
+
+// GameCell formats a game the way the default slate layout writes it.
+func (g SuggestedGame) GameCell() string {
+	vs := "@"
+	if g.Game.NeutralSite {
+		vs = "vs"
+	}
+	cell := fmt.Sprintf("%s %s %s", rankedName(g.AwayShort, g.AwayRank), vs, rankedName(g.HomeShort, g.HomeRank))
+	if g.GOTW {
+		cell = "** " + cell + " **"
+	}
+	return cell
+}
+
+// NoisySpreadCell formats the noisy spread instructions the way the default slate layout writes them, or returns an empty string if there is no noisy spread.
+func (g SuggestedGame) NoisySpreadCell() string {
+	if g.NoisySpread == 0 {
+		return ""
+	}
+	fav := g.AwayShort
+	if g.HomeFavored {
+		fav = g.HomeShort
+	}
+	return fmt.Sprintf("Enter %s iff you predict %s wins by at least %d points", fav, fav, g.NoisySpread)
+}
+
+// SuperdogCell formats a superdog the way the default slate layout writes it.
+func (d SuggestedDog) SuperdogCell() string {
+	dog, fav := d.HomeOther, d.AwayOther
+	if d.HomeFavored {
+		dog, fav = fav, dog
+	}
+	return fmt.Sprintf("%s over %s (%d points, if correct)", dog, fav, d.Value)
+}
+
+// Rows lays out a suggested slate in the default slate layout: a title, one row per game with any noisy spread in the next column,
+// then the superdogs.
+func Rows(s Suggestion, title string) [][]string {
+	rows := [][]string{{title}}
+	for _, g := range s.Games {
+		rows = append(rows, []string{g.GameCell(), g.NoisySpreadCell()})
+	}
+	if len(s.Superdogs) > 0 {
+		rows = append(rows, []string{""}, []string{"Superdogs"})
+		for _, d := range s.Superdogs {
+			rows = append(rows, []string{d.SuperdogCell()})
+		}
+	}
+	return rows
+}
+
+// WriteXLSX writes rows of cells to the first sheet of an Excel workbook.
+func WriteXLSX(w io.Writer, rows [][]string) error {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Slate")
+	if err != nil {
+		return err
+	}
+	for _, r := range rows {
+		row := sheet.AddRow()
+		for _, v := range r {
+			row.AddCell().SetString(v)
+		}
+	}
+	return file.Write(w)
+}
+
+// ReadRankings reads "rank,team" rows from a CSV file, returning team ranks keyed by team ID. A header row is skipped.
+func ReadRankings(r io.Reader, tl firestore.TeamLookup) (map[string]int, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	ranks := make(map[string]int)
+	for i, rec := range records {
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("line %d: expected rank and team", i+1)
+		}
+		rank, err := strconv.Atoi(strings.TrimSpace(rec[0]))
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: failed to parse rank: %w", i+1, err)
+		}
+		name := strings.TrimSpace(rec[1])
+		ref, ok := tl.Lookup(name, firestore.OtherName)
+		if !ok {
+			return nil, fmt.Errorf("line %d: %w", i+1, firestore.NameNotFoundError{Name: name, NameType: firestore.OtherName})
+		}
+		ranks[ref.ID] = rank
+	}
+	return ranks, nil
+}
+
+func shortName(t firestore.Team) string {
+	if len(t.ShortNames) > 0 {
+		return t.ShortNames[0]
+	}
+	return t.School
+}
+
+func otherName(t firestore.Team) string {
+	if len(t.OtherNames) > 0 {
+		return t.OtherNames[0]
+	}
+	return t.School
+}
+
+// GenerateSlate suggests a slate for a week from the week's games, rankings, and model predictions and writes it as a workbook that ParseSlate can read back.
+func GenerateSlate(ctx *Context) error {
+	if !ctx.Force && !ctx.DryRun {
+		if _, err := os.Stat(ctx.Output); err == nil {
+			return fmt.Errorf("GenerateSlate: refusing to overwrite '%s': explicitly override with --force argument", ctx.Output)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("GenerateSlate: failed to check '%s': %w", ctx.Output, err)
+		}
+	}
+
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to get season: %w", err)
+	}
+
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to get week: %w", err)
+	}
+
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to get games: %w", err)
+	}
+
+	tr, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to get teams: %w", err)
+	}
+
+	ranks := make(map[string]int)
+	if ctx.Rankings != "" {
+		f, err := os.Open(ctx.Rankings)
+		if err != nil {
+			return fmt.Errorf("GenerateSlate: failed to open rankings: %w", err)
+		}
+		defer f.Close()
+		ranks, err = ReadRankings(f, tr)
+		if err != nil {
+			return fmt.Errorf("GenerateSlate: failed to read rankings '%s': %w", ctx.Rankings, err)
+		}
+	} else if ctx.Poll != "" {
+		ranking, _, err := firestore.GetLatestRanking(ctx, seasonRef, ctx.Week, ctx.Poll)
+		if _, ok := err.(firestore.NoRankingError); ok {
+			log.Printf("%v: only games involving featured teams will be put on the slate", err)
+		} else if err != nil {
+			return fmt.Errorf("GenerateSlate: failed to get ranking: %w", err)
+		} else {
+			ranks = ranking.Ranks()
+		}
+	} else {
+		log.Print("No rankings given: only games involving featured teams will be put on the slate")
+	}
+
+	featured := make(map[string]struct{})
+	for _, name := range ctx.Teams {
+		ref, err := tr.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("GenerateSlate: %w", err)
+		}
+		featured[ref.ID] = struct{}{}
+	}
+
+	models, modelRefs, err := firestore.GetModels(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to get models: %w", err)
+	}
+	modelRef, ok := firestore.NewModelRefsByShortName(models, modelRefs)[ctx.Model]
+	if !ok {
+		return fmt.Errorf("GenerateSlate: model '%s' not found", ctx.Model)
+	}
+
+	candidates := make([]Candidate, 0, len(games))
+	for i, g := range games {
+		home, ok := tr.Team(g.HomeTeam)
+		if !ok {
+			return fmt.Errorf("GenerateSlate: home team %s of game %s not found", g.HomeTeam.ID, gameRefs[i].ID)
+		}
+		away, ok := tr.Team(g.AwayTeam)
+		if !ok {
+			return fmt.Errorf("GenerateSlate: away team %s of game %s not found", g.AwayTeam.ID, gameRefs[i].ID)
+		}
+		_, homeFeatured := featured[g.HomeTeam.ID]
+		_, awayFeatured := featured[g.AwayTeam.ID]
+		c := Candidate{
+			Game:      g,
+			Ref:       gameRefs[i],
+			HomeRank:  ranks[g.HomeTeam.ID],
+			AwayRank:  ranks[g.AwayTeam.ID],
+			Featured:  homeFeatured || awayFeatured,
+			HomeShort: shortName(home),
+			AwayShort: shortName(away),
+			HomeOther: otherName(home),
+			AwayOther: otherName(away),
+		}
+		if !c.onSlate() {
+			continue
+		}
+
+		pred, _, found, err := firestore.GetPredictionByModel(ctx, ctx.FirestoreClient, gameRefs[i], modelRef)
+		if err != nil {
+			return fmt.Errorf("GenerateSlate: failed to get prediction: %w", err)
+		}
+		if found {
+			c.HasSpread = true
+			c.Spread = pred.Spread
+			if pred.HomeTeam.ID != g.HomeTeam.ID {
+				c.Spread = -c.Spread
+			}
+		} else {
+			log.Printf("No %s prediction for game %s", ctx.Model, g)
+		}
+		candidates = append(candidates, c)
+	}
+
+	suggestion := Suggest(candidates, ctx.NoisySpreadMin, ctx.SuperdogMinSpread, ctx.NSuperdogs)
+	rows := Rows(suggestion, fmt.Sprintf("Season %d Week %d", ctx.Season, ctx.Week))
+	printSuggestion(suggestion)
+
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would write %d games and %d superdogs to %s", len(suggestion.Games), len(suggestion.Superdogs), ctx.Output)
+		return nil
+	}
+
+	f, err := os.Create(ctx.Output)
+	if err != nil {
+		return fmt.Errorf("GenerateSlate: failed to create '%s': %w", ctx.Output, err)
+	}
+	defer f.Close()
+	if err := WriteXLSX(f, rows); err != nil {
+		return fmt.Errorf("GenerateSlate: failed to write '%s': %w", ctx.Output, err)
+	}
+	log.Printf("Wrote %d games and %d superdogs to %s", len(suggestion.Games), len(suggestion.Superdogs), ctx.Output)
+	return nil
+}
+
+func printSuggestion(s Suggestion) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Game", "Spread", "Slate"})
+	for _, g := range s.Games {
+		slate := "straight up"
+		if g.NoisySpread != 0 {
+			slate = g.NoisySpreadCell()
+		}
+		t.AppendRow(table.Row{g.GameCell(), spreadString(g.Candidate), slate})
+	}
+	for _, d := range s.Superdogs {
+		t.AppendRow(table.Row{SuggestedGame{Candidate: d.Candidate}.GameCell(), spreadString(d.Candidate), d.SuperdogCell()})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+func spreadString(c Candidate) string {
+	if !c.HasSpread {
+		return ""
+	}
+	return fmt.Sprintf("%+0.1f", c.Spread)
+}
@@ -0,0 +1,141 @@
+package generateslate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/parseslate"
+)
+
+var kickoff = time.Date(2021, 10, 2, 12, 0, 0, 0, time.UTC)
+
+var otherNames = map[string]string{
+	"MICH": "Michigan",
+	"WISC": "Wisconsin",
+	"IND":  "Indiana",
+	"PSU":  "Penn State",
+	"OSU":  "Ohio State",
+	"RUT":  "Rutgers",
+	"IOWA": "Iowa",
+	"MD":   "Maryland",
+	"ILL":  "Illinois",
+	"PUR":  "Purdue",
+}
+
+func candidate(id string, away string, home string, awayRank int, homeRank int, spread float64, hours int) Candidate {
+	return Candidate{
+		Game: firestore.Game{
+			HomeTeam:  &fs.DocumentRef{ID: strings.ToLower(home)},
+			AwayTeam:  &fs.DocumentRef{ID: strings.ToLower(away)},
+			StartTime: kickoff.Add(time.Duration(hours) * time.Hour),
+		},
+		Ref:       &fs.DocumentRef{ID: id},
+		HomeRank:  homeRank,
+		AwayRank:  awayRank,
+		Spread:    spread,
+		HasSpread: spread != 0,
+		HomeShort: home,
+		AwayShort: away,
+		HomeOther: otherNames[home],
+		AwayOther: otherNames[away],
+	}
+}
+
+func testCandidates() []Candidate {
+	return []Candidate{
+		candidate("g1", "MICH", "WISC", 1, 12, -3, 3),
+		candidate("g2", "IND", "PSU", 0, 4, 20.5, 0),
+		candidate("g3", "OSU", "RUT", 7, 0, -14, 6),
+		candidate("g4", "IOWA", "MD", 5, 0, -7.5, 1),
+		candidate("g5", "ILL", "PUR", 0, 0, 1, 2),
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	s := Suggest(testCandidates(), 10, 7, 1)
+
+	if len(s.Games) != 3 || len(s.Superdogs) != 1 {
+		t.Fatalf("expected 3 games and 1 superdog, got %d and %d", len(s.Games), len(s.Superdogs))
+	}
+	order := []string{"g2", "g1", "g3"}
+	for i, g := range s.Games {
+		if g.Ref.ID != order[i] {
+			t.Errorf("game %d: expected %s, got %s", i, order[i], g.Ref.ID)
+		}
+	}
+	if !s.Games[1].GOTW {
+		t.Errorf("expected g1 to be the game of the week")
+	}
+	if s.Games[0].NoisySpread != 21 || !s.Games[0].HomeFavored {
+		t.Errorf("expected g2 noisy spread 21 for home, got %d (home favored %t)", s.Games[0].NoisySpread, s.Games[0].HomeFavored)
+	}
+	if s.Games[1].NoisySpread != 0 {
+		t.Errorf("expected no noisy spread for g1, got %d", s.Games[1].NoisySpread)
+	}
+	if d := s.Superdogs[0]; d.Ref.ID != "g4" || d.Value != 4 || d.HomeFavored {
+		t.Errorf("expected superdog g4 worth 4 with away favored, got %s worth %d (home favored %t)", d.Ref.ID, d.Value, d.HomeFavored)
+	}
+}
+
+// TestRoundTrip checks that a generated slate parses back into the games that were suggested.
+func TestRoundTrip(t *testing.T) {
+	candidates := testCandidates()
+	candidates[4].Featured = true
+	s := Suggest(candidates, 10, 7, 2)
+
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, Rows(s, "Season 2021 Week 5")); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := parseslate.XLSXReader{}.ReadRows(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(firestore.TeamRefsByName)
+	games := make([]firestore.Game, len(candidates))
+	refs := make([]*fs.DocumentRef, len(candidates))
+	for i, c := range candidates {
+		names[c.HomeShort] = c.Game.HomeTeam
+		names[c.AwayShort] = c.Game.AwayTeam
+		names[c.HomeOther] = c.Game.HomeTeam
+		names[c.AwayOther] = c.Game.AwayTeam
+		games[i] = c.Game
+		refs[i] = c.Ref
+	}
+	layout := parseslate.DefaultLayout()
+	if err := layout.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	sgames, errs := parseslate.ParseRows(rows, layout, names, firestore.NewGameRefsByMatchup(games, refs))
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if len(sgames) != len(s.Games)+len(s.Superdogs) {
+		t.Fatalf("expected %d slate games, got %d", len(s.Games)+len(s.Superdogs), len(sgames))
+	}
+	for i, g := range s.Games {
+		sg := sgames[i]
+		if sg.Game.ID != g.Ref.ID || sg.GOTW != g.GOTW || sg.HomeRank != g.HomeRank || sg.AwayRank != g.AwayRank || sg.Superdog {
+			t.Errorf("game %d: expected %s (gotw %t, ranks %d/%d), got %+v", i, g.Ref.ID, g.GOTW, g.HomeRank, g.AwayRank, sg)
+		}
+		noisy := g.NoisySpread
+		if !g.HomeFavored {
+			noisy = -noisy
+		}
+		if sg.NoisySpread != noisy {
+			t.Errorf("game %d: expected noisy spread %d, got %d", i, noisy, sg.NoisySpread)
+		}
+	}
+	for i, d := range s.Superdogs {
+		sg := sgames[len(s.Games)+i]
+		if sg.Game.ID != d.Ref.ID || !sg.Superdog || sg.Value != d.Value || sg.HomeFavored != d.HomeFavored {
+			t.Errorf("superdog %d: expected %s worth %d, got %+v", i, d.Ref.ID, d.Value, sg)
+		}
+	}
+}
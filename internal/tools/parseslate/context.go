@@ -4,6 +4,9 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
+	"github.com/reallyasi9/b1gpickem/internal/tools/slatediff"
 )
 
 type Context struct {
@@ -17,6 +20,24 @@ type Context struct {
 	Season int
 	Week   int
 	Slate  string
+
+	// Layout is the path to a JSON file describing the layout of the slate. If empty, the default layout is used.
+	Layout string
+	// Format overrides the slate file format given in the layout ("xlsx", "csv", or "ods").
+	Format string
+
+	// Migrate moves picks made against the previous slate to the matching games in the new slate.
+	Migrate bool
+	// Confirmer confirms the migration of picks whose games' terms changed. If nil, those picks are not migrated.
+	Confirmer slatediff.Confirmer
+	// Actor is the authenticated commissioner parsing the slate, as recorded in the pick change log of migrated picks.
+	Actor firestore.Picker
+
+	// Poll is the name of the poll whose most recent stored ranking is compared with the ranks on the slate. If empty, ranks are not checked.
+	Poll string
+
+	// Resolver resolves team names that are missing or duplicated. If nil, the user is asked on the terminal.
+	Resolver editteams.NameResolver
 }
 
 func NewContext(ctx context.Context) *Context {
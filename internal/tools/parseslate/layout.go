@@ -0,0 +1,181 @@
+package parseslate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Layout declares where and how games are found in a slate.
+// Layouts are stored as JSON so that a change in the commissioner's template means editing a config file rather than code.
+// Any field left empty in the JSON takes the value from DefaultLayout.
+type Layout struct {
+	// Format is the file format of the slate: "xlsx", "csv", or "ods". If empty, the format is guessed from the slate's file extension.
+	Format string `json:"format,omitempty"`
+
+	// Sheet is the name of the sheet to read from workbook formats. If empty, the first sheet is read.
+	Sheet string `json:"sheet,omitempty"`
+
+	// Game matches a straight-up or noisy spread game.
+	// Named capture groups: "gotw" (non-empty marks the game of the week), "away_rank", "away", "location", "home_rank", and "home".
+	// Team names are short names.
+	Game *CellPattern `json:"game,omitempty"`
+
+	// NeutralMarkers are the values of the Game pattern's "location" group that mark a neutral site game (case insensitive).
+	NeutralMarkers []string `json:"neutral_markers,omitempty"`
+
+	// NoisySpread matches the noisy spread instructions for a game.
+	// Named capture groups: "favorite" (a short name) and "spread".
+	NoisySpread *CellPattern `json:"noisy_spread,omitempty"`
+
+	// NoisySpreadOffset is the number of columns to the right of a game cell where its noisy spread instructions are found.
+	NoisySpreadOffset int `json:"noisy_spread_offset,omitempty"`
+
+	// Superdog matches a superdog game.
+	// Named capture groups: "underdog", "favorite" (both other names), and "value".
+	Superdog *CellPattern `json:"superdog,omitempty"`
+}
+
+// CellPattern is a regular expression to match against the contents of slate cells.
+type CellPattern struct {
+	// Pattern is the regular expression with named capture groups.
+	Pattern string `json:"pattern"`
+
+	// Columns are the (zero-indexed) columns searched for matches. If empty, all columns are searched.
+	Columns []int `json:"columns,omitempty"`
+
+	re     *regexp.Regexp
+	groups map[string]int
+}
+
+// DefaultLayout is the layout of Luke's default slate template.
+func DefaultLayout() Layout {
+	return Layout{
+		Game: &CellPattern{
+			Pattern: `^\s*(?P<gotw>\*\*)?\s*(?:#\s*(?P<away_rank>\d+)\s+)?(?P<away>.*?)\s+(?P<location>(?i:@|at|vs))\s+(?:#\s*(?P<home_rank>\d+)\s+)?(?P<home>.*?)(?:\s*\*\*)?\s*$`,
+		},
+		NeutralMarkers: []string{"vs"},
+		NoisySpread: &CellPattern{
+			Pattern: `\s*(?i:Enter\s+(?P<favorite>.*?)\s+iff\s+you\s+predict\s+.*?\s+wins\s+by\s+at\s+least\s+(?P<spread>\d+)\s+points)`,
+		},
+		NoisySpreadOffset: 1,
+		Superdog: &CellPattern{
+			Pattern: `(?i:\s*(?:#\s*\d+\s+)?(?P<underdog>.*?)\s+over\s+(?:#\s*\d+\s+)?(?P<favorite>.*?)\s+\(\s*(?P<value>\d+)\s+points,?\s+if\s+correct\s*\))`,
+		},
+	}
+}
+
+// ReadLayout reads a JSON layout, filling in unspecified fields from DefaultLayout and compiling the patterns.
+func ReadLayout(r io.Reader) (Layout, error) {
+	layout := Layout{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&layout); err != nil {
+		return layout, fmt.Errorf("ReadLayout: failed to decode layout: %w", err)
+	}
+
+	def := DefaultLayout()
+	if layout.Game == nil {
+		layout.Game = def.Game
+	}
+	if len(layout.NeutralMarkers) == 0 {
+		layout.NeutralMarkers = def.NeutralMarkers
+	}
+	if layout.NoisySpread == nil {
+		layout.NoisySpread = def.NoisySpread
+	}
+	if layout.NoisySpreadOffset == 0 {
+		layout.NoisySpreadOffset = def.NoisySpreadOffset
+	}
+	if layout.Superdog == nil {
+		layout.Superdog = def.Superdog
+	}
+
+	if err := layout.Compile(); err != nil {
+		return layout, fmt.Errorf("ReadLayout: %w", err)
+	}
+	return layout, nil
+}
+
+// WriteLayout writes a layout as indented JSON, suitable for editing and reading back with ReadLayout.
+func WriteLayout(w io.Writer, layout Layout) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(layout)
+}
+
+// Compile compiles the layout's patterns and checks that they capture the required groups.
+func (l *Layout) Compile() error {
+	if err := l.Game.compile("game", "away", "location", "home"); err != nil {
+		return err
+	}
+	if err := l.NoisySpread.compile("noisy_spread", "favorite", "spread"); err != nil {
+		return err
+	}
+	if err := l.Superdog.compile("superdog", "underdog", "favorite", "value"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsNeutral reports whether the location captured by the Game pattern marks a neutral site.
+func (l Layout) IsNeutral(location string) bool {
+	for _, m := range l.NeutralMarkers {
+		if strings.EqualFold(strings.TrimSpace(location), m) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CellPattern) compile(name string, required ...string) error {
+	if c == nil {
+		return fmt.Errorf("%s pattern missing", name)
+	}
+	re, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return fmt.Errorf("failed to compile %s pattern: %w", name, err)
+	}
+	c.re = re
+	c.groups = make(map[string]int)
+	for i, g := range re.SubexpNames() {
+		if g != "" {
+			c.groups[g] = i
+		}
+	}
+	for _, g := range required {
+		if _, ok := c.groups[g]; !ok {
+			return fmt.Errorf("%s pattern missing required capture group '%s'", name, g)
+		}
+	}
+	return nil
+}
+
+// InColumn reports whether the pattern should be matched against cells in the given column.
+func (c *CellPattern) InColumn(col int) bool {
+	if len(c.Columns) == 0 {
+		return true
+	}
+	for _, x := range c.Columns {
+		if x == col {
+			return true
+		}
+	}
+	return false
+}
+
+// Match matches the pattern against a cell, returning the named groups that were captured, or nil if the pattern does not match.
+func (c *CellPattern) Match(cell string) map[string]string {
+	submatches := c.re.FindStringSubmatch(cell)
+	if len(submatches) == 0 {
+		return nil
+	}
+	m := make(map[string]string)
+	for g, i := range c.groups {
+		m[g] = submatches[i]
+	}
+	return m
+}
@@ -0,0 +1,162 @@
+package parseslate
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+var testShortNames = firestore.TeamRefsByName{
+	"ILL":  &fs.DocumentRef{ID: "illinois"},
+	"PUR":  &fs.DocumentRef{ID: "purdue"},
+	"MICH": &fs.DocumentRef{ID: "michigan"},
+}
+
+var testOtherNames = firestore.TeamRefsByName{
+	"Illinois": &fs.DocumentRef{ID: "illinois"},
+	"Purdue":   &fs.DocumentRef{ID: "purdue"},
+}
+
+func defaultLayout(t *testing.T) Layout {
+	layout := DefaultLayout()
+	if err := layout.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return layout
+}
+
+func TestParseGameDefaultLayout(t *testing.T) {
+	layout := defaultLayout(t)
+	tests := []struct {
+		cell     string
+		found    bool
+		gotw     bool
+		neutral  bool
+		awayRank int
+		homeRank int
+	}{
+		{"ILL @ PUR", true, false, false, 0, 0},
+		{"** #3 MICH vs ILL **", true, true, true, 3, 0},
+		{"PUR at #12 ILL", true, false, false, 0, 12},
+		{"Nothing to see here", false, false, false, 0, 0},
+	}
+	for _, test := range tests {
+		matchup, homeRank, awayRank, gotw, found, err := parseGame(test.cell, 0, layout, testShortNames)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.cell, err)
+			continue
+		}
+		if found != test.found {
+			t.Errorf("%s: expected found %t, got %t", test.cell, test.found, found)
+			continue
+		}
+		if !found {
+			continue
+		}
+		if gotw != test.gotw || matchup.Neutral != test.neutral || awayRank != test.awayRank || homeRank != test.homeRank {
+			t.Errorf("%s: expected gotw %t, neutral %t, ranks %d/%d, got %t, %t, %d/%d", test.cell, test.gotw, test.neutral, test.awayRank, test.homeRank, gotw, matchup.Neutral, awayRank, homeRank)
+		}
+	}
+
+	_, _, _, _, _, err := parseGame("ILL @ OSU", 0, layout, testShortNames)
+	if _, ok := err.(firestore.NameNotFoundError); !ok {
+		t.Errorf("expected NameNotFoundError, got %v", err)
+	}
+}
+
+func TestParseNoisySpreadAndDogDefaultLayout(t *testing.T) {
+	layout := defaultLayout(t)
+
+	favorite, spread, found, err := parseNoisySpread("Enter PUR iff you predict PUR wins by at least 7 points", 1, layout, testShortNames)
+	if err != nil || !found || favorite != "purdue" || spread != 7 {
+		t.Errorf("noisy spread: got %s, %d, %t, %v", favorite, spread, found, err)
+	}
+
+	matchup, favorite, value, found, err := parseDog("Illinois over Purdue (5 points, if correct)", 0, layout, testOtherNames)
+	if err != nil || !found || matchup.Home != "illinois" || favorite != "purdue" || value != 5 {
+		t.Errorf("superdog: got %+v, %s, %d, %t, %v", matchup, favorite, value, found, err)
+	}
+}
+
+func TestReadLayout(t *testing.T) {
+	config := `{
+		"format": "csv",
+		"game": {"pattern": "^(?P<away>\\w+) (?P<location>AT|N) (?P<home>\\w+)$", "columns": [2]},
+		"neutral_markers": ["N"]
+	}`
+	layout, err := ReadLayout(strings.NewReader(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if layout.Format != "csv" {
+		t.Errorf("expected format csv, got %s", layout.Format)
+	}
+	if layout.NoisySpreadOffset != 1 || layout.Superdog == nil {
+		t.Error("expected unspecified fields to take default values")
+	}
+
+	matchup, _, _, _, found, err := parseGame("ILL N PUR", 2, layout, testShortNames)
+	if err != nil || !found || !matchup.Neutral || matchup.Away != "illinois" || matchup.Home != "purdue" {
+		t.Errorf("custom layout: got %+v, %t, %v", matchup, found, err)
+	}
+	if _, _, _, _, found, _ := parseGame("ILL N PUR", 0, layout, testShortNames); found {
+		t.Error("custom layout: expected no match outside configured columns")
+	}
+
+	if _, err := ReadLayout(strings.NewReader(`{"game": {"pattern": "(?P<away>.*) @ (.*)"}}`)); err == nil {
+		t.Error("expected error for pattern missing required groups")
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	rows, err := CSVReader{}.ReadRows([]byte("ILL @ PUR,Enter PUR iff you predict PUR wins by at least 7 points\n\"Illinois over Purdue (5 points, if correct)\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[0]) != 2 || len(rows[1]) != 1 {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	if rows[1][0] != "Illinois over Purdue (5 points, if correct)" {
+		t.Errorf("unexpected cell %s", rows[1][0])
+	}
+}
+
+func TestODSReader(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+<office:body><office:spreadsheet>
+<table:table table:name="Ignored"><table:table-row><table:table-cell><text:p>nope</text:p></table:table-cell></table:table-row></table:table>
+<table:table table:name="Slate">
+<table:table-row><table:table-cell table:number-columns-repeated="2"/><table:table-cell><text:p>ILL @ PUR</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell><text:p>x</text:p></table:table-cell></table:table-row>
+</table:table>
+</office:spreadsheet></office:body>
+</office:document-content>`
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	w, err := z.Create("content.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte(content))
+	z.Close()
+
+	rows, err := ODSReader{Sheet: "Slate"}.ReadRows(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d: %v", len(rows), rows)
+	}
+	if len(rows[0]) != 3 || rows[0][2] != "ILL @ PUR" {
+		t.Errorf("unexpected first row %v", rows[0])
+	}
+
+	if _, err := (ODSReader{Sheet: "Missing"}).ReadRows(buf.Bytes()); err == nil {
+		t.Error("expected error for missing sheet")
+	}
+}
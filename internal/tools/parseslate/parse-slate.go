@@ -2,13 +2,11 @@ package parseslate
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/url"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -17,7 +15,8 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
-	"github.com/tealeg/xlsx"
+	"github.com/reallyasi9/b1gpickem/internal/tools/rankings"
+	"github.com/reallyasi9/b1gpickem/internal/tools/slatediff"
 )
 
 func ParseSlate(ctx *Context) error {
@@ -50,70 +49,17 @@ func ParseSlate(ctx *Context) error {
 		return fmt.Errorf("ParseSlate: failed to get teams: %w", err)
 	}
 
-	var tlOther firestore.TeamRefsByName
-	var err2 *firestore.DuplicateTeamNameError
-	for {
-		tlOther, err2 = firestore.NewTeamRefsByOtherName(teams, teamRefs)
-		if err2 == nil {
-			break
-		}
-
-		updateNames, err := editteams.SurveyReplaceName(teams, teamRefs, err2.Name, err2.Teams, err2.Refs, firestore.OtherName)
-		if err != nil {
-			panic(err)
-		}
-
-		for ref, t := range updateNames {
-			fmt.Printf("Updating %s to eliminate %s (names now [%s])\n", ref.ID, err2.Name, strings.Join(t.OtherNames, ", "))
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	editContext := &editteams.Context{
+		Context:         ctx.Context,
+		Force:           ctx.Force,
+		DryRun:          ctx.DryRun,
+		FirestoreClient: ctx.FirestoreClient,
+		Season:          ctx.Season,
 	}
 
-	var tlShort firestore.TeamRefsByName
-	var err3 *firestore.DuplicateTeamNameError
-	for {
-		tlShort, err3 = firestore.NewTeamRefsByShortName(teams, teamRefs)
-		if err3 == nil {
-			break
-		}
-
-		updateNames, err := editteams.SurveyReplaceName(teams, teamRefs, err3.Name, err3.Teams, err3.Refs, firestore.ShortName)
-		if err != nil {
-			panic(err)
-		}
-
-		for ref, t := range updateNames {
-			fmt.Printf("Updating %s to eliminate %s (names now [%s])\n", ref.ID, err3.Name, strings.Join(t.ShortNames, ", "))
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	tr, err := editteams.NewTeamResolver(editContext, ctx.Resolver, teams, teamRefs)
+	if err != nil {
+		return fmt.Errorf("ParseSlate: failed to make team resolver: %w", err)
 	}
 
 	slurp, err := io.ReadAll(reader)
@@ -121,54 +67,47 @@ func ParseSlate(ctx *Context) error {
 		return fmt.Errorf("ParseSlate: failed to read slate file: %w", err)
 	}
 
+	layout, err := getLayout(ctx, ctx.Layout)
+	if err != nil {
+		return fmt.Errorf("ParseSlate: failed to read layout '%s': %w", ctx.Layout, err)
+	}
+	if ctx.Format != "" {
+		layout.Format = ctx.Format
+	}
+
+	rows, err := readSlate(slurp, ctx.Slate, layout)
+	if err != nil {
+		return fmt.Errorf("ParseSlate: failed to read slate rows: %w", err)
+	}
+
 	var sgames []firestore.SlateGame
 	var errs []error
 
-SlateParseLoop:
 	for {
-		sgames, errs = parseSheet(slurp, tlOther, tlShort, gl)
+		sgames, errs = ParseRows(rows, layout, tr, gl)
 		if errs == nil {
 			break
 		}
 
+		// Resolve every missing name before parsing again so that all unresolved names are reported at once.
+		var unresolved []error
 		for _, err := range errs {
-			if e, ok := err.(firestore.NameNotFoundError); ok {
-				t, ref, err2 := editteams.SurveyAddName(teams, teamRefs, e.Name, e.NameType)
-				if err2 != nil {
-					panic(err2)
-				}
-
-				fmt.Printf("Updating %s to add %s name %s\n", ref.ID, e.NameType, e.Name)
-
-				editContext := &editteams.Context{
-					Context:         ctx.Context,
-					Force:           ctx.Force,
-					DryRun:          ctx.DryRun,
-					FirestoreClient: ctx.FirestoreClient,
-					ID:              ref.ID,
-					Team:            t,
-					Season:          ctx.Season,
-					Append:          false,
-				}
-				err := editteams.EditTeam(editContext)
-				if err != nil {
-					panic(err)
-				}
-
-				// One error fixed: try parsing again after adding team to lookup map
-				switch e.NameType {
-				case firestore.ShortName:
-					tlShort[e.Name] = ref
-				case firestore.OtherName:
-					tlOther[e.Name] = ref
-				default:
-					panic(errors.New("unrecognized name type"))
-				}
-				continue SlateParseLoop
-
-			} else {
+			e, ok := err.(firestore.NameNotFoundError)
+			if !ok {
 				return fmt.Errorf("ParseSlate: failed to parse games from slate file: %w", err)
 			}
+			if _, err := editteams.ResolveName(editContext, ctx.Resolver, tr, e.Name, e.NameType); err != nil {
+				unresolved = append(unresolved, err)
+			}
+		}
+		if len(unresolved) > 0 {
+			return fmt.Errorf("ParseSlate: failed to resolve %d team names: %v", len(unresolved), unresolved)
+		}
+	}
+
+	if ctx.Poll != "" {
+		if err := checkRanks(ctx, seasonRef, sgames, games, gameRefs); err != nil {
+			return fmt.Errorf("ParseSlate: %w", err)
 		}
 	}
 
@@ -188,6 +127,12 @@ SlateParseLoop:
 		Created:  ct,
 		FileName: ctx.Slate,
 	}
+	// Remember the previous slate so the new one can be compared to it.
+	_, prevSlateRefs, err := firestore.GetSlates(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("ParseSlate: failed to get previous slates: %w", err)
+	}
+
 	slateRef := weekRef.Collection(firestore.SLATES_COLLECTION).NewDoc()
 	err = ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
 		var err error
@@ -230,6 +175,33 @@ SlateParseLoop:
 		return fmt.Errorf("ParseSlate: failed to store slate and games in firestore: %w", err)
 	}
 
+	if len(prevSlateRefs) == 0 {
+		return nil
+	}
+	diff, stale, err := slatediff.Compare(ctx, weekRef, prevSlateRefs[0], slateRef)
+	if err != nil {
+		return fmt.Errorf("ParseSlate: failed to compare with previous slate: %w", err)
+	}
+	fmt.Printf("Changes from previous slate %s:\n%s\n", prevSlateRefs[0].ID, diff)
+	slatediff.PrintStalePicks(stale)
+	if len(stale) == 0 {
+		return nil
+	}
+	if !ctx.Migrate {
+		fmt.Println("Run `b1gtool slate diff --migrate` to migrate stale picks to the new slate.")
+		return nil
+	}
+	mctx := slatediff.NewContext(ctx)
+	mctx.FirestoreClient = ctx.FirestoreClient
+	mctx.Season = ctx.Season
+	mctx.Week = ctx.Week
+	mctx.Confirmer = ctx.Confirmer
+	mctx.Actor = ctx.Actor
+	mctx.Source = "b1gtool slate parse"
+	if err := slatediff.Migrate(mctx, stale); err != nil {
+		return fmt.Errorf("ParseSlate: %w", err)
+	}
+
 	return nil
 }
 
@@ -335,24 +307,60 @@ func getCreationTime(ctx context.Context, f string) (time.Time, error) {
 	return t, nil
 }
 
-func parseSheet(slurp []byte, tlOther, tlShort firestore.TeamRefsByName, gl firestore.GameRefsByMatchup) ([]firestore.SlateGame, []error) {
-	xl, err := xlsx.OpenBinary(slurp)
+// readSlate reads the rows of the slate using the reader appropriate for the layout and slate file.
+func readSlate(slurp []byte, fileName string, layout Layout) ([][]string, error) {
+	reader, err := NewSlateReader(layout.Format, fileName, layout.Sheet)
 	if err != nil {
-		return nil, []error{err}
+		return nil, err
 	}
+	return reader.ReadRows(slurp)
+}
 
-	sheet := xl.Sheets[0]
-	log.Printf("Reading sheet name: %s", sheet.Name)
+// checkRanks reports the slate ranks that do not match the most recent stored ranking of ctx.Poll.
+// A missing ranking is not an error, because rankings are not always stored before the slate arrives.
+func checkRanks(ctx *Context, seasonRef *fs.DocumentRef, sgames []firestore.SlateGame, games []firestore.Game, gameRefs []*fs.DocumentRef) error {
+	ranking, _, err := firestore.GetLatestRanking(ctx, seasonRef, ctx.Week, ctx.Poll)
+	if _, ok := err.(firestore.NoRankingError); ok {
+		log.Printf("Not checking slate ranks: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ranking: %w", err)
+	}
+	mismatches, err := firestore.CompareSlateRanks(sgames, rankings.GamesByID(games, gameRefs), ranking.Ranks())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Slate ranks compared with %s:\n", ranking.Poll)
+	rankings.PrintMismatches(mismatches)
+	return nil
+}
+
+// getLayout reads the layout from a local file or Google Storage URL, or returns the default layout if the file name is empty.
+func getLayout(ctx context.Context, fileName string) (Layout, error) {
+	if fileName == "" {
+		layout := DefaultLayout()
+		return layout, layout.Compile()
+	}
+	r, err := getFileOrGSReader(ctx, fileName)
+	if err != nil {
+		return Layout{}, err
+	}
+	defer r.Close()
+	return ReadLayout(r)
+}
 
+// ParseRows parses slate games from rows of cells read from a slate, reporting every cell that cannot be parsed.
+func ParseRows(rows [][]string, layout Layout, tl firestore.TeamLookup, gl firestore.GameRefsByMatchup) ([]firestore.SlateGame, []error) {
 	games := make([]firestore.SlateGame, 0)
 
 	// catch all the errors from all the cells and report them all rather than stopping after the first
 	errors := make([]error, 0)
 
-	for irow, row := range sheet.Rows {
-		for icol, cell := range row.Cells {
+	for irow, row := range rows {
+		for icol, cell := range row {
 
-			matchup, homeRank, awayRank, gotw, found, err := parseGame(cell.Value, tlShort)
+			matchup, homeRank, awayRank, gotw, found, err := parseGame(cell, icol, layout, tl)
 			if err != nil {
 				errors = append(errors, err)
 				continue
@@ -381,9 +389,9 @@ func parseSheet(slurp []byte, tlOther, tlShort firestore.TeamRefsByName, gl fire
 					NeutralDisagreement: wn,
 					Value:               value,
 				}
-				// check the immediate next column for noise
-				if len(row.Cells) != icol+1 {
-					favorite, spread, found, err := parseNoisySpread(row.Cells[icol+1].Value, tlShort)
+				// check the noisy spread column for noise
+				if ncol := icol + layout.NoisySpreadOffset; ncol < len(row) {
+					favorite, spread, found, err := parseNoisySpread(row[ncol], ncol, layout, tl)
 					if err != nil {
 						errors = append(errors, err)
 						continue
@@ -403,7 +411,7 @@ func parseSheet(slurp []byte, tlOther, tlShort firestore.TeamRefsByName, gl fire
 				break
 			}
 
-			matchup, favorite, value, found, err := parseDog(cell.Value, tlOther)
+			matchup, favorite, value, found, err := parseDog(cell, icol, layout, tl)
 			if err != nil {
 				errors = append(errors, err)
 			}
@@ -442,33 +450,22 @@ func parseSheet(slurp []byte, tlOther, tlShort firestore.TeamRefsByName, gl fire
 	return games, errors
 }
 
-// ^(\*\*)?         # Marker for GOTW
-// (?:\#(\d+)\s+)?  # Optional rank for Team 1
-// (.*?)\s+         # Team 1 (home) name in LUKE format
-// (@|vs)\s+        # Whether or not Team 2 is away (@) or if the game is at a neutral site (vs)
-// (?:\#(\d+)\s+)?  # Optional rank for Team 2
-// (.*?)            # Team 2 (away) name in LUKE format
-// \1?\s*$          # Marker for GOTW
-var gameRe = regexp.MustCompile(`^\s*(\*\*)?\s*(?:#\s*(\d+)\s+)?(.*?)\s+((?i:@|at|vs))\s+(?:#\s*(\d+)\s+)?(.*?)(?:\s*\*\*)?\s*$`)
-
-var noiseRe = regexp.MustCompile(`\s*(?i:Enter\s+(.*?)\s+iff\s+you\s+predict\s+.*?\s+wins\s+by\s+at\s+least\s+(\d+)\s+points)`)
-
-var sdRe = regexp.MustCompile(`(?i:\s*(?:#\s*\d+\s+)?(.*?)\s+over\s+(?:#\s*\d+\s+)?(.*?)\s+\(\s*(\d+)\s+points,?\s+if\s+correct\s*\))`)
-
-// parseGame parses game information in Luke's default format
-func parseGame(cell string, tl firestore.TeamRefsByName) (matchup firestore.Matchup, homeRank int, awayRank int, gotw bool, found bool, err error) {
-
-	submatches := gameRe.FindStringSubmatch(cell)
-	if len(submatches) == 0 {
+// parseGame parses game information using the layout's game pattern.
+func parseGame(cell string, col int, layout Layout, tl firestore.TeamLookup) (matchup firestore.Matchup, homeRank int, awayRank int, gotw bool, found bool, err error) {
+	if !layout.Game.InColumn(col) {
+		return
+	}
+	groups := layout.Game.Match(cell)
+	if groups == nil {
 		return
 	}
 
 	found = true
 
-	gotw = submatches[1] == "**"
+	gotw = groups["gotw"] != ""
 
-	if submatches[2] != "" {
-		awayRank, err = strconv.Atoi(submatches[2])
+	if groups["away_rank"] != "" {
+		awayRank, err = strconv.Atoi(groups["away_rank"])
 		if err != nil {
 			err = fmt.Errorf("parseGame: error parsing rank of first team: %w", err)
 			return
@@ -477,25 +474,25 @@ func parseGame(cell string, tl firestore.TeamRefsByName) (matchup firestore.Matc
 
 	var ok bool
 	var teamRef *fs.DocumentRef
-	name := submatches[3]
-	if teamRef, ok = tl[name]; !ok {
+	name := groups["away"]
+	if teamRef, ok = tl.Lookup(name, firestore.ShortName); !ok {
 		err = firestore.NameNotFoundError{Name: name, NameType: firestore.ShortName}
 		return
 	}
 	matchup.Away = teamRef.ID
 
-	matchup.Neutral = strings.ToLower(submatches[4]) == "vs"
+	matchup.Neutral = layout.IsNeutral(groups["location"])
 
-	if submatches[5] != "" {
-		homeRank, err = strconv.Atoi(submatches[5])
+	if groups["home_rank"] != "" {
+		homeRank, err = strconv.Atoi(groups["home_rank"])
 		if err != nil {
 			err = fmt.Errorf("parseGame: error parsing rank of second team: %w", err)
 			return
 		}
 	}
 
-	name = submatches[6]
-	if teamRef, ok = tl[name]; !ok {
+	name = groups["home"]
+	if teamRef, ok = tl.Lookup(name, firestore.ShortName); !ok {
 		err = firestore.NameNotFoundError{Name: name, NameType: firestore.ShortName}
 		return
 	}
@@ -504,25 +501,28 @@ func parseGame(cell string, tl firestore.TeamRefsByName) (matchup firestore.Matc
 	return
 }
 
-// parseNoisySpread parses noisy spread from a cell.
-func parseNoisySpread(cell string, tl firestore.TeamRefsByName) (favorite string, spread int, found bool, err error) {
-	submatches := noiseRe.FindStringSubmatch(cell)
-	if len(submatches) == 0 {
+// parseNoisySpread parses noisy spread from a cell using the layout's noisy spread pattern.
+func parseNoisySpread(cell string, col int, layout Layout, tl firestore.TeamLookup) (favorite string, spread int, found bool, err error) {
+	if !layout.NoisySpread.InColumn(col) {
+		return
+	}
+	groups := layout.NoisySpread.Match(cell)
+	if groups == nil {
 		return
 	}
 
 	found = true
 
-	name := submatches[1]
+	name := groups["favorite"]
 	var teamRef *fs.DocumentRef
 	var ok bool
-	if teamRef, ok = tl[name]; !ok {
+	if teamRef, ok = tl.Lookup(name, firestore.ShortName); !ok {
 		err = firestore.NameNotFoundError{Name: name, NameType: firestore.ShortName}
 		return
 	}
 	favorite = teamRef.ID
 
-	spread, err = strconv.Atoi(submatches[2])
+	spread, err = strconv.Atoi(groups["spread"])
 	if err != nil {
 		err = fmt.Errorf("parseNoisySpread: error parsing noisy spread value: %w", err)
 		return
@@ -530,33 +530,36 @@ func parseNoisySpread(cell string, tl firestore.TeamRefsByName) (favorite string
 	return
 }
 
-// parseDog parses a superdog game from a cell.
-func parseDog(cell string, tl firestore.TeamRefsByName) (matchup firestore.Matchup, favorite string, value int, found bool, err error) {
-	submatches := sdRe.FindStringSubmatch(cell)
-	if len(submatches) == 0 {
+// parseDog parses a superdog game from a cell using the layout's superdog pattern.
+func parseDog(cell string, col int, layout Layout, tl firestore.TeamLookup) (matchup firestore.Matchup, favorite string, value int, found bool, err error) {
+	if !layout.Superdog.InColumn(col) {
+		return
+	}
+	groups := layout.Superdog.Match(cell)
+	if groups == nil {
 		return
 	}
 
 	found = true
 
-	name := submatches[1]
+	name := groups["underdog"]
 	var teamRef *fs.DocumentRef
 	var ok bool
-	if teamRef, ok = tl[name]; !ok {
+	if teamRef, ok = tl.Lookup(name, firestore.OtherName); !ok {
 		err = firestore.NameNotFoundError{Name: name, NameType: firestore.OtherName}
 		return
 	}
 	matchup.Home = teamRef.ID
 
-	name = submatches[2]
-	if teamRef, ok = tl[name]; !ok {
+	name = groups["favorite"]
+	if teamRef, ok = tl.Lookup(name, firestore.OtherName); !ok {
 		err = firestore.NameNotFoundError{Name: name, NameType: firestore.OtherName}
 		return
 	}
 	matchup.Away = teamRef.ID
-	favorite = teamRef.ID // second team listed is always the favorite
+	favorite = teamRef.ID
 
-	value, err = strconv.Atoi(submatches[3])
+	value, err = strconv.Atoi(groups["value"])
 
 	if err != nil {
 		err = fmt.Errorf("parseDog: error parsing game value: %w", err)
@@ -0,0 +1,190 @@
+package parseslate
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/tealeg/xlsx"
+)
+
+// SlateReader reads the contents of a slate file as rows of cell values.
+type SlateReader interface {
+	ReadRows(slurp []byte) ([][]string, error)
+}
+
+// NewSlateReader makes a SlateReader for the given format ("xlsx", "csv", or "ods").
+// If the format is empty, it is guessed from the extension of the file name.
+// The sheet is the name of the sheet to read from workbook formats: if empty, the first sheet is read.
+func NewSlateReader(format string, fileName string, sheet string) (SlateReader, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(path.Ext(fileName)), ".")
+	}
+	switch strings.ToLower(format) {
+	case "xlsx":
+		return XLSXReader{Sheet: sheet}, nil
+	case "csv":
+		return CSVReader{}, nil
+	case "ods":
+		return ODSReader{Sheet: sheet}, nil
+	}
+	return nil, fmt.Errorf("unrecognized slate format '%s'", format)
+}
+
+// XLSXReader reads Excel workbooks.
+type XLSXReader struct {
+	// Sheet is the name of the sheet to read. If empty, the first sheet is read.
+	Sheet string
+}
+
+// ReadRows implements SlateReader.
+func (r XLSXReader) ReadRows(slurp []byte) ([][]string, error) {
+	xl, err := xlsx.OpenBinary(slurp)
+	if err != nil {
+		return nil, err
+	}
+	if len(xl.Sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
+	}
+
+	sheet := xl.Sheets[0]
+	if r.Sheet != "" {
+		var ok bool
+		if sheet, ok = xl.Sheet[r.Sheet]; !ok {
+			return nil, fmt.Errorf("sheet '%s' not found", r.Sheet)
+		}
+	}
+
+	rows := make([][]string, len(sheet.Rows))
+	for irow, row := range sheet.Rows {
+		if row == nil {
+			continue
+		}
+		rows[irow] = make([]string, len(row.Cells))
+		for icol, cell := range row.Cells {
+			rows[irow][icol] = cell.Value
+		}
+	}
+	return rows, nil
+}
+
+// CSVReader reads comma-separated values, like those exported from Google Sheets.
+type CSVReader struct{}
+
+// ReadRows implements SlateReader.
+func (r CSVReader) ReadRows(slurp []byte) ([][]string, error) {
+	c := csv.NewReader(bytes.NewReader(slurp))
+	c.FieldsPerRecord = -1
+	c.LazyQuotes = true
+	return c.ReadAll()
+}
+
+// ODSReader reads OpenDocument spreadsheets, like those exported from Google Sheets or LibreOffice.
+type ODSReader struct {
+	// Sheet is the name of the sheet to read. If empty, the first sheet is read.
+	Sheet string
+}
+
+type odsContent struct {
+	Tables []odsTable `xml:"body>spreadsheet>table"`
+}
+
+type odsTable struct {
+	Name string   `xml:"name,attr"`
+	Rows []odsRow `xml:"table-row"`
+}
+
+type odsRow struct {
+	Repeat string    `xml:"number-rows-repeated,attr"`
+	Cells  []odsCell `xml:"table-cell"`
+}
+
+type odsCell struct {
+	Repeat     string   `xml:"number-columns-repeated,attr"`
+	Paragraphs []string `xml:"p"`
+}
+
+// odsMaxRepeat limits how many times an empty row or cell is repeated. Spreadsheets pad the end of sheets with enormous repeats.
+const odsMaxRepeat = 1000
+
+func odsRepeat(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 1
+	}
+	if n > odsMaxRepeat {
+		return odsMaxRepeat
+	}
+	return n
+}
+
+// ReadRows implements SlateReader.
+func (r ODSReader) ReadRows(slurp []byte) ([][]string, error) {
+	z, err := zip.NewReader(bytes.NewReader(slurp), int64(len(slurp)))
+	if err != nil {
+		return nil, err
+	}
+	var content odsContent
+	found := false
+	for _, f := range z.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err := xml.Unmarshal(b, &content); err != nil {
+			return nil, err
+		}
+		found = true
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("content.xml not found in ODS file")
+	}
+	if len(content.Tables) == 0 {
+		return nil, fmt.Errorf("spreadsheet has no sheets")
+	}
+
+	table := content.Tables[0]
+	if r.Sheet != "" {
+		found = false
+		for _, t := range content.Tables {
+			if t.Name == r.Sheet {
+				table = t
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("sheet '%s' not found", r.Sheet)
+		}
+	}
+
+	rows := make([][]string, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		values := make([]string, 0, len(row.Cells))
+		for _, cell := range row.Cells {
+			v := strings.Join(cell.Paragraphs, "\n")
+			for i := 0; i < odsRepeat(cell.Repeat); i++ {
+				values = append(values, v)
+			}
+		}
+		for i := 0; i < odsRepeat(row.Repeat); i++ {
+			rows = append(rows, values)
+		}
+	}
+	return rows, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
 )
 
 type Context struct {
@@ -12,15 +13,36 @@ type Context struct {
 	Force  bool
 	DryRun bool
 
+	// Override is the commissioner's reason for changing picks after they locked. Locked picks cannot be changed without a reason.
+	Override string
+
 	FirestoreClient *fs.Client
 
+	// Source is the program making the picks, as recorded in the pick change log. Pickem records "b1gtool picks pickem" if it is empty.
+	Source string
+
+	// Actor is the authenticated picker making the picks, as recorded in the pick change log.
+	Actor firestore.Picker
+
 	Season   int
 	Week     int
 	Picker   string
 	Picks    []string
 	SuperDog string
 
+	// Models are the short names of the models whose predictions are shown when picking interactively. The first model with a prediction of a game suggests the pick.
+	Models []string
+
 	Output string
+
+	// Format is the export format, one of Formats. The default is FormatXLSX.
+	Format string
+
+	// All exports the picks of every picker in the season rather than just Picker.
+	All bool
+
+	// Remind prints a reminder message for each picker whose picks are missing.
+	Remind bool
 }
 
 func NewContext(ctx context.Context) *Context {
@@ -0,0 +1,305 @@
+package pickem
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Export formats.
+const (
+	FormatXLSX     = "xlsx"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats are the supported export formats.
+var Formats = []string{FormatXLSX, FormatCSV, FormatJSON, FormatMarkdown, FormatHTML}
+
+var contentTypes = map[string]string{
+	FormatXLSX:     "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatCSV:      "text/csv",
+	FormatJSON:     "application/json",
+	FormatMarkdown: "text/markdown",
+	FormatHTML:     "text/html",
+}
+
+// exportHeader is the first row of an exported slate.
+var exportHeader = []string{"GAME", "Instruction", "Your Selection", "Predicted Spread", "Notes", "Expected Value"}
+
+// summaryHeader is the first row of the summary of every picker's picks.
+var summaryHeader = []string{"Picker", "Picks", "Superdog", "Streak", "Expected Points"}
+
+const streakOver = "STREAK OVER!"
+
+// pickerExport is a picker's picks laid out as on the slate, with a summary.
+type pickerExport struct {
+	Picker string
+
+	// Rows are the slate rows, starting with exportHeader. Empty rows are nil.
+	Rows [][]string
+
+	// Picks is the number of teams picked.
+	Picks int
+
+	// Superdog is the picked superdog, if any.
+	Superdog string
+
+	// Streak is the streak pick, "BYE", or streakOver.
+	Streak string
+
+	// ExpectedPoints is the sum of the expected points of the picks.
+	ExpectedPoints float64
+}
+
+func (e pickerExport) summary() []string {
+	return []string{e.Picker, fmt.Sprint(e.Picks), e.Superdog, e.Streak, fmt.Sprintf("%0.3f", e.ExpectedPoints)}
+}
+
+// body returns the non-empty rows after the header, padded to the width of the header.
+func (e pickerExport) body() [][]string {
+	rows := make([][]string, 0, len(e.Rows))
+	for _, row := range e.Rows[1:] {
+		if len(strings.Join(row, "")) == 0 {
+			continue
+		}
+		padded := make([]string, len(exportHeader))
+		copy(padded, row)
+		rows = append(rows, padded)
+	}
+	return rows
+}
+
+// writeExports writes the exported picks in the given format.
+// If all is true, the export is for the commissioner: every picker's picks are labeled and a summary is included.
+func writeExports(w io.Writer, format string, exports []pickerExport, all bool) error {
+	switch format {
+	case FormatXLSX:
+		return writeXLSX(w, exports, all)
+	case FormatCSV:
+		return writeCSV(w, exports, all)
+	case FormatJSON:
+		return writeJSON(w, exports, all)
+	case FormatMarkdown:
+		return writeMarkdown(w, exports, all)
+	case FormatHTML:
+		return writeHTML(w, exports, all)
+	}
+	return fmt.Errorf("unrecognized format '%s'", format)
+}
+
+func setSheetRows(xl *excelize.File, sheet string, rows [][]string) error {
+	for r, row := range rows {
+		for c, str := range row {
+			index, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			xl.SetCellStr(sheet, index, str)
+		}
+	}
+	return nil
+}
+
+// sheetName makes a valid Excel sheet name out of a picker's name.
+func sheetName(name string) string {
+	name = strings.NewReplacer(":", "", "\\", "", "/", "", "?", "", "*", "", "[", "", "]", "").Replace(name)
+	if len(name) > 31 {
+		name = name[:31]
+	}
+	return name
+}
+
+func writeXLSX(w io.Writer, exports []pickerExport, all bool) error {
+	// Make an excel file in memory.
+	xl := excelize.NewFile()
+	first := xl.GetSheetName(xl.GetActiveSheetIndex())
+	if !all {
+		for _, e := range exports {
+			if err := setSheetRows(xl, first, e.Rows); err != nil {
+				return err
+			}
+		}
+		_, err := xl.WriteTo(w)
+		return err
+	}
+
+	summary := [][]string{summaryHeader}
+	for _, e := range exports {
+		summary = append(summary, e.summary())
+	}
+	xl.SetSheetName(first, "Summary")
+	if err := setSheetRows(xl, "Summary", summary); err != nil {
+		return err
+	}
+	for _, e := range exports {
+		name := sheetName(e.Picker)
+		xl.NewSheet(name)
+		if err := setSheetRows(xl, name, e.Rows); err != nil {
+			return err
+		}
+	}
+	_, err := xl.WriteTo(w)
+	return err
+}
+
+func writeCSV(w io.Writer, exports []pickerExport, all bool) error {
+	cw := csv.NewWriter(w)
+	header := exportHeader
+	if all {
+		header = append([]string{"Picker"}, header...)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range exports {
+		for _, row := range e.body() {
+			if all {
+				row = append([]string{e.Picker}, row...)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonPick is an exported slate row.
+type jsonPick struct {
+	Game          string `json:"game"`
+	Instruction   string `json:"instruction,omitempty"`
+	Selection     string `json:"selection,omitempty"`
+	Spread        string `json:"predicted_spread,omitempty"`
+	Notes         string `json:"notes,omitempty"`
+	ExpectedValue string `json:"expected_value,omitempty"`
+}
+
+type jsonPicker struct {
+	Picker         string     `json:"picker"`
+	Picks          []jsonPick `json:"picks"`
+	Superdog       string     `json:"superdog,omitempty"`
+	Streak         string     `json:"streak"`
+	ExpectedPoints float64    `json:"expected_points"`
+}
+
+func writeJSON(w io.Writer, exports []pickerExport, all bool) error {
+	pickers := make([]jsonPicker, len(exports))
+	for i, e := range exports {
+		p := jsonPicker{Picker: e.Picker, Picks: make([]jsonPick, 0), Superdog: e.Superdog, Streak: e.Streak, ExpectedPoints: e.ExpectedPoints}
+		for _, row := range e.body() {
+			p.Picks = append(p.Picks, jsonPick{row[0], row[1], row[2], row[3], row[4], row[5]})
+		}
+		pickers[i] = p
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if all || len(pickers) != 1 {
+		return enc.Encode(pickers)
+	}
+	return enc.Encode(pickers[0])
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\n", "<br>")
+
+func writeMarkdownTable(w io.Writer, header []string, rows [][]string) error {
+	lines := make([]string, 0, len(rows)+2)
+	lines = append(lines, "| "+strings.Join(header, " | ")+" |")
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+	lines = append(lines, "| "+strings.Join(sep, " | ")+" |")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = markdownEscaper.Replace(c)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func writeMarkdown(w io.Writer, exports []pickerExport, all bool) error {
+	if all {
+		summary := make([][]string, len(exports))
+		for i, e := range exports {
+			summary[i] = e.summary()
+		}
+		if _, err := io.WriteString(w, "## Summary\n\n"); err != nil {
+			return err
+		}
+		if err := writeMarkdownTable(w, summaryHeader, summary); err != nil {
+			return err
+		}
+	}
+	for i, e := range exports {
+		if all {
+			if _, err := fmt.Fprintf(w, "\n## %s\n\n", e.Picker); err != nil {
+				return err
+			}
+		} else if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeMarkdownTable(w, exportHeader, e.body()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var htmlTemplate = template.Must(template.New("picks").Parse(`<html>
+<body>
+{{- if .All}}
+<h2>Summary</h2>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr>{{range .SummaryHeader}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Summary}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Pickers}}
+{{- if $.All}}
+<h2>{{.Picker}}</h2>
+{{- end}}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr>{{range $.Header}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, exports []pickerExport, all bool) error {
+	type htmlPicker struct {
+		Picker string
+		Rows   [][]string
+	}
+	data := struct {
+		All           bool
+		Header        []string
+		SummaryHeader []string
+		Summary       [][]string
+		Pickers       []htmlPicker
+	}{All: all, Header: exportHeader, SummaryHeader: summaryHeader}
+	for _, e := range exports {
+		data.Summary = append(data.Summary, e.summary())
+		data.Pickers = append(data.Pickers, htmlPicker{e.Picker, e.body()})
+	}
+	return htmlTemplate.Execute(w, data)
+}
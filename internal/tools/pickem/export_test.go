@@ -0,0 +1,143 @@
+package pickem
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	excelize "github.com/xuri/excelize/v2"
+)
+
+var testExports = []pickerExport{
+	{
+		Picker: "Alice",
+		Rows: [][]string{
+			exportHeader,
+			{"** Ohio State @ Michigan **", "", "Wolverines", "+3.50", "", "0.620"},
+			nil,
+			{"BEAT THE STREAK!", "", "Hawkeyes"},
+			{"Illinois upsets Wisconsin at home", "", "Illini", "-7.00", "Pipe | and\nnewline", "1.500"},
+		},
+		Picks:          2,
+		Superdog:       "Illini",
+		Streak:         "Hawkeyes",
+		ExpectedPoints: 2.12,
+	},
+	{
+		Picker: "Bob",
+		Rows: [][]string{
+			exportHeader,
+			{"** Ohio State @ Michigan **", "", "Buckeyes", "+3.50", "", "0.380"},
+			nil,
+			{"BEAT THE STREAK!", "", streakOver},
+		},
+		Picks:          1,
+		Streak:         streakOver,
+		ExpectedPoints: .38,
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExports(&buf, FormatCSV, testExports[:1], false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != strings.Join(exportHeader, ",") {
+		t.Errorf("expected header, got %q", lines[0])
+	}
+	if lines[2] != "BEAT THE STREAK!,,Hawkeyes,,," {
+		t.Errorf("expected padded streak row without blank rows, got %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := writeExports(&buf, FormatCSV, testExports, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\nBob,** Ohio State @ Michigan **,,Buckeyes,") {
+		t.Errorf("expected rows labeled by picker, got\n%s", buf.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExports(&buf, FormatJSON, testExports, true); err != nil {
+		t.Fatal(err)
+	}
+	var pickers []jsonPicker
+	if err := json.Unmarshal(buf.Bytes(), &pickers); err != nil {
+		t.Fatal(err)
+	}
+	if len(pickers) != 2 || len(pickers[0].Picks) != 3 || pickers[0].Superdog != "Illini" || pickers[1].Streak != streakOver {
+		t.Errorf("unexpected JSON export %+v", pickers)
+	}
+
+	buf.Reset()
+	if err := writeExports(&buf, FormatJSON, testExports[1:], false); err != nil {
+		t.Fatal(err)
+	}
+	var picker jsonPicker
+	if err := json.Unmarshal(buf.Bytes(), &picker); err != nil {
+		t.Fatalf("expected a single picker object: %v", err)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExports(&buf, FormatMarkdown, testExports, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"## Summary", "| Alice | 2 | Illini | Hawkeyes | 2.120 |", "## Bob", `Pipe \| and<br>newline`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExports(&buf, FormatHTML, testExports, true); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"<h2>Summary</h2>", "<h2>Alice</h2>", "<td>Wolverines</td>", "Pipe | and"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	if err := writeExports(&buf, FormatHTML, testExports[:1], false); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<h2>") {
+		t.Errorf("expected no headings for a single picker, got\n%s", buf.String())
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeExports(&buf, FormatXLSX, testExports, true); err != nil {
+		t.Fatal(err)
+	}
+	xl, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheets := xl.GetSheetList()
+	if strings.Join(sheets, ",") != "Summary,Alice,Bob" {
+		t.Fatalf("expected summary and picker sheets, got %v", sheets)
+	}
+	if v, _ := xl.GetCellValue("Summary", "C2"); v != "Illini" {
+		t.Errorf("expected Alice's superdog in summary, got %q", v)
+	}
+	if v, _ := xl.GetCellValue("Bob", "C2"); v != "Buckeyes" {
+		t.Errorf("expected Bob's pick on his sheet, got %q", v)
+	}
+
+	if err := writeExports(&buf, "bogus", testExports, true); err == nil {
+		t.Error("expected error for unrecognized format")
+	}
+}
@@ -0,0 +1,173 @@
+package pickem
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// timeFormat is how times of changes are printed.
+const timeFormat = "2006-01-02 15:04:05 MST"
+
+// PickHistory prints every change made to the week's picks in the order they were made, either for Picker or for every picker if Picker is empty.
+func PickHistory(ctx *Context) error {
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("PickHistory: failed to get season: %w", err)
+	}
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("PickHistory: failed to get week: %w", err)
+	}
+	var pickerRef *fs.DocumentRef
+	if ctx.Picker != "" {
+		_, pickerRef, err = firestore.GetPickerByLukeName(ctx, ctx.FirestoreClient, ctx.Picker)
+		if err != nil {
+			return fmt.Errorf("PickHistory: failed to get picker '%s': %w", ctx.Picker, err)
+		}
+	}
+
+	changes, _, err := firestore.GetPickChanges(ctx, weekRef, pickerRef)
+	if err != nil {
+		return fmt.Errorf("PickHistory: failed to get pick changes: %w", err)
+	}
+	overrides, err := firestore.GetLockOverrides(ctx, weekRef, pickerRef)
+	if err != nil {
+		return fmt.Errorf("PickHistory: failed to get lock overrides: %w", err)
+	}
+	if len(changes) == 0 && len(overrides) == 0 {
+		fmt.Println("No pick changes recorded")
+		return nil
+	}
+
+	teams, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("PickHistory: failed to get teams: %w", err)
+	}
+	pickerNames := make(map[string]string)
+	for name, ref := range season.Pickers {
+		pickerNames[ref.ID] = name
+	}
+
+	h := historyNames{ctx: ctx, teams: teams, games: make(map[string]string), models: make(map[string]string)}
+	tw := table.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.AppendHeader(table.Row{"Time", "Picker", "Game", "From", "To", "From Model", "To Model", "By", "Source", "User", "Override"})
+	for _, c := range changes {
+		picker, ok := pickerNames[c.Picker.ID]
+		if !ok {
+			picker = c.Picker.ID
+		}
+		game, err := h.game(c.SlateGame)
+		if err != nil {
+			return fmt.Errorf("PickHistory: failed to get slate game %s: %w", c.SlateGame.ID, err)
+		}
+		fromModel, err := h.model(c.FromModelPrediction)
+		if err != nil {
+			return fmt.Errorf("PickHistory: failed to get model prediction: %w", err)
+		}
+		toModel, err := h.model(c.ToModelPrediction)
+		if err != nil {
+			return fmt.Errorf("PickHistory: failed to get model prediction: %w", err)
+		}
+		from := h.team(c.From)
+		if c.Created {
+			from = "(new)"
+		}
+		tw.AppendRow(table.Row{c.Timestamp.Local().Format(timeFormat), picker, game, from, h.team(c.To), fromModel, toModel, c.Actor, c.Source, c.User, c.Override})
+	}
+	tw.SetStyle(table.StyleLight)
+	tw.Render()
+
+	if len(overrides) == 0 {
+		return nil
+	}
+	fmt.Println("Lock overrides:")
+	ow := table.NewWriter()
+	ow.SetOutputMirror(os.Stdout)
+	ow.AppendHeader(table.Row{"Time", "Picker", "Locked", "Reason", "By", "Source", "User"})
+	for _, o := range overrides {
+		picker, ok := pickerNames[o.Picker.ID]
+		if !ok {
+			picker = o.Picker.ID
+		}
+		ow.AppendRow(table.Row{o.Timestamp.Local().Format(timeFormat), picker, strings.Join(o.Locked, "\n"), o.Reason, o.Actor, o.Source, o.User})
+	}
+	ow.SetStyle(table.StyleLight)
+	ow.Render()
+	return nil
+}
+
+// historyNames looks up and caches the names of what pick changes refer to.
+type historyNames struct {
+	ctx    *Context
+	teams  *firestore.TeamResolver
+	games  map[string]string
+	models map[string]string
+}
+
+func (h historyNames) team(ref *fs.DocumentRef) string {
+	if ref == nil {
+		return "-"
+	}
+	if t, ok := h.teams.Team(ref); ok {
+		return t.School
+	}
+	return ref.ID
+}
+
+func (h historyNames) game(ref *fs.DocumentRef) (string, error) {
+	if name, ok := h.games[ref.Path]; ok {
+		return name, nil
+	}
+	snap, err := ref.Get(h.ctx)
+	if err != nil {
+		return "", err
+	}
+	var sg firestore.SlateGame
+	if err := snap.DataTo(&sg); err != nil {
+		return "", err
+	}
+	snap, err = sg.Game.Get(h.ctx)
+	if err != nil {
+		return "", err
+	}
+	var game firestore.Game
+	if err := snap.DataTo(&game); err != nil {
+		return "", err
+	}
+	sep := "@"
+	if game.NeutralSite {
+		sep = "vs."
+	}
+	name := fmt.Sprintf("%s %s %s", h.team(game.AwayTeam), sep, h.team(game.HomeTeam))
+	h.games[ref.Path] = name
+	return name, nil
+}
+
+func (h historyNames) model(ref *fs.DocumentRef) (string, error) {
+	if ref == nil {
+		return "-", nil
+	}
+	if name, ok := h.models[ref.Path]; ok {
+		return name, nil
+	}
+	snap, err := ref.Get(h.ctx)
+	if err != nil {
+		return "", err
+	}
+	var pred firestore.ModelPrediction
+	if err := snap.DataTo(&pred); err != nil {
+		return "", err
+	}
+	name := ref.ID
+	if pred.Model != nil {
+		name = pred.Model.ID
+	}
+	h.models[ref.Path] = name
+	return name, nil
+}
@@ -0,0 +1,420 @@
+package pickem
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// noSuperdog is the option to leave every superdog unpicked.
+const noSuperdog = "No superdog"
+
+// slateChoice is a slate game as presented to the picker.
+type slateChoice struct {
+	sg    firestore.SlateGame
+	ref   *fs.DocumentRef
+	game  firestore.Game
+	home  firestore.Team
+	away  firestore.Team
+	lines []string
+
+	// model is the pick made by the first model with a prediction of the game.
+	model firestore.Pick
+
+	// stored is the team the picker has already picked, if any.
+	stored *fs.DocumentRef
+}
+
+// InteractivePickem walks the picker through every game on the week's slate, showing model predictions and asking for a pick,
+// then saves all the picks in a single transaction.
+func InteractivePickem(ctx *Context) error {
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get season: %w", err)
+	}
+	week, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get week: %w", err)
+	}
+	_, pickerRef, err := firestore.GetPickerByLukeName(ctx, ctx.FirestoreClient, ctx.Picker)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get picker '%s': %w", ctx.Picker, err)
+	}
+
+	slateGames, sgRefs, err := firestore.GetSlateGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get slate games: %w", err)
+	}
+
+	picks, pickRefs, err := firestore.GetPicks(ctx, weekRef, pickerRef)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get picks for picker '%s': %w", ctx.Picker, err)
+	}
+	pickLookup := newPicksByGameID(picks, pickRefs)
+
+	teams, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get teams: %w", err)
+	}
+
+	models, modelRefs, err := firestore.GetModels(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get models: %w", err)
+	}
+	modelLookup := firestore.NewModelRefsByShortName(models, modelRefs)
+	perfs, _, err := firestore.GetMostRecentModelPerformances(ctx, ctx.FirestoreClient, weekRef)
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to get model performances: %w", err)
+	}
+	perfLookup := make(map[string]firestore.ModelPerformance)
+	for _, p := range perfs {
+		perfLookup[p.Model.ID] = p
+	}
+
+	choices := make([]slateChoice, len(slateGames))
+	for i, sg := range slateGames {
+		choices[i], err = newSlateChoice(ctx, sg, sgRefs[i], teams, modelLookup, perfLookup)
+		if err != nil {
+			return fmt.Errorf("InteractivePickem: failed to build choices for slate game %s: %w", sgRefs[i].ID, err)
+		}
+		if pick, _, ok := pickLookup.Lookup(sgRefs[i].ID); ok {
+			choices[i].stored = pick.PickedTeam
+		}
+	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i].sg.Row < choices[j].sg.Row })
+
+	made := make(map[string]firestore.Pick)
+	dogs := make([]slateChoice, 0)
+	for _, c := range choices {
+		if c.sg.Superdog {
+			dogs = append(dogs, c)
+			continue
+		}
+		pick, err := askPick(c)
+		if err != nil {
+			return fmt.Errorf("InteractivePickem: %w", err)
+		}
+		made[c.ref.ID] = pick
+	}
+	if len(dogs) > 0 {
+		dogPicks, err := askSuperdog(dogs)
+		if err != nil {
+			return fmt.Errorf("InteractivePickem: %w", err)
+		}
+		for id, pick := range dogPicks {
+			made[id] = pick
+		}
+	}
+
+	picksToUpdate := make(map[string]firestore.Pick)
+	newPicks := make([]firestore.Pick, 0)
+	for _, c := range choices {
+		pick, ok := made[c.ref.ID]
+		if !ok {
+			continue
+		}
+		pick.Picker = pickerRef
+		pick.SlateGame = c.ref
+		if _, ref, ok := pickLookup.Lookup(c.ref.ID); ok {
+			picksToUpdate[ref.ID] = pick
+		} else if pick.PickedTeam != nil {
+			newPicks = append(newPicks, pick)
+		}
+	}
+
+	printPicks(choices, made)
+
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would create %d new picks", len(newPicks))
+		for _, pick := range newPicks {
+			log.Print(pick)
+		}
+		log.Printf("DRY RUN: would update %d previously-made picks", len(picksToUpdate))
+		for id, pick := range picksToUpdate {
+			log.Printf("%s -> %s", id, pick)
+		}
+		return nil
+	}
+
+	save := false
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("Save %d new picks and update %d previously-made picks?", len(newPicks), len(picksToUpdate)),
+		Default: true,
+	}
+	if err := survey.AskOne(prompt, &save); err != nil {
+		return fmt.Errorf("InteractivePickem: %w", err)
+	}
+	if !save {
+		log.Print("Picks not saved")
+		return nil
+	}
+
+	err = savePicks(ctx, season.PickLock, week, weekRef, pickerRef, pickLookup, picksToUpdate, newPicks, "b1gtool picks interactive")
+	if err != nil {
+		return fmt.Errorf("InteractivePickem: failed to save picks: %w", err)
+	}
+	log.Printf("Saved %d new picks and updated %d previously-made picks for picker %s", len(newPicks), len(picksToUpdate), ctx.Picker)
+	return nil
+}
+
+func newSlateChoice(ctx *Context, sg firestore.SlateGame, ref *fs.DocumentRef, teams *firestore.TeamResolver, models firestore.ModelRefsByName, perfs map[string]firestore.ModelPerformance) (slateChoice, error) {
+	c := slateChoice{sg: sg, ref: ref}
+	snap, err := sg.Game.Get(ctx)
+	if err != nil {
+		return c, err
+	}
+	if err := snap.DataTo(&c.game); err != nil {
+		return c, err
+	}
+	var ok bool
+	if c.home, ok = teams.Team(c.game.HomeTeam); !ok {
+		return c, fmt.Errorf("team %s not found", c.game.HomeTeam.ID)
+	}
+	if c.away, ok = teams.Team(c.game.AwayTeam); !ok {
+		return c, fmt.Errorf("team %s not found", c.game.AwayTeam.ID)
+	}
+
+	for _, name := range ctx.Models {
+		modelRef, ok := models[name]
+		if !ok {
+			return c, fmt.Errorf("model '%s' not found", name)
+		}
+		pred, predRef, found, err := firestore.GetPredictionByModel(ctx, ctx.FirestoreClient, sg.Game, modelRef)
+		if err != nil {
+			return c, err
+		}
+		if !found {
+			c.lines = append(c.lines, fmt.Sprintf("%s: no prediction", name))
+			continue
+		}
+		perf, ok := perfs[modelRef.ID]
+		if !ok {
+			c.lines = append(c.lines, fmt.Sprintf("%s: no model performance", name))
+			continue
+		}
+		if pred.HomeTeam != nil && pred.HomeTeam.ID != c.game.HomeTeam.ID {
+			pred.Spread = -pred.Spread
+			pred.HomeTeam, pred.AwayTeam = pred.AwayTeam, pred.HomeTeam
+		}
+		var p firestore.Pick
+		p.FillOut(c.game, perf, pred, predRef, sg.NoisySpread)
+		c.lines = append(c.lines, fmt.Sprintf("%s: %s (%s, p = %0.3f)", name, c.spreadString(pred.Spread), c.name(p.PickedTeam), p.PredictedProbability))
+		if c.model.PickedTeam == nil {
+			c.model = p
+		}
+	}
+	return c, nil
+}
+
+// name returns the display name of one of the teams in the game.
+func (c slateChoice) name(team *fs.DocumentRef) string {
+	switch {
+	case team == nil:
+		return "none"
+	case team.ID == c.game.HomeTeam.ID:
+		return c.home.School
+	case team.ID == c.game.AwayTeam.ID:
+		return c.away.School
+	}
+	return team.ID
+}
+
+func (c slateChoice) spreadString(spread float64) string {
+	switch {
+	case spread > 0:
+		return fmt.Sprintf("%s by %0.1f", c.home.School, spread)
+	case spread < 0:
+		return fmt.Sprintf("%s by %0.1f", c.away.School, -spread)
+	}
+	return "even"
+}
+
+// title returns the game as it appears on the slate.
+func (c slateChoice) title() string {
+	var sb strings.Builder
+	if c.sg.GOTW {
+		sb.WriteString("** ")
+	}
+	if c.sg.AwayRank > 0 {
+		sb.WriteString(fmt.Sprintf("#%d ", c.sg.AwayRank))
+	}
+	sb.WriteString(c.away.School)
+	if c.game.NeutralSite {
+		sb.WriteString(" vs. ")
+	} else {
+		sb.WriteString(" @ ")
+	}
+	if c.sg.HomeRank > 0 {
+		sb.WriteString(fmt.Sprintf("#%d ", c.sg.HomeRank))
+	}
+	sb.WriteString(c.home.School)
+	if c.sg.GOTW {
+		sb.WriteString(" ** (game of the week)")
+	}
+	sb.WriteString(fmt.Sprintf(" [%d points]", c.sg.Value))
+	return sb.String()
+}
+
+// instruction returns how the game is picked.
+func (c slateChoice) instruction() string {
+	fav, dog := c.home, c.away
+	if !c.sg.HomeFavored {
+		fav, dog = dog, fav
+	}
+	switch {
+	case c.sg.Superdog:
+		return fmt.Sprintf("Superdog: %s over %s (%d points, if correct)", dog.School, fav.School, c.sg.Value)
+	case c.sg.NoisySpread != 0:
+		spread := c.sg.NoisySpread
+		if spread < 0 {
+			spread = -spread
+		}
+		return fmt.Sprintf("Enter %s iff you predict %s wins by at least %d points", fav.School, fav.School, spread)
+	}
+	return "Pick the winner"
+}
+
+// probability returns the model's probability that a pick of the team is correct.
+func (c slateChoice) probability(team *fs.DocumentRef) (float64, bool) {
+	if c.model.PickedTeam == nil {
+		return 0, false
+	}
+	if team.ID == c.model.PickedTeam.ID {
+		return c.model.PredictedProbability, true
+	}
+	return 1 - c.model.PredictedProbability, true
+}
+
+// suggested returns the team picked by default: the picker's stored pick, otherwise the model's pick.
+func (c slateChoice) suggested() *fs.DocumentRef {
+	if c.stored != nil {
+		return c.stored
+	}
+	return c.model.PickedTeam
+}
+
+// pick returns a pick of the team along with the model's prediction.
+func (c slateChoice) pick(team *fs.DocumentRef) firestore.Pick {
+	p := c.model
+	p.PickedTeam = team
+	if prob, ok := c.probability(team); ok {
+		p.PredictedProbability = prob
+	}
+	return p
+}
+
+func (c slateChoice) option(team *fs.DocumentRef) string {
+	if prob, ok := c.probability(team); ok {
+		return fmt.Sprintf("%s (p = %0.3f)", c.name(team), prob)
+	}
+	return c.name(team)
+}
+
+func (c slateChoice) underdog() *fs.DocumentRef {
+	if c.sg.HomeFavored {
+		return c.game.AwayTeam
+	}
+	return c.game.HomeTeam
+}
+
+func askPick(c slateChoice) (firestore.Pick, error) {
+	fmt.Println()
+	fmt.Println(c.title())
+	for _, l := range c.lines {
+		fmt.Printf("  %s\n", l)
+	}
+	fmt.Printf("  %s\n", c.instruction())
+
+	teams := []*fs.DocumentRef{c.game.AwayTeam, c.game.HomeTeam}
+	options := make([]string, len(teams))
+	var def interface{}
+	for i, team := range teams {
+		options[i] = c.option(team)
+		if s := c.suggested(); s != nil && s.ID == team.ID {
+			def = options[i]
+		}
+	}
+	q := &survey.Select{
+		Message: "Pick:",
+		Options: options,
+		Default: def,
+	}
+	var answer int
+	if err := survey.AskOne(q, &answer); err != nil {
+		return firestore.Pick{}, err
+	}
+	return c.pick(teams[answer]), nil
+}
+
+// askSuperdog asks which superdog to pick and returns picks for every superdog game, with the unpicked games' teams set to nil.
+func askSuperdog(dogs []slateChoice) (map[string]firestore.Pick, error) {
+	fmt.Println()
+	fmt.Println("Superdogs")
+	options := make([]string, len(dogs)+1)
+	var def interface{}
+	bestEV := 0.
+	for i, c := range dogs {
+		dog := c.underdog()
+		fmt.Printf("  %s\n", c.instruction())
+		for _, l := range c.lines {
+			fmt.Printf("    %s\n", l)
+		}
+		options[i] = fmt.Sprintf("%s [%d points]", c.option(dog), c.sg.Value)
+		if p, ok := c.probability(dog); ok && p*float64(c.sg.Value) > bestEV {
+			def = options[i]
+			bestEV = p * float64(c.sg.Value)
+		}
+	}
+	for i, c := range dogs {
+		if c.stored != nil && c.stored.ID == c.underdog().ID {
+			// a stored superdog pick beats any expected value
+			def = options[i]
+		}
+	}
+	options[len(dogs)] = noSuperdog
+	q := &survey.Select{
+		Message: "Pick superdog:",
+		Options: options,
+		Default: def,
+	}
+	var answer int
+	if err := survey.AskOne(q, &answer); err != nil {
+		return nil, err
+	}
+
+	picks := make(map[string]firestore.Pick)
+	for i, c := range dogs {
+		if i == answer {
+			picks[c.ref.ID] = c.pick(c.underdog())
+			continue
+		}
+		picks[c.ref.ID] = firestore.Pick{}
+	}
+	return picks, nil
+}
+
+func printPicks(choices []slateChoice, picks map[string]firestore.Pick) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Game", "Pick", "P(Correct)", "Value"})
+	for _, c := range choices {
+		pick, ok := picks[c.ref.ID]
+		if !ok || pick.PickedTeam == nil {
+			continue
+		}
+		prob := "-"
+		if pick.PredictedProbability > 0 {
+			prob = fmt.Sprintf("%0.3f", pick.PredictedProbability)
+		}
+		t.AppendRow(table.Row{c.title(), c.name(pick.PickedTeam), prob, c.sg.Value})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -0,0 +1,115 @@
+package pickem
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Missing prints which pickers of the season have not finished their picks for the week: slate games without a pick, no superdog, or no Beat the Streak pick while still streaking.
+// With Remind, it also prints a reminder message for each of them.
+func Missing(ctx *Context) error {
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get season: %w", err)
+	}
+	week, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get week: %w", err)
+	}
+	sgs, sgRefs, err := firestore.GetSlateGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get slate games: %w", err)
+	}
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get games: %w", err)
+	}
+	gamesByID := make(map[string]firestore.Game)
+	for i, ref := range gameRefs {
+		gamesByID[ref.ID] = games[i]
+	}
+	picks, _, err := firestore.GetWeekPicks(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get picks: %w", err)
+	}
+	streaks, _, err := firestore.GetRemainingStreaks(ctx, seasonRef, weekRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get remaining streaks: %w", err)
+	}
+
+	// streak picks are recorded as streak picks by pickem4me and as the following week's remaining teams by btstool
+	streakPicked := make(map[string]bool)
+	streakPicks, _, err := firestore.GetStreakPicks(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get streak picks: %w", err)
+	}
+	for _, sp := range streakPicks {
+		if sp.Picker != nil {
+			streakPicked[sp.Picker.ID] = true
+		}
+	}
+	_, nextRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week+1)
+	if _, ok := err.(firestore.NoWeekError); !ok && err != nil {
+		return fmt.Errorf("Missing: failed to get following week: %w", err)
+	}
+	if nextRef != nil {
+		next, _, err := firestore.GetRemainingStreaks(ctx, seasonRef, nextRef)
+		if err != nil {
+			return fmt.Errorf("Missing: failed to get remaining streaks of following week: %w", err)
+		}
+		for id := range next {
+			streakPicked[id] = true
+		}
+	}
+
+	missing := firestore.FindMissingPicks(season.Pickers, sgs, sgRefs, gamesByID, picks, streaks, streakPicked)
+	if len(missing) == 0 {
+		fmt.Println("All picks are in")
+		return nil
+	}
+
+	teams, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("Missing: failed to get teams: %w", err)
+	}
+	h := historyNames{ctx: ctx, teams: teams, games: make(map[string]string), models: make(map[string]string)}
+	gameNames := make([][]string, len(missing))
+	for i, m := range missing {
+		for _, ref := range m.Games {
+			name, err := h.game(ref)
+			if err != nil {
+				return fmt.Errorf("Missing: failed to get slate game %s: %w", ref.ID, err)
+			}
+			gameNames[i] = append(gameNames[i], name)
+		}
+	}
+
+	tw := table.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.AppendHeader(table.Row{"Picker", "Games Without Picks", "Superdog", "Streak"})
+	for i, m := range missing {
+		tw.AppendRow(table.Row{m.Picker, strings.Join(gameNames[i], "\n"), missingMark(m.NoSuperdog), missingMark(m.NoStreakPick)})
+	}
+	tw.SetStyle(table.StyleLight)
+	tw.Render()
+
+	if !ctx.Remind {
+		return nil
+	}
+	for i, m := range missing {
+		fmt.Println()
+		fmt.Print(m.Reminder(season.Year, season.PickLock, week, gameNames[i]))
+	}
+	return nil
+}
+
+func missingMark(missing bool) string {
+	if missing {
+		return "missing"
+	}
+	return ""
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
@@ -11,11 +12,11 @@ import (
 
 func Pickem(ctx *Context) error {
 
-	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
 	if err != nil {
 		return fmt.Errorf("Pickem: failed to get season: %w", err)
 	}
-	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	week, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
 	if err != nil {
 		return fmt.Errorf("Pickem: failed to get week: %w", err)
 	}
@@ -47,27 +48,22 @@ func Pickem(ctx *Context) error {
 
 	pickLookup := newPicksByGameID(picks, pickRefs)
 
-	teams, teamRefs, err := firestore.GetTeams(ctx, seasonRef)
+	teamLookup, err := firestore.GetTeamResolver(ctx, seasonRef)
 	if err != nil {
 		return fmt.Errorf("Pickem: failed to get teams: %w", err)
 	}
 
-	teamLookup, err2 := firestore.NewTeamRefsByOtherName(teams, teamRefs)
-	if err2 != nil {
-		panic(err2)
-	}
-
 	picksToUpdate := make(map[string]firestore.Pick)
 	newPicks := make([]firestore.Pick, 0)
 
 	for _, pickedTeam := range ctx.Picks {
-		_, gameRef, ok := gameLookup.Lookup(pickedTeam)
-		if !ok {
-			return fmt.Errorf("Pickem: Team '%s' not found in slate games", pickedTeam)
+		pickedTeamRef, err := teamLookup.Resolve(pickedTeam, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("Pickem: %w", err)
 		}
-		pickedTeamRef, ok := teamLookup[pickedTeam]
+		_, gameRef, ok := gameLookup.Lookup(pickedTeamRef.ID)
 		if !ok {
-			return fmt.Errorf("Pickem: Team '%s' not found in teams", pickedTeam)
+			return fmt.Errorf("Pickem: Team '%s' not found in slate games", pickedTeam)
 		}
 		pick, pickRef, ok := pickLookup.Lookup(gameRef.ID)
 		pick.PickedTeam = pickedTeamRef
@@ -85,17 +81,17 @@ func Pickem(ctx *Context) error {
 	}
 
 	if ctx.SuperDog != "" {
-		game, gameRef, ok := gameLookup.Lookup(ctx.SuperDog)
+		pickedTeamRef, err := teamLookup.Resolve(ctx.SuperDog, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("Pickem: %w", err)
+		}
+		game, gameRef, ok := gameLookup.Lookup(pickedTeamRef.ID)
 		if !ok {
 			return fmt.Errorf("Pickem: Team '%s' not found in slate games", ctx.SuperDog)
 		}
 		if !game.Superdog {
 			return fmt.Errorf("Pickem: Team '%s' not found in superdog games", ctx.SuperDog)
 		}
-		pickedTeamRef, ok := teamLookup[ctx.SuperDog]
-		if !ok {
-			return fmt.Errorf("Pickem: Team '%s' not found in teams", ctx.SuperDog)
-		}
 
 		// unpick other SD games
 		for id := range sdGames {
@@ -143,28 +139,79 @@ func Pickem(ctx *Context) error {
 		return fmt.Errorf("Pickem: refusing to proceed when updating %d picks: add --force flag to force update", len(picksToUpdate))
 	}
 
+	source := ctx.Source
+	if source == "" {
+		source = "b1gtool picks pickem"
+	}
+	err = savePicks(ctx, season.PickLock, week, weekRef, pickerRef, pickLookup, picksToUpdate, newPicks, source)
+	if err != nil {
+		return fmt.Errorf("Pickem: failed to save picks: %w", err)
+	}
+
+	return nil
+}
+
+// savePicks updates and creates a picker's picks in a single transaction, recording every change in the week's pick change log.
+// Picks to update are keyed by the IDs of the pick documents they replace, which are looked up in previous.
+// Changes to picks that have locked are refused unless ctx.Override gives a reason, in which case the override is recorded.
+func savePicks(ctx *Context, lock firestore.PickLock, week firestore.Week, weekRef, pickerRef *fs.DocumentRef, previous *picksByGameID, picksToUpdate map[string]firestore.Pick, newPicks []firestore.Pick, source string) error {
 	picksCollection := weekRef.Collection(firestore.PICKS_COLLECTION)
-	err = ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
-		for id, pick := range picksToUpdate {
-			ref := picksCollection.Doc(id)
-			if err := t.Set(ref, &pick); err != nil {
-				return err
-			}
-		}
-		for _, pick := range newPicks {
-			ref := picksCollection.NewDoc()
-			if err := t.Create(ref, &pick); err != nil {
-				return err
-			}
+	n := len(picksToUpdate) + len(newPicks)
+	picks := make([]firestore.Pick, 0, n)
+	refs := make([]*fs.DocumentRef, 0, n)
+	changes := make([]firestore.PickChange, 0, n)
+	for id, pick := range picksToUpdate {
+		before, ok := previous.LookupRef(id)
+		if !ok {
+			return fmt.Errorf("pick %s to update not found", id)
 		}
-		return nil
-	})
+		ref := picksCollection.Doc(id)
+		picks = append(picks, pick)
+		refs = append(refs, ref)
+		changes = append(changes, firestore.NewPickChange(&before, pick, ref, source, ctx.Actor))
+	}
+	for _, pick := range newPicks {
+		ref := picksCollection.NewDoc()
+		picks = append(picks, pick)
+		refs = append(refs, ref)
+		changes = append(changes, firestore.NewPickChange(nil, pick, ref, source, ctx.Actor))
+	}
 
+	locked, err := firestore.LockedPickChanges(ctx, lock, week, changes, time.Now())
 	if err != nil {
-		return fmt.Errorf("Pickem: failed to complete transaction to update picks: %w", err)
+		return fmt.Errorf("failed to check pick locks: %w", err)
+	}
+	if err := firestore.OverrideLocks(changes, locked, ctx.Override); err != nil {
+		return err
+	}
+	lockedErrs := make([]firestore.PickLockedError, 0, len(locked))
+	for i := range changes {
+		if l, ok := locked[i]; ok {
+			log.Printf("Overriding lock: %s", l)
+			lockedErrs = append(lockedErrs, l)
+		}
 	}
 
-	return nil
+	return ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
+		for i, pick := range picks {
+			var err error
+			if changes[i].Created {
+				err = t.Create(refs[i], &pick)
+			} else {
+				err = t.Set(refs[i], &pick)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if err := firestore.RecordPickChanges(t, weekRef, changes); err != nil {
+			return err
+		}
+		if len(lockedErrs) == 0 {
+			return nil
+		}
+		return firestore.RecordLockOverride(t, weekRef, firestore.NewLockOverride(pickerRef, lockedErrs, ctx.Override, source, ctx.Actor))
+	})
 }
 
 type slateGamesByTeam struct {
@@ -188,39 +235,11 @@ func newSlateGamesByTeam(ctx context.Context, games []firestore.SlateGame, refs
 			return nil, err
 		}
 
-		snap, err = game.HomeTeam.Get(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		var team firestore.Team
-		err = snap.DataTo(&team)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, name := range team.OtherNames {
-			if _, ok := lookup[name]; ok {
-				return nil, fmt.Errorf("newSlateGamesByTeam: ambiguous team OtherName '%s'", name)
+		for _, team := range []*fs.DocumentRef{game.HomeTeam, game.AwayTeam} {
+			if _, ok := lookup[team.ID]; ok {
+				return nil, fmt.Errorf("newSlateGamesByTeam: team '%s' appears in more than one slate game", team.ID)
 			}
-			lookup[name] = i
-		}
-
-		snap, err = game.AwayTeam.Get(ctx)
-		if err != nil {
-			return nil, err
-		}
-
-		err = snap.DataTo(&team)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, name := range team.OtherNames {
-			if _, ok := lookup[name]; ok {
-				return nil, fmt.Errorf("newSlateGamesByTeam: ambiguous team OtherName '%s'", name)
-			}
-			lookup[name] = i
+			lookup[team.ID] = i
 		}
 	}
 
@@ -231,6 +250,7 @@ func newSlateGamesByTeam(ctx context.Context, games []firestore.SlateGame, refs
 	}, nil
 }
 
+// Lookup finds the slate game played by the team with the given ID.
 func (s *slateGamesByTeam) Lookup(team string) (sg firestore.SlateGame, ref *fs.DocumentRef, ok bool) {
 	var idx int
 	idx, ok = s.indexLookup[team]
@@ -270,3 +290,13 @@ func (p *picksByGameID) Lookup(id string) (pick firestore.Pick, ref *fs.Document
 	ref = p.pickRefs[idx]
 	return
 }
+
+// LookupRef finds the pick stored in the document with the given ID.
+func (p *picksByGameID) LookupRef(id string) (pick firestore.Pick, ok bool) {
+	for i, ref := range p.pickRefs {
+		if ref.ID == id {
+			return p.picks[i], true
+		}
+	}
+	return
+}
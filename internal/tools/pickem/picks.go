@@ -7,16 +7,17 @@ import (
 	"math"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 
 	fs "cloud.google.com/go/firestore"
 	"cloud.google.com/go/storage"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
-	excelize "github.com/xuri/excelize/v2"
 )
 
+// ExportPicks exports a picker's picks, or with All the picks of every picker in the season, in the format given by the context.
 func ExportPicks(ctx *Context) error {
-	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
 	if err != nil {
 		return fmt.Errorf("ExportPicks: failed to get season %d: %w", ctx.Season, err)
 	}
@@ -24,126 +25,142 @@ func ExportPicks(ctx *Context) error {
 	if err != nil {
 		return fmt.Errorf("ExportPicks: failed to get week %d: %w", ctx.Week, err)
 	}
-	_, pickerRef, err := firestore.GetPickerByLukeName(ctx, ctx.FirestoreClient, ctx.Picker)
-	if err != nil {
-		return fmt.Errorf("ExportPicks: failed to get picker %s: %w", ctx.Picker, err)
-	}
 
-	picks, picksRef, err := firestore.GetPicks(ctx, weekRef, pickerRef)
-	if err != nil {
-		return fmt.Errorf("ExportPicks: failed to get picks: %w", err)
+	pickers := make(map[string]*fs.DocumentRef)
+	if ctx.All {
+		pickers = season.Pickers
+	} else {
+		_, pickerRef, err := firestore.GetPickerByLukeName(ctx, ctx.FirestoreClient, ctx.Picker)
+		if err != nil {
+			return fmt.Errorf("ExportPicks: failed to get picker %s: %w", ctx.Picker, err)
+		}
+		pickers[ctx.Picker] = pickerRef
 	}
+	names := make([]string, 0, len(pickers))
+	for name := range pickers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	btsPick, btsPickRef, err := firestore.GetStreakPick(ctx, weekRef, pickerRef)
-	if err != nil {
-		if _, ok := err.(firestore.NoStreakPickError); !ok {
-			return fmt.Errorf("ExportPicks: failed to get streak pick: %w", err)
+	exports := make([]pickerExport, len(names))
+	for i, name := range names {
+		exports[i], err = exportPicker(ctx, weekRef, name, pickers[name])
+		if err != nil {
+			return fmt.Errorf("ExportPicks: %w", err)
 		}
 	}
 
-	// Make me some rows!
-	xl, err := makePicksExcelFile(ctx, picks, picksRef, btsPick, btsPickRef)
-	if err != nil {
-		return fmt.Errorf("ExportPicks: failed to make pick rows: %w", err)
+	format := ctx.Format
+	if format == "" {
+		format = FormatXLSX
 	}
 
 	// Figure out output location
 	if ctx.Output == "" || ctx.DryRun {
 		// no location? Print the rows to screen
-		sheetName := xl.GetSheetName(xl.GetActiveSheetIndex())
-		rows, err := xl.Rows(sheetName)
-		if err != nil {
-			return fmt.Errorf("ExportPicks: failed to get Excel row iterator: %w", err)
-		}
-		for rows.Next() {
-			row, err := rows.Columns()
-			if err != nil {
-				return fmt.Errorf("ExportPicks: failed to get Excel cells from row iterator: %w", err)
+		if format == FormatXLSX {
+			for _, e := range exports {
+				if ctx.All {
+					fmt.Printf("%s\n", e.Picker)
+				}
+				for _, row := range e.Rows {
+					fmt.Println(strings.Join(row, ", "))
+				}
 			}
-			fmt.Println(strings.Join(row, ", "))
+			return nil
 		}
-		return nil
+		return writeExports(os.Stdout, format, exports, ctx.All)
 	}
 
-	writer, err := openFileOrGSWriter(ctx, ctx.Output)
+	writer, err := openFileOrGSWriter(ctx, ctx.Output, contentTypes[format])
 	if err != nil {
 		return fmt.Errorf("ExportPicks: failed to open '%s': %w", ctx.Output, err)
 	}
 	defer writer.Close()
 
-	_, err = xl.WriteTo(writer)
+	err = writeExports(writer, format, exports, ctx.All)
 	if err != nil {
-		return fmt.Errorf("ExportPicks: failed to write Excel file: %w", err)
+		return fmt.Errorf("ExportPicks: failed to write %s: %w", format, err)
 	}
 
 	return nil
 }
 
-func addRows(ctx context.Context, outExcel *excelize.File, sheetName string, rowNumber int, pick firestore.SlateRowBuilder) error {
-	out, err := pick.BuildSlateRows(ctx)
+// exportPicker builds the slate rows and summary of a picker's picks.
+func exportPicker(ctx context.Context, weekRef *fs.DocumentRef, name string, pickerRef *fs.DocumentRef) (pickerExport, error) {
+	picks, _, err := firestore.GetPicks(ctx, weekRef, pickerRef)
 	if err != nil {
-		return fmt.Errorf("failed making game output: %w", err)
+		return pickerExport{}, fmt.Errorf("failed to get picks of %s: %w", name, err)
 	}
-	for row, content := range out {
-		for col, str := range content {
-			index, err := excelize.CoordinatesToCellName(col+1, row+rowNumber+1)
-			if err != nil {
-				return err
-			}
-			outExcel.SetCellStr(sheetName, index, str)
+
+	btsPick, btsPickRef, err := firestore.GetStreakPick(ctx, weekRef, pickerRef)
+	if err != nil {
+		if _, ok := err.(firestore.NoStreakPickError); !ok {
+			return pickerExport{}, fmt.Errorf("failed to get streak pick of %s: %w", name, err)
 		}
 	}
-	return nil
-}
 
-func addStreakOverRow(outExcel *excelize.File, sheetName string, row int) error {
-	index, err := excelize.CoordinatesToCellName(1, row+1)
+	// Make me some rows!
+	e, err := makePicksRows(ctx, picks, btsPick, btsPickRef)
 	if err != nil {
-		return err
+		return e, fmt.Errorf("failed to make pick rows of %s: %w", name, err)
+	}
+	e.Picker = name
+	return e, nil
+}
+
+// setRows places rows in the grid starting at the given row, growing the grid as needed.
+func setRows(grid [][]string, rowNumber int, rows [][]string) [][]string {
+	for len(grid) < rowNumber+len(rows) {
+		grid = append(grid, nil)
+	}
+	for i, row := range rows {
+		grid[rowNumber+i] = row
 	}
-	outExcel.SetCellStr(sheetName, index, "BEAT THE STREAK!")
-	index, err = excelize.CoordinatesToCellName(3, row+1)
+	return grid
+}
+
+func addRows(ctx context.Context, grid [][]string, rowNumber int, pick firestore.SlateRowBuilder) ([][]string, error) {
+	out, err := pick.BuildSlateRows(ctx)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed making game output: %w", err)
 	}
-	outExcel.SetCellStr(sheetName, index, "STREAK OVER!")
-	return nil
+	return setRows(grid, rowNumber, out), nil
 }
 
-func makePicksExcelFile(ctx context.Context, picks []firestore.Pick, pickRefs []*fs.DocumentRef, btsPick firestore.StreakPick, btsPickRef *fs.DocumentRef) (*excelize.File, error) {
-	// Make an excel file in memory.
-	outExcel := excelize.NewFile()
-	sheetName := outExcel.GetSheetName(outExcel.GetActiveSheetIndex())
-	// Write the header row
-	outExcel.SetCellStr(sheetName, "A1", "GAME")
-	outExcel.SetCellStr(sheetName, "B1", "Instruction")
-	outExcel.SetCellStr(sheetName, "C1", "Your Selection")
-	outExcel.SetCellStr(sheetName, "D1", "Predicted Spread")
-	outExcel.SetCellStr(sheetName, "E1", "Notes")
-	outExcel.SetCellStr(sheetName, "F1", "Expected Value")
+// makePicksRows lays out picks as they appear on the slate: a header row, then each pick in its slate game's row, with the streak pick between the picks and the superdogs.
+func makePicksRows(ctx context.Context, picks []firestore.Pick, btsPick firestore.StreakPick, btsPickRef *fs.DocumentRef) (pickerExport, error) {
+	e := pickerExport{Streak: streakOver}
+	grid := [][]string{exportHeader}
 
 	lastPickRow := math.MinInt // need to calculate where the BTS row is
 	firstSDRow := math.MaxInt
-	slateGames := make([]firestore.SlateGame, len(picks))
 
-	for i, pick := range picks {
+	for _, pick := range picks {
 		snap, err := pick.SlateGame.Get(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("unable to get SlateGame for pick: %w", err)
+			return e, fmt.Errorf("unable to get SlateGame for pick: %w", err)
 		}
 		var sg firestore.SlateGame
 		if err = snap.DataTo(&sg); err != nil {
-			return nil, err
+			return e, err
 		}
-		slateGames[i] = sg
 		if !sg.Superdog && sg.Row > lastPickRow {
 			lastPickRow = sg.Row
 		}
 		if sg.Superdog && sg.Row < firstSDRow {
 			firstSDRow = sg.Row
 		}
-		if err = addRows(ctx, outExcel, sheetName, sg.Row, pick); err != nil {
-			return nil, err
+		if grid, err = addRows(ctx, grid, sg.Row, pick); err != nil {
+			return e, err
+		}
+		if pick.PickedTeam == nil {
+			continue
+		}
+		e.Picks++
+		e.ExpectedPoints += pick.PredictedProbability * float64(sg.Value)
+		if sg.Superdog {
+			e.Superdog = grid[sg.Row][2]
 		}
 	}
 
@@ -155,19 +172,25 @@ func makePicksExcelFile(ctx context.Context, picks []firestore.Pick, pickRefs []
 	// Between the picks and dogs
 	btsRow := (lastPickRow + firstSDRow) / 2
 	if btsPickRef != nil {
-		if err := addRows(ctx, outExcel, sheetName, btsRow, btsPick); err != nil {
-			return nil, err
+		out, err := btsPick.BuildSlateRows(ctx)
+		if err != nil {
+			return e, fmt.Errorf("failed making streak output: %w", err)
 		}
-	} else {
-		if err := addStreakOverRow(outExcel, sheetName, btsRow); err != nil {
-			return nil, err
+		grid = setRows(grid, btsRow, out)
+		teams := make([]string, len(out))
+		for i, row := range out {
+			teams[i] = row[2]
 		}
+		e.Streak = strings.Join(teams, ", ")
+	} else {
+		grid = setRows(grid, btsRow, [][]string{{"BEAT THE STREAK!", "", streakOver}})
 	}
 
-	return outExcel, nil
+	e.Rows = grid
+	return e, nil
 }
 
-func openFileOrGSWriter(ctx context.Context, f string) (io.WriteCloser, error) {
+func openFileOrGSWriter(ctx context.Context, f string, contentType string) (io.WriteCloser, error) {
 	u, err := url.Parse(f)
 	if err != nil {
 		return nil, err
@@ -184,7 +207,7 @@ func openFileOrGSWriter(ctx context.Context, f string) (io.WriteCloser, error) {
 		obj := bucket.Object(path)
 		w := obj.NewWriter(ctx)
 		// Setting the ContentType before writing is preferred, as net/http.DetectContentType assumes that XLSX files are ZIP archives
-		w.ObjectAttrs.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		w.ObjectAttrs.ContentType = contentType
 
 		return w, nil
 
@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
+)
+
+type Context struct {
+	context.Context
+
+	Force  bool
+	DryRun bool
+
+	FirestoreClient *fs.Client
+
+	// ProjectID and Token are passed to the programs the pipeline runs rather than calls.
+	ProjectID string
+	Token     string
+	// Actor is the authenticated commissioner running the pipeline, as recorded in the pick change log of migrated picks.
+	Actor firestore.Picker
+
+	Season int
+	Week   int
+
+	// Slate is the path of the official slate to parse. "{week}" is replaced with the week number, so one path can serve every week of a schedule.
+	Slate string
+	// Layout is the path to a JSON file describing the layout of the slate. If empty, the default layout is used.
+	Layout string
+
+	// Resolver resolves team names that are missing or duplicated. The pipeline runs unattended, so it should not ask on the terminal.
+	Resolver editteams.NameResolver
+	// Migrate moves picks made against a previously parsed slate of the week to the matching games in the new slate.
+	// The pipeline cannot ask for confirmation, so only picks whose games' terms did not change are migrated. Without it, stale picks are only reported.
+	Migrate bool
+
+	// Iterations is the number of simulated annealing iterations per streaker.
+	Iterations int
+
+	// Pickem4me is the pickem4me program, and AutoPickers are the LukeNames of the pickers it makes picks for.
+	Pickem4me   string
+	AutoPickers []string
+
+	// ExportDir is the directory the week's picks are exported to.
+	ExportDir string
+
+	// StateDir is the directory where the state of each week's run is kept, so a failed run can resume where it stopped.
+	StateDir string
+	// Restart ignores the state of previous runs and runs every step again.
+	Restart bool
+	// Only runs just the named steps (and checks their inputs), regardless of the state of previous runs.
+	Only []string
+
+	// Lead is how long before the first game of a week the scheduler runs the pipeline, and Retry is how long it waits to try again after a failure.
+	Lead  time.Duration
+	Retry time.Duration
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
@@ -0,0 +1,175 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// testSteps are steps a -> b -> c and an independent step d, recording which ran.
+func testSteps(ran *[]string, fail map[string]error, notReady map[string]error) []Step {
+	step := func(name string, after ...string) Step {
+		return Step{
+			Name:  name,
+			After: after,
+			Check: func(ctx *Context) error { return notReady[name] },
+			Run: func(ctx *Context) error {
+				*ran = append(*ran, name)
+				return fail[name]
+			},
+		}
+	}
+	return []Step{step("a"), step("b", "a"), step("c", "b"), step("d")}
+}
+
+func statuses(results []Result) map[string]Status {
+	s := make(map[string]Status)
+	for _, r := range results {
+		s[r.Step] = r.Status
+	}
+	return s
+}
+
+func TestRunStepsResumes(t *testing.T) {
+	ctx := NewContext(context.Background())
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadState(path, 2023, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(s State) error { return s.Save(path) }
+
+	var ran []string
+	fail := map[string]error{"b": errors.New("boom")}
+	results, err := RunSteps(ctx, testSteps(&ran, fail, nil), &state, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Status{"a": StatusDone, "b": StatusFailed, "c": StatusBlocked, "d": StatusDone}
+	for step, s := range statuses(results) {
+		if want[step] != s {
+			t.Errorf("step %s: expected %s, got %s", step, want[step], s)
+		}
+	}
+	if len(ran) != 3 {
+		t.Errorf("expected a, b, and d to run, got %v", ran)
+	}
+
+	// the next run picks up where the last one stopped
+	state, err = LoadState(path, 2023, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Steps["b"].Error != "boom" {
+		t.Errorf("expected the failure to be saved, got %+v", state.Steps["b"])
+	}
+	ran = nil
+	results, err = RunSteps(ctx, testSteps(&ran, nil, map[string]error{"c": errors.New("no input")}), &state, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "b" {
+		t.Errorf("expected only b to run, got %v", ran)
+	}
+	if s := statuses(results); s["c"] != StatusNotReady || !results[0].Resumed {
+		t.Errorf("expected c not to be ready and a to be resumed, got %+v", results)
+	}
+	if state.Done(testSteps(&ran, nil, nil)) {
+		t.Error("expected state not to be done")
+	}
+
+	ran = nil
+	if _, err := RunSteps(ctx, testSteps(&ran, nil, nil), &state, save); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "c" || !state.Done(testSteps(&ran, nil, nil)) {
+		t.Errorf("expected c to run and the week to be done, ran %v", ran)
+	}
+
+	// only runs named steps, even if they are done
+	ran = nil
+	ctx.Only = []string{"b"}
+	if _, err := RunSteps(ctx, testSteps(&ran, nil, nil), &state, save); err != nil {
+		t.Fatal(err)
+	}
+	if len(ran) != 1 || ran[0] != "b" {
+		t.Errorf("expected only b to run, got %v", ran)
+	}
+	ctx.Only = []string{"z"}
+	if _, err := RunSteps(ctx, testSteps(&ran, nil, nil), &state, save); err == nil {
+		t.Error("expected an unknown step to fail")
+	}
+
+	if _, err := LoadState(path, 2023, 3); err == nil {
+		t.Error("expected the state of another week to fail to load")
+	}
+}
+
+func TestRunStepsNotReady(t *testing.T) {
+	ctx := NewContext(context.Background())
+	state := State{Season: 2023, Week: 2, Steps: make(map[string]StepState)}
+	save := func(State) error { return nil }
+
+	// a step that is not ready blocks only the steps that use its output
+	var ran []string
+	results, err := RunSteps(ctx, testSteps(&ran, nil, map[string]error{"a": errors.New("no input")}), &state, save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Status{"a": StatusNotReady, "b": StatusBlocked, "c": StatusBlocked, "d": StatusDone}
+	for step, s := range statuses(results) {
+		if want[step] != s {
+			t.Errorf("step %s: expected %s, got %s", step, want[step], s)
+		}
+	}
+	if len(ran) != 1 || ran[0] != "d" {
+		t.Errorf("expected only d to run, got %v", ran)
+	}
+}
+
+func TestValidateSteps(t *testing.T) {
+	if err := validate(Steps); err != nil {
+		t.Errorf("expected the pipeline's steps to be valid, got %v", err)
+	}
+	// making and exporting picks does not wait on streak predictions, which weeks without active streakers do not have
+	for _, step := range Steps {
+		for _, after := range step.After {
+			if after == "simulate-anneal" {
+				t.Errorf("expected step %s not to come after simulate-anneal", step.Name)
+			}
+		}
+	}
+	if err := validate([]Step{{Name: "a", After: []string{"b"}}, {Name: "b"}}); err == nil {
+		t.Error("expected a step after a later step to be invalid")
+	}
+	if err := validate([]Step{{Name: "a"}, {Name: "a"}}); err == nil {
+		t.Error("expected duplicate steps to be invalid")
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2023, 9, 10, 12, 0, 0, 0, time.UTC)
+	weeks := []firestore.Week{
+		{Number: 3, FirstGameStart: now.Add(6 * 24 * time.Hour)},
+		{Number: 1, FirstGameStart: now.Add(-24 * time.Hour)},
+		{Number: 2, FirstGameStart: now.Add(time.Hour)},
+	}
+	lead := 48 * time.Hour
+	notDone := func(int) bool { return false }
+
+	w, at, ok := nextRun(weeks, notDone, lead, now)
+	if !ok || w.Number != 2 || !at.Equal(now) {
+		t.Errorf("expected week 2 to run now, got week %d at %s", w.Number, at)
+	}
+	w, at, ok = nextRun(weeks, func(week int) bool { return week == 2 }, lead, now)
+	if !ok || w.Number != 3 || !at.Equal(now.Add(4*24*time.Hour)) {
+		t.Errorf("expected week 3 to run 2 days before kickoff, got week %d at %s", w.Number, at)
+	}
+	if _, _, ok := nextRun(weeks, notDone, lead, now.Add(7*24*time.Hour)); ok {
+		t.Error("expected no week to run after the season")
+	}
+}
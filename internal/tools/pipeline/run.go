@@ -0,0 +1,255 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// Status is the outcome of a step.
+type Status string
+
+const (
+	// StatusDone steps ran successfully, in this run or a previous one.
+	StatusDone Status = "done"
+	// StatusFailed steps ran and returned an error.
+	StatusFailed Status = "failed"
+	// StatusNotReady steps did not run because their inputs were not ready.
+	StatusNotReady Status = "not ready"
+	// StatusBlocked steps did not run because a step they come after did not finish.
+	StatusBlocked Status = "blocked"
+)
+
+// StepState is the outcome of the most recent attempt at a step.
+type StepState struct {
+	Status   Status    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// State is what the runs of the pipeline for a week have done so far. Steps that are done are not run again.
+type State struct {
+	Season int                  `json:"season"`
+	Week   int                  `json:"week"`
+	Steps  map[string]StepState `json:"steps"`
+}
+
+// Done returns whether every one of the steps is done.
+func (s State) Done(steps []Step) bool {
+	for _, step := range steps {
+		if s.Steps[step.Name].Status != StatusDone {
+			return false
+		}
+	}
+	return true
+}
+
+// Result is what happened to a step in a run.
+type Result struct {
+	Step     string
+	Status   Status
+	Duration time.Duration
+	Err      error
+
+	// Resumed is true if the step was done in a previous run and so was not run again.
+	Resumed bool
+}
+
+// statePath returns the path of the state file of the context's week.
+func statePath(ctx *Context) string {
+	return filepath.Join(ctx.StateDir, fmt.Sprintf("pipeline-%d-week-%d.json", ctx.Season, ctx.Week))
+}
+
+// LoadState reads the state of a week's runs from a file. A missing file is a week that has not run yet.
+func LoadState(path string, season, week int) (State, error) {
+	state := State{Season: season, Week: week, Steps: make(map[string]StepState)}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("LoadState: failed to read '%s': %w", path, err)
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return state, fmt.Errorf("LoadState: failed to parse '%s': %w", path, err)
+	}
+	if state.Season != season || state.Week != week {
+		return state, fmt.Errorf("LoadState: '%s' is the state of season %d week %d, not season %d week %d", path, state.Season, state.Week, season, week)
+	}
+	if state.Steps == nil {
+		state.Steps = make(map[string]StepState)
+	}
+	return state, nil
+}
+
+// Save writes the state to a file, replacing it whole so a crash cannot leave it half written.
+func (s State) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Save: failed to encode state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("Save: failed to make state directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("Save: failed to write '%s': %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("Save: failed to replace '%s': %w", path, err)
+	}
+	return nil
+}
+
+// validate checks that every step has a unique name and comes after only steps earlier in the list.
+func validate(steps []Step) error {
+	seen := make(map[string]bool)
+	for _, step := range steps {
+		if seen[step.Name] {
+			return fmt.Errorf("step '%s' is defined more than once", step.Name)
+		}
+		for _, after := range step.After {
+			if !seen[after] {
+				return fmt.Errorf("step '%s' comes after step '%s', which is not an earlier step", step.Name, after)
+			}
+		}
+		seen[step.Name] = true
+	}
+	return nil
+}
+
+// RunSteps runs the steps in order and records each outcome in the state, calling save after every step that runs.
+// Steps done in the state are not run again unless they are named in ctx.Only, in which case only the named steps run.
+// A step runs only if the steps it comes after are done and its check passes; steps that do not depend on a failed step still run.
+func RunSteps(ctx *Context, steps []Step, state *State, save func(State) error) ([]Result, error) {
+	if err := validate(steps); err != nil {
+		return nil, fmt.Errorf("RunSteps: %w", err)
+	}
+	only := make(map[string]bool)
+	for _, name := range ctx.Only {
+		only[name] = true
+	}
+	for name := range only {
+		found := false
+		for _, step := range steps {
+			found = found || step.Name == name
+		}
+		if !found {
+			return nil, fmt.Errorf("RunSteps: no step named '%s'", name)
+		}
+	}
+
+	results := make([]Result, 0, len(steps))
+	for _, step := range steps {
+		prev := state.Steps[step.Name]
+		if len(only) > 0 && !only[step.Name] {
+			results = append(results, Result{Step: step.Name, Status: prev.Status, Resumed: true})
+			continue
+		}
+		if len(only) == 0 && prev.Status == StatusDone {
+			results = append(results, Result{Step: step.Name, Status: StatusDone, Resumed: true})
+			continue
+		}
+
+		r := Result{Step: step.Name}
+		started := time.Now()
+		var blockers []string
+		for _, after := range step.After {
+			if state.Steps[after].Status != StatusDone && len(only) == 0 {
+				blockers = append(blockers, after)
+			}
+		}
+		switch {
+		case len(blockers) > 0:
+			r.Status = StatusBlocked
+			r.Err = fmt.Errorf("waiting on %v", blockers)
+		case step.Check != nil:
+			if err := step.Check(ctx); err != nil {
+				r.Status = StatusNotReady
+				r.Err = err
+			}
+		}
+		if r.Err == nil {
+			log.Printf("Running step %s", step.Name)
+			if err := step.Run(ctx); err != nil {
+				r.Status = StatusFailed
+				r.Err = err
+			} else {
+				r.Status = StatusDone
+			}
+		}
+		finished := time.Now()
+		r.Duration = finished.Sub(started)
+		results = append(results, r)
+
+		s := StepState{Status: r.Status, Started: started, Finished: finished}
+		if r.Err != nil {
+			s.Error = r.Err.Error()
+		}
+		state.Steps[step.Name] = s
+		if err := save(*state); err != nil {
+			return results, fmt.Errorf("RunSteps: failed to save state: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// Report prints a summary of a run.
+func Report(results []Result) {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(os.Stdout)
+	tw.AppendHeader(table.Row{"Step", "Status", "Time", "Error"})
+	for _, r := range results {
+		status := string(r.Status)
+		if status == "" {
+			status = "not run"
+		}
+		if r.Resumed {
+			status += " (previous run)"
+		}
+		errText := ""
+		if r.Err != nil {
+			errText = r.Err.Error()
+		}
+		tw.AppendRow(table.Row{r.Step, status, r.Duration.Round(time.Second), errText})
+	}
+	tw.SetStyle(table.StyleLight)
+	tw.Render()
+}
+
+// Run runs the weekly pipeline for a week, resuming from the state of previous runs, and prints a summary.
+// It returns an error if any step is not done, so that running it again resumes the steps that remain.
+func Run(ctx *Context) error {
+	path := statePath(ctx)
+	state, err := LoadState(path, ctx.Season, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("Run: %w", err)
+	}
+	if ctx.Restart {
+		state.Steps = make(map[string]StepState)
+	}
+	save := func(s State) error { return s.Save(path) }
+	if ctx.DryRun {
+		// a dry run does nothing that later runs can build on
+		save = func(State) error { return nil }
+	}
+
+	results, err := RunSteps(ctx, Steps, &state, save)
+	Report(results)
+	if err != nil {
+		return fmt.Errorf("Run: %w", err)
+	}
+	for _, r := range results {
+		if !r.Resumed && r.Status != StatusDone {
+			return fmt.Errorf("Run: season %d week %d is not finished: fix the steps that did not finish and run again to resume (state in %s)", ctx.Season, ctx.Week, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// nextRun returns the week the scheduler runs next and when: the first week that has not kicked off and is not done, at Lead before its first game.
+// If that time has passed, the week runs now. It returns false if every week has kicked off or is done.
+func nextRun(weeks []firestore.Week, done func(week int) bool, lead time.Duration, now time.Time) (firestore.Week, time.Time, bool) {
+	sorted := make([]firestore.Week, len(weeks))
+	copy(sorted, weeks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Number < sorted[j].Number })
+	for _, w := range sorted {
+		if w.FirstGameStart.IsZero() || !w.FirstGameStart.After(now) || done(w.Number) {
+			continue
+		}
+		at := w.FirstGameStart.Add(-lead)
+		if at.Before(now) {
+			at = now
+		}
+		return w, at, true
+	}
+	return firestore.Week{}, time.Time{}, false
+}
+
+// Schedule runs the weekly pipeline for every remaining week of the season, each at Lead before the week's first game.
+// A week whose run does not finish is tried again every Retry until its first game kicks off. It returns when every week has kicked off or the context is cancelled.
+func Schedule(ctx *Context) error {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("Schedule: failed to get season: %w", err)
+	}
+	if ctx.DryRun {
+		return fmt.Errorf("Schedule: dry runs record nothing, so a scheduled dry run would run the same week over and over: use run with --dry-run instead")
+	}
+	if ctx.Retry <= 0 {
+		return fmt.Errorf("Schedule: retry interval must be positive, got %s", ctx.Retry)
+	}
+	for {
+		// weeks are read every time around so that changes to the schedule are picked up
+		weeks, _, err := firestore.GetWeeks(ctx, seasonRef)
+		if err != nil {
+			return fmt.Errorf("Schedule: failed to get weeks: %w", err)
+		}
+		done := func(week int) bool {
+			state, err := LoadState(statePath(&Context{StateDir: ctx.StateDir, Season: ctx.Season, Week: week}), ctx.Season, week)
+			return err == nil && state.Done(Steps)
+		}
+		week, at, ok := nextRun(weeks, done, ctx.Lead, time.Now())
+		if !ok {
+			log.Printf("No weeks of season %d left to run", ctx.Season)
+			return nil
+		}
+		log.Printf("Next run: week %d at %s", week.Number, at.Local().Format(time.RFC1123))
+		if !sleepUntil(ctx, at) {
+			return ctx.Err()
+		}
+
+		wctx := *ctx
+		wctx.Week = week.Number
+		wctx.Restart = false
+		wctx.Only = nil
+		if err := Run(&wctx); err != nil {
+			log.Printf("Week %d did not finish, trying again in %s: %v", week.Number, ctx.Retry, err)
+			if !sleepUntil(ctx, time.Now().Add(ctx.Retry)) {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// sleepUntil waits until the given time, returning false if the context is cancelled first.
+func sleepUntil(ctx *Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,278 @@
+package pipeline
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/bts/sa"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/parseslate"
+	"github.com/reallyasi9/b1gpickem/internal/tools/pickem"
+	"github.com/reallyasi9/b1gpickem/internal/tools/updatemodels"
+)
+
+// Step is one step of the weekly pipeline.
+type Step struct {
+	// Name identifies the step in the state file and the summary.
+	Name string
+
+	// After are the names of the steps whose outputs this step uses. A step does not run if any of them did not succeed.
+	After []string
+
+	// Check returns an error if the step's inputs are not ready. It is checked just before the step runs.
+	Check func(ctx *Context) error
+
+	// Run does the step.
+	Run func(ctx *Context) error
+}
+
+// Steps are the steps of the weekly pipeline, in the order they run.
+var Steps = []Step{
+	{
+		Name:  "models-update",
+		Check: hasWeek,
+		Run: func(ctx *Context) error {
+			uctx := updatemodels.NewContext(ctx)
+			uctx.FirestoreClient = ctx.FirestoreClient
+			uctx.Force = ctx.Force
+			uctx.DryRun = ctx.DryRun
+			uctx.Season = ctx.Season
+			uctx.Week = ctx.Week
+			return updatemodels.UpdateModels(uctx)
+		},
+	},
+	{
+		Name:  "get-predictions",
+		After: []string{"models-update"},
+		Check: hasGames,
+		Run: func(ctx *Context) error {
+			uctx := updatemodels.NewContext(ctx)
+			uctx.FirestoreClient = ctx.FirestoreClient
+			uctx.Force = ctx.Force
+			uctx.DryRun = ctx.DryRun
+			uctx.Season = ctx.Season
+			uctx.Week = ctx.Week
+			return updatemodels.GetPredictions(uctx)
+		},
+	},
+	{
+		Name:  "update-sagarin",
+		After: []string{"models-update"},
+		Check: hasWeek,
+		Run: func(ctx *Context) error {
+			uctx := updatemodels.NewContext(ctx)
+			uctx.FirestoreClient = ctx.FirestoreClient
+			uctx.Force = ctx.Force
+			uctx.DryRun = ctx.DryRun
+			uctx.Season = ctx.Season
+			uctx.Week = ctx.Week
+			uctx.Resolver = ctx.Resolver
+			return updatemodels.UpdateSagarin(uctx)
+		},
+	},
+	{
+		Name:  "slate-parse",
+		Check: hasSlateFile,
+		Run: func(ctx *Context) error {
+			pctx := parseslate.NewContext(ctx)
+			pctx.FirestoreClient = ctx.FirestoreClient
+			pctx.Force = ctx.Force
+			pctx.DryRun = ctx.DryRun
+			pctx.Season = ctx.Season
+			pctx.Week = ctx.Week
+			pctx.Slate = ctx.slatePath()
+			pctx.Layout = ctx.Layout
+			pctx.Migrate = ctx.Migrate
+			pctx.Resolver = ctx.Resolver
+			pctx.Actor = ctx.Actor
+			return parseslate.ParseSlate(pctx)
+		},
+	},
+	{
+		Name:  "simulate-anneal",
+		After: []string{"get-predictions", "update-sagarin"},
+		Check: hasWeek,
+		Run: func(ctx *Context) error {
+			active, err := hasActiveStreakers(ctx)
+			if err != nil {
+				return err
+			}
+			if !active {
+				log.Printf("No active streakers in week %d", ctx.Week)
+				return nil
+			}
+			sctx := sa.NewContext(ctx)
+			sctx.FirestoreClient = ctx.FirestoreClient
+			sctx.Force = ctx.Force
+			sctx.DryRun = ctx.DryRun
+			sctx.Season = ctx.Season
+			sctx.Week = ctx.Week
+			sctx.All = true
+			sctx.Seed = -1
+			sctx.Workers = 1
+			sctx.Iterations = ctx.Iterations
+			sctx.WanderLimit = 10000
+			sctx.C = 1
+			sctx.E = 3
+			return sa.Anneal(sctx)
+		},
+	},
+	{
+		Name:  "pickem4me",
+		After: []string{"slate-parse", "get-predictions", "update-sagarin"},
+		Check: hasModelPerformances,
+		Run:   runPickem4me,
+	},
+	{
+		Name:  "picks-export",
+		After: []string{"slate-parse", "pickem4me"},
+		Check: hasSlate,
+		Run: func(ctx *Context) error {
+			pctx := pickem.NewContext(ctx)
+			pctx.FirestoreClient = ctx.FirestoreClient
+			pctx.Force = ctx.Force
+			pctx.DryRun = ctx.DryRun
+			pctx.Season = ctx.Season
+			pctx.Week = ctx.Week
+			pctx.All = true
+			pctx.Format = pickem.FormatXLSX
+			pctx.Output = filepath.Join(ctx.ExportDir, fmt.Sprintf("picks-%d-week-%d.xlsx", ctx.Season, ctx.Week))
+			if ctx.DryRun {
+				log.Printf("DRY RUN: would export picks to %s", pctx.Output)
+				return nil
+			}
+			return pickem.ExportPicks(pctx)
+		},
+	},
+}
+
+// slatePath returns the path of the week's slate.
+func (ctx *Context) slatePath() string {
+	return strings.ReplaceAll(ctx.Slate, "{week}", strconv.Itoa(ctx.Week))
+}
+
+func week(ctx *Context) (*fs.DocumentRef, error) {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return nil, fmt.Errorf("no season %d: %w", ctx.Season, err)
+	}
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return nil, fmt.Errorf("no week %d in season %d: %w", ctx.Week, ctx.Season, err)
+	}
+	return weekRef, nil
+}
+
+func hasWeek(ctx *Context) error {
+	_, err := week(ctx)
+	return err
+}
+
+func hasGames(ctx *Context) error {
+	weekRef, err := week(ctx)
+	if err != nil {
+		return err
+	}
+	games, _, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("failed to get games: %w", err)
+	}
+	if len(games) == 0 {
+		return fmt.Errorf("week %d has no games: has the season been set up?", ctx.Week)
+	}
+	return nil
+}
+
+func hasSlateFile(ctx *Context) error {
+	path := ctx.slatePath()
+	if path == "" {
+		return fmt.Errorf("no slate file given")
+	}
+	if strings.HasPrefix(path, "gs://") {
+		return nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("slate file not available yet: %w", err)
+	}
+	return nil
+}
+
+func hasSlate(ctx *Context) error {
+	weekRef, err := week(ctx)
+	if err != nil {
+		return err
+	}
+	if _, _, err := firestore.GetSlateGames(ctx, weekRef); err != nil {
+		return fmt.Errorf("no slate parsed: %w", err)
+	}
+	return nil
+}
+
+// hasActiveStreakers returns whether any picker still has streak picks to make in the week.
+// Weeks without active streakers have nothing to anneal, which is not a reason to hold up the pipeline.
+func hasActiveStreakers(ctx *Context) (bool, error) {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return false, fmt.Errorf("no season %d: %w", ctx.Season, err)
+	}
+	weekRef, err := week(ctx)
+	if err != nil {
+		return false, err
+	}
+	streaks, _, err := firestore.GetRemainingStreaks(ctx, seasonRef, weekRef)
+	if err != nil {
+		return false, fmt.Errorf("failed to get remaining streaks: %w", err)
+	}
+	for _, s := range streaks {
+		if s.Active() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func hasModelPerformances(ctx *Context) error {
+	if err := hasSlate(ctx); err != nil {
+		return err
+	}
+	weekRef, err := week(ctx)
+	if err != nil {
+		return err
+	}
+	if _, _, err := firestore.GetMostRecentModelPerformances(ctx, ctx.FirestoreClient, weekRef); err != nil {
+		return fmt.Errorf("no model performances: %w", err)
+	}
+	return nil
+}
+
+// runPickem4me runs the pickem4me program for each of the auto pickers.
+func runPickem4me(ctx *Context) error {
+	if len(ctx.AutoPickers) == 0 {
+		log.Print("No pickers to make picks for")
+		return nil
+	}
+	for _, picker := range ctx.AutoPickers {
+		args := []string{"--project-id", ctx.ProjectID, "--fallback"}
+		if ctx.DryRun {
+			args = append(args, "--dry-run")
+		}
+		if ctx.Force {
+			args = append(args, "--force")
+		}
+		args = append(args, strconv.Itoa(ctx.Season), strconv.Itoa(ctx.Week), picker)
+		cmd := exec.CommandContext(ctx, ctx.Pickem4me, args...)
+		cmd.Env = append(os.Environ(), "B1GPICKEM_TOKEN="+ctx.Token)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("pickem4me failed for picker '%s': %w", picker, err)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/reallyasi9/b1gpickem/internal/firestore"
@@ -20,11 +21,23 @@ func AddTeams(ctx *Context) error {
 		return fmt.Errorf("AddTeams: failed to get season %d: %w", ctx.Season, err)
 	}
 
-	teams, teamRefs, err := firestore.GetTeams(ctx.Context, seasonRef)
+	lookup, err := firestore.GetTeamResolver(ctx.Context, seasonRef)
 	if err != nil {
 		return fmt.Errorf("AddTeams: failed to get teams: %w", err)
 	}
-	lookup := firestore.NewTeamRefsByOtherName(teams, teamRefs)
+
+	var preseason map[string]int
+	if ctx.Poll != "" {
+		_, firstWeekRef, err := firestore.GetFirstWeek(ctx.Context, seasonRef)
+		if err != nil {
+			return fmt.Errorf("AddTeams: failed to get first week of season %d: %w", ctx.Season, err)
+		}
+		ranking, _, err := firestore.GetRanking(ctx.Context, firstWeekRef, ctx.Poll)
+		if err != nil {
+			return fmt.Errorf("AddTeams: failed to get preseason ranking: %w", err)
+		}
+		preseason = ranking.Ranks()
+	}
 
 	teamsToAdd := make(map[string]float64)
 	if ctx.Append {
@@ -33,9 +46,16 @@ func AddTeams(ctx *Context) error {
 		}
 	}
 	for name, wins := range ctx.TeamNameWins {
-		ref, found := lookup[name]
-		if !found {
-			return fmt.Errorf("AddTeams: failed to find team with other name '%s'", name)
+		ref, err := lookup.Resolve(name, firestore.OtherName)
+		if err != nil {
+			return fmt.Errorf("AddTeams: %w", err)
+		}
+		if preseason != nil {
+			wins = math.Abs(wins)
+			if rank, ranked := preseason[ref.ID]; ranked {
+				log.Printf("%s is #%d in the preseason %s: adding as a top 25 pony", name, rank, ctx.Poll)
+				wins = -wins
+			}
 		}
 		teamsToAdd[ref.ID] = wins
 	}
@@ -17,6 +17,10 @@ type Context struct {
 	Season       int
 	TeamNameWins map[string]float64
 	Append       bool
+
+	// Poll is the name of a poll whose preseason ranking (stored for the season's first week) decides which added teams are top 25 ponies.
+	// Ranked teams get negative wins and unranked teams get positive wins, whatever the sign given. If empty, wins are used as given.
+	Poll string
 }
 
 // NewContext creates and returns a pypteams.Context from a base context object.
@@ -0,0 +1,89 @@
+package rankings
+
+import (
+	"fmt"
+	"os"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// CheckSlate compares the ranks on a week's slate with the most recent stored ranking of each poll in ctx.Polls
+// (or firestore.DefaultPoll) and prints the teams whose ranks do not match.
+func CheckSlate(ctx *Context) error {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("CheckSlate: failed to get season: %w", err)
+	}
+
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("CheckSlate: failed to get week: %w", err)
+	}
+
+	var sgames []firestore.SlateGame
+	if ctx.Slate == "" {
+		sgames, _, err = firestore.GetSlateGames(ctx, weekRef)
+	} else {
+		sgames, _, err = firestore.GetSlateGamesFromSlate(ctx, weekRef.Collection(firestore.SLATES_COLLECTION).Doc(ctx.Slate))
+	}
+	if err != nil {
+		return fmt.Errorf("CheckSlate: failed to get slate games: %w", err)
+	}
+
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return fmt.Errorf("CheckSlate: failed to get games: %w", err)
+	}
+
+	polls := ctx.Polls
+	if len(polls) == 0 {
+		polls = []string{firestore.DefaultPoll}
+	}
+	for _, poll := range polls {
+		ranking, rankingRef, err := firestore.GetLatestRanking(ctx, seasonRef, ctx.Week, poll)
+		if err != nil {
+			return fmt.Errorf("CheckSlate: failed to get ranking: %w", err)
+		}
+		mismatches, err := firestore.CompareSlateRanks(sgames, GamesByID(games, gameRefs), ranking.Ranks())
+		if err != nil {
+			return fmt.Errorf("CheckSlate: %w", err)
+		}
+		fmt.Printf("Slate ranks compared with %s (%s):\n", ranking.Poll, rankingRef.Path)
+		PrintMismatches(mismatches)
+	}
+	return nil
+}
+
+// GamesByID maps games by the IDs of their documents.
+func GamesByID(games []firestore.Game, refs []*fs.DocumentRef) map[string]firestore.Game {
+	m := make(map[string]firestore.Game)
+	for i, g := range games {
+		m[refs[i].ID] = g
+	}
+	return m
+}
+
+// PrintMismatches prints a table of rank mismatches to standard output.
+func PrintMismatches(mismatches []firestore.RankMismatch) {
+	if len(mismatches) == 0 {
+		fmt.Println("All slate ranks match.")
+		return
+	}
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Row", "Game", "Team", "Slate Rank", "Poll Rank"})
+	for _, m := range mismatches {
+		t.AppendRow(table.Row{m.Row, m.Game.ID, m.Team.ID, rankString(m.SlateRank), rankString(m.PollRank)})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
+
+func rankString(r int) string {
+	if r == 0 {
+		return "unranked"
+	}
+	return fmt.Sprintf("#%d", r)
+}
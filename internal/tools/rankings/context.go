@@ -0,0 +1,33 @@
+package rankings
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+
+	Force  bool
+	DryRun bool
+
+	FirestoreClient *fs.Client
+	ApiKey          string
+
+	Season int
+	Week   int
+
+	// SeasonType is the CollegeFootballData season type of the week's polls: "regular" or "postseason".
+	SeasonType string
+
+	// Polls are the names of the polls to store or check. If empty, every poll is stored, and rankings are checked against firestore.DefaultPoll.
+	Polls []string
+
+	// Slate is the ID of the slate to check. If empty, the most recently parsed slate is checked.
+	Slate string
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
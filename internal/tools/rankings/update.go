@@ -0,0 +1,92 @@
+package rankings
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/cfbdata"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// UpdateRankings gets the polls released going into a week from CollegeFootballData and stores them in the week's rankings collection.
+func UpdateRankings(ctx *Context) error {
+	httpClient := http.DefaultClient
+
+	rankings, err := cfbdata.GetRankings(httpClient, ctx.ApiKey, ctx.Season, ctx.Week, ctx.SeasonType)
+	if err != nil {
+		return fmt.Errorf("UpdateRankings: failed to get rankings: %w", err)
+	}
+
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("UpdateRankings: failed to get season: %w", err)
+	}
+
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("UpdateRankings: failed to get week: %w", err)
+	}
+
+	tr, err := firestore.GetTeamResolver(ctx, seasonRef)
+	if err != nil {
+		return fmt.Errorf("UpdateRankings: failed to get teams: %w", err)
+	}
+
+	keep := make(map[string]struct{})
+	for _, p := range ctx.Polls {
+		keep[firestore.PollID(p)] = struct{}{}
+	}
+
+	fetched := time.Now()
+	polls := make(map[*fs.DocumentRef]firestore.Ranking)
+	for _, r := range rankings {
+		for _, p := range r.Polls {
+			id := firestore.PollID(p.Poll)
+			if _, ok := keep[id]; len(keep) > 0 && !ok {
+				continue
+			}
+			ranking, err := p.ToFirestore(tr, fetched)
+			if err != nil {
+				return fmt.Errorf("UpdateRankings: %w", err)
+			}
+			polls[weekRef.Collection(firestore.RANKINGS_COLLECTION).Doc(id)] = ranking
+		}
+	}
+	if len(polls) == 0 {
+		return fmt.Errorf("UpdateRankings: no polls found for season %d week %d", ctx.Season, ctx.Week)
+	}
+
+	if ctx.DryRun {
+		log.Print("DRY RUN: would write the following to firestore:")
+		for ref, r := range polls {
+			log.Printf("%s: %s", ref.Path, r)
+		}
+		return nil
+	}
+
+	err = ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
+		for ref, r := range polls {
+			var err error
+			if ctx.Force {
+				err = t.Set(ref, &r)
+			} else {
+				err = t.Create(ref, &r)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("UpdateRankings: failed to store rankings: %w", err)
+	}
+	for ref, r := range polls {
+		log.Printf("Stored %d teams of %s to %s", len(r.Teams), r.Poll, ref.Path)
+	}
+	return nil
+}
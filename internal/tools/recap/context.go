@@ -0,0 +1,29 @@
+package recap
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+
+	FirestoreClient *fs.Client
+
+	Season int
+	Week   int
+
+	// Format is the format of the recap: FormatMarkdown or FormatHTML.
+	Format string
+
+	// Template is the path to a template file to use instead of the built-in template for Format.
+	Template string
+
+	// Output is the path to write the recap to. If empty, the recap is printed to stdout.
+	Output string
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
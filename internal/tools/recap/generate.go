@@ -0,0 +1,239 @@
+package recap
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Recap formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var templateFiles = map[string]string{
+	FormatMarkdown: "templates/recap.md.tmpl",
+	FormatHTML:     "templates/recap.html.tmpl",
+}
+
+// Funcs are the functions available to recap templates in addition to the text/template builtins.
+var Funcs = template.FuncMap{
+	"pct": func(p float64) string {
+		return fmt.Sprintf("%0.0f%%", 100*p)
+	},
+	"signed": func(x float64) string {
+		return fmt.Sprintf("%+0.1f", x)
+	},
+	"move": func(m int) string {
+		switch {
+		case m > 0:
+			return fmt.Sprintf("▲%d", m)
+		case m < 0:
+			return fmt.Sprintf("▼%d", -m)
+		}
+		return "–"
+	},
+	"ranked": func(rank int, team string) string {
+		if rank > 0 {
+			return fmt.Sprintf("#%d %s", rank, team)
+		}
+		return team
+	},
+	"join": strings.Join,
+}
+
+// Template returns the built-in recap template for the format, or the template in file if file is not empty.
+func Template(format string, file string) (*template.Template, error) {
+	if file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		return template.New(filepath.Base(file)).Funcs(Funcs).Parse(string(b))
+	}
+	name, ok := templateFiles[format]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized format '%s'", format)
+	}
+	return template.New(path.Base(name)).Funcs(Funcs).ParseFS(templates, name)
+}
+
+// GenerateRecap renders the recap of a week from the slate, picks, scores, and streak picks stored for it.
+func GenerateRecap(ctx *Context) error {
+	format := ctx.Format
+	if format == "" {
+		format = FormatMarkdown
+	}
+	t, err := Template(format, ctx.Template)
+	if err != nil {
+		return fmt.Errorf("GenerateRecap: failed to load template: %w", err)
+	}
+
+	in, err := getInput(ctx)
+	if err != nil {
+		return fmt.Errorf("GenerateRecap: %w", err)
+	}
+	r, err := Build(in)
+	if err != nil {
+		return fmt.Errorf("GenerateRecap: failed to build recap: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if ctx.Output != "" {
+		f, err := os.Create(ctx.Output)
+		if err != nil {
+			return fmt.Errorf("GenerateRecap: failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := t.Execute(w, r); err != nil {
+		return fmt.Errorf("GenerateRecap: failed to render recap: %w", err)
+	}
+	return nil
+}
+
+func getInput(ctx *Context) (Input, error) {
+	in := Input{Season: ctx.Season, Week: ctx.Week}
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return in, fmt.Errorf("failed to get season: %w", err)
+	}
+	in.Pickers = make(map[string]string)
+	for name, ref := range season.Pickers {
+		in.Pickers[ref.ID] = name
+	}
+	in.PonyTeams = season.PonyTeams
+
+	teams, teamRefs, err := firestore.GetTeams(ctx, seasonRef)
+	if err != nil {
+		return in, fmt.Errorf("failed to get teams: %w", err)
+	}
+	in.Teams = make(map[string]string)
+	for i, ref := range teamRefs {
+		in.Teams[ref.ID] = teams[i].School
+	}
+
+	in.Previous, err = firestore.GetWeekResults(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return in, fmt.Errorf("failed to get results of previous weeks: %w", err)
+	}
+
+	weeks, weekRefs, err := firestore.GetWeeks(ctx, seasonRef)
+	if err != nil {
+		return in, fmt.Errorf("failed to get weeks: %w", err)
+	}
+	var weekRef, nextRef *fs.DocumentRef
+	in.PonyWins = make(map[string]int)
+	in.PonyGames = make(map[string]int)
+	for i, week := range weeks {
+		if week.Number == ctx.Week+1 {
+			nextRef = weekRefs[i]
+		}
+		if week.Number > ctx.Week {
+			continue
+		}
+		games, gameRefs, err := firestore.GetGames(ctx, weekRefs[i])
+		if err != nil {
+			return in, fmt.Errorf("failed to get games of week %d: %w", week.Number, err)
+		}
+		countPonyWins(in.PonyTeams, games, in.PonyWins, in.PonyGames)
+		if week.Number == ctx.Week {
+			weekRef = weekRefs[i]
+			in.Games = make(map[string]firestore.Game)
+			for j, ref := range gameRefs {
+				in.Games[ref.ID] = games[j]
+			}
+		}
+	}
+	if weekRef == nil {
+		return in, fmt.Errorf("week %d not found", ctx.Week)
+	}
+
+	in.SlateGames, in.SlateRefs, err = firestore.GetSlateGames(ctx, weekRef)
+	if err != nil {
+		return in, fmt.Errorf("failed to get slate games: %w", err)
+	}
+	in.Picks, _, err = firestore.GetWeekPicks(ctx, weekRef)
+	if err != nil {
+		return in, fmt.Errorf("failed to get picks: %w", err)
+	}
+	in.StreakPicks, _, err = firestore.GetStreakPicks(ctx, weekRef)
+	if err != nil {
+		return in, fmt.Errorf("failed to get streak picks: %w", err)
+	}
+
+	// streak picks made with btstool or the web are recorded only as the following week's remaining teams
+	if nextRef != nil {
+		before, _, err := firestore.GetRemainingStreaks(ctx, seasonRef, weekRef)
+		if err != nil {
+			return in, fmt.Errorf("failed to get remaining streaks: %w", err)
+		}
+		after, _, err := firestore.GetRemainingStreaks(ctx, seasonRef, nextRef)
+		if err != nil {
+			return in, fmt.Errorf("failed to get remaining streaks of following week: %w", err)
+		}
+		in.StreakPicks = addRemainingStreakPicks(in.StreakPicks, before, after)
+	}
+	return in, nil
+}
+
+// addRemainingStreakPicks adds the streak picks shown by the difference in teams remaining before and after the week, keyed by picker ID,
+// for pickers who do not already have a streak pick.
+func addRemainingStreakPicks(picks []firestore.StreakPick, before, after map[string]firestore.StreakTeamsRemaining) []firestore.StreakPick {
+	picked := make(map[string]bool)
+	for _, sp := range picks {
+		if sp.Picker != nil {
+			picked[sp.Picker.ID] = true
+		}
+	}
+	ids := make([]string, 0, len(after))
+	for id := range after {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		b, ok := before[id]
+		if !ok || picked[id] {
+			continue
+		}
+		if sp, ok := firestore.StreakPickFromRemaining(b, after[id]); ok {
+			picks = append(picks, sp)
+		}
+	}
+	return picks
+}
+
+func countPonyWins(ponies map[string]float64, games []firestore.Game, wins map[string]int, played map[string]int) {
+	for _, g := range games {
+		if g.HomePoints == nil || g.AwayPoints == nil {
+			continue
+		}
+		margin := *g.HomePoints - *g.AwayPoints
+		for _, side := range []struct {
+			team *fs.DocumentRef
+			won  bool
+		}{{g.HomeTeam, margin > 0}, {g.AwayTeam, margin < 0}} {
+			if _, ok := ponies[side.team.ID]; !ok {
+				continue
+			}
+			played[side.team.ID]++
+			if side.won {
+				wins[side.team.ID]++
+			}
+		}
+	}
+}
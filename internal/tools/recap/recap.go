@@ -0,0 +1,354 @@
+package recap
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// nNotable is the number of best and worst picks in a recap.
+const nNotable = 3
+
+// regularSeasonGames is the number of regular season games a team plays, used to pace pony teams' projected wins.
+const regularSeasonGames = 12
+
+// Input is everything stored about a week that goes into its recap.
+type Input struct {
+	Season int
+	Week   int
+
+	// Pickers are picker names keyed by picker ID.
+	Pickers map[string]string
+
+	// Teams are team names keyed by team ID.
+	Teams map[string]string
+
+	// Previous are the results of the weeks of the season before this one.
+	Previous []firestore.WeekResult
+
+	SlateGames []firestore.SlateGame
+	SlateRefs  []*fs.DocumentRef
+
+	// Games are the week's games keyed by game ID.
+	Games map[string]firestore.Game
+
+	Picks []firestore.Pick
+
+	// StreakPicks are the week's streak picks, whether stored as streak picks or shown by the teams remaining going into the following week.
+	StreakPicks []firestore.StreakPick
+
+	// PonyTeams are the projected wins of the pony teams keyed by team ID, as stored in the season.
+	PonyTeams map[string]float64
+
+	// PonyWins and PonyGames are the wins and decided games of the pony teams through this week.
+	PonyWins  map[string]int
+	PonyGames map[string]int
+}
+
+// Standing is a picker's place in the season standings after the week.
+type Standing struct {
+	Picker       string
+	Rank         int
+	PreviousRank int
+	Points       int
+	WeekPoints   int
+}
+
+// Movement is the number of places the picker moved up (positive) or down (negative) this week.
+func (s Standing) Movement() int {
+	if s.PreviousRank == 0 {
+		return 0
+	}
+	return s.PreviousRank - s.Rank
+}
+
+// PickNote is a notable pick.
+type PickNote struct {
+	Picker      string
+	Game        string
+	Team        string
+	Probability float64
+	Value       int
+}
+
+// Upset is a slate game the favorite lost.
+type Upset struct {
+	Winner     string
+	WinnerRank int
+	Loser      string
+	LoserRank  int
+	Score      string
+}
+
+// StreakNote is what happened to a picker's streak this week.
+type StreakNote struct {
+	Picker string
+	Teams  []string
+	Bye    bool
+}
+
+// Pony is a pony team's progress against its projected wins.
+type Pony struct {
+	Team      string
+	Wins      int
+	Games     int
+	Projected float64
+	Top25     bool
+
+	// Pace is the number of wins above (or below) the team's projection for the games it has played.
+	Pace float64
+}
+
+// Losses is the number of decided games the team has not won.
+func (p Pony) Losses() int {
+	return p.Games - p.Wins
+}
+
+// Recap is the summary of a week of pick 'em.
+type Recap struct {
+	Season int
+	Week   int
+
+	// Decided is false if some games on the slate have not been decided.
+	Decided bool
+
+	Standings    []Standing
+	BestPicks    []PickNote
+	WorstPicks   []PickNote
+	Upsets       []Upset
+	SuperdogHits []PickNote
+	Survivors    []StreakNote
+	Busted       []StreakNote
+	Ponies       []Pony
+}
+
+// Build builds the recap of a week.
+func Build(in Input) (Recap, error) {
+	r := Recap{Season: in.Season, Week: in.Week}
+	result, err := firestore.ScoreWeek(in.SlateGames, in.SlateRefs, in.Games, in.Picks)
+	if err != nil {
+		return r, err
+	}
+	r.Decided = result.Decided
+
+	r.Standings = standings(in, result)
+	r.BestPicks, r.WorstPicks, r.SuperdogHits = notablePicks(in)
+	r.Upsets = upsets(in)
+	r.Survivors, r.Busted = streaks(in)
+	r.Ponies = ponies(in)
+	return r, nil
+}
+
+// Rank assigns competition ranks ("1, 2, 2, 4") to pickers by points.
+func Rank(points map[string]int) map[string]int {
+	ids := make([]string, 0, len(points))
+	for id := range points {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return points[ids[i]] > points[ids[j]] })
+	ranks := make(map[string]int)
+	for i, id := range ids {
+		if i > 0 && points[id] == points[ids[i-1]] {
+			ranks[id] = ranks[ids[i-1]]
+			continue
+		}
+		ranks[id] = i + 1
+	}
+	return ranks
+}
+
+func standings(in Input, week firestore.WeekResult) []Standing {
+	before := make(map[string]int)
+	after := make(map[string]int)
+	for id := range in.Pickers {
+		before[id] = 0
+		after[id] = week.Scores[id]
+	}
+	for _, r := range in.Previous {
+		for id, score := range r.Scores {
+			if _, ok := in.Pickers[id]; !ok {
+				continue
+			}
+			before[id] += score
+			after[id] += score
+		}
+	}
+	beforeRanks := Rank(before)
+	afterRanks := Rank(after)
+
+	s := make([]Standing, 0, len(in.Pickers))
+	for id, name := range in.Pickers {
+		st := Standing{Picker: name, Rank: afterRanks[id], Points: after[id], WeekPoints: week.Scores[id]}
+		if len(in.Previous) > 0 {
+			st.PreviousRank = beforeRanks[id]
+		}
+		s = append(s, st)
+	}
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].Rank != s[j].Rank {
+			return s[i].Rank < s[j].Rank
+		}
+		return s[i].Picker < s[j].Picker
+	})
+	return s
+}
+
+func (in Input) team(ref *fs.DocumentRef) string {
+	if name, ok := in.Teams[ref.ID]; ok {
+		return name
+	}
+	return ref.ID
+}
+
+func (in Input) matchup(game firestore.Game) string {
+	sep := "@"
+	if game.NeutralSite {
+		sep = "vs."
+	}
+	return fmt.Sprintf("%s %s %s", in.team(game.AwayTeam), sep, in.team(game.HomeTeam))
+}
+
+func notablePicks(in Input) (best, worst, dogs []PickNote) {
+	byPath := make(map[string]int)
+	for i, ref := range in.SlateRefs {
+		byPath[ref.Path] = i
+	}
+	for _, p := range in.Picks {
+		if p.Picker == nil || p.SlateGame == nil || p.PickedTeam == nil {
+			continue
+		}
+		name, ok := in.Pickers[p.Picker.ID]
+		if !ok {
+			continue
+		}
+		i, ok := byPath[p.SlateGame.Path]
+		if !ok {
+			continue
+		}
+		sg := in.SlateGames[i]
+		game := in.Games[sg.Game.ID]
+		points, decided := sg.Points(game, p.PickedTeam)
+		if !decided {
+			continue
+		}
+		note := PickNote{Picker: name, Game: in.matchup(game), Team: in.team(p.PickedTeam), Probability: p.PredictedProbability, Value: sg.Value}
+		switch {
+		case sg.Superdog && points > 0:
+			dogs = append(dogs, note)
+		case sg.Superdog || p.PredictedProbability == 0:
+			// superdog misses are expected, and picks without a probability cannot be judged
+		case points > 0:
+			best = append(best, note)
+		default:
+			worst = append(worst, note)
+		}
+	}
+	sort.SliceStable(best, func(i, j int) bool { return best[i].Probability < best[j].Probability })
+	sort.SliceStable(worst, func(i, j int) bool { return worst[i].Probability > worst[j].Probability })
+	sort.SliceStable(dogs, func(i, j int) bool { return dogs[i].Value > dogs[j].Value })
+	if len(best) > nNotable {
+		best = best[:nNotable]
+	}
+	if len(worst) > nNotable {
+		worst = worst[:nNotable]
+	}
+	return
+}
+
+func upsets(in Input) []Upset {
+	u := make([]Upset, 0)
+	for _, sg := range in.SlateGames {
+		game := in.Games[sg.Game.ID]
+		if game.HomePoints == nil || game.AwayPoints == nil || *game.HomePoints == *game.AwayPoints {
+			continue
+		}
+		homeWon := *game.HomePoints > *game.AwayPoints
+		if homeWon == sg.HomeFavored {
+			continue
+		}
+		if homeWon {
+			u = append(u, Upset{in.team(game.HomeTeam), sg.HomeRank, in.team(game.AwayTeam), sg.AwayRank, fmt.Sprintf("%d-%d", *game.HomePoints, *game.AwayPoints)})
+		} else {
+			u = append(u, Upset{in.team(game.AwayTeam), sg.AwayRank, in.team(game.HomeTeam), sg.HomeRank, fmt.Sprintf("%d-%d", *game.AwayPoints, *game.HomePoints)})
+		}
+	}
+	return u
+}
+
+// teamResult returns whether the team won its game of the week, and whether the game has been decided.
+func teamResult(games map[string]firestore.Game, team *fs.DocumentRef) (won bool, decided bool) {
+	for _, g := range games {
+		var home bool
+		switch team.ID {
+		case g.HomeTeam.ID:
+			home = true
+		case g.AwayTeam.ID:
+			home = false
+		default:
+			continue
+		}
+		if g.HomePoints == nil || g.AwayPoints == nil {
+			return false, false
+		}
+		return (*g.HomePoints > *g.AwayPoints) == home, true
+	}
+	return false, false
+}
+
+func streaks(in Input) (survivors, busted []StreakNote) {
+	for _, sp := range in.StreakPicks {
+		if sp.Picker == nil {
+			continue
+		}
+		name, ok := in.Pickers[sp.Picker.ID]
+		if !ok {
+			continue
+		}
+		note := StreakNote{Picker: name, Bye: len(sp.PickedTeams) == 0}
+		lost, pending := false, false
+		for _, team := range sp.PickedTeams {
+			note.Teams = append(note.Teams, in.team(team))
+			won, decided := teamResult(in.Games, team)
+			switch {
+			case !decided:
+				pending = true
+			case !won:
+				lost = true
+			}
+		}
+		switch {
+		case lost:
+			busted = append(busted, note)
+		case !pending:
+			survivors = append(survivors, note)
+		}
+	}
+	sort.Slice(survivors, func(i, j int) bool { return survivors[i].Picker < survivors[j].Picker })
+	sort.Slice(busted, func(i, j int) bool { return busted[i].Picker < busted[j].Picker })
+	return
+}
+
+func ponies(in Input) []Pony {
+	p := make([]Pony, 0, len(in.PonyTeams))
+	for id, wins := range in.PonyTeams {
+		pony := Pony{
+			Team:      in.team(&fs.DocumentRef{ID: id}),
+			Wins:      in.PonyWins[id],
+			Games:     in.PonyGames[id],
+			Projected: math.Abs(wins),
+			Top25:     wins < 0,
+		}
+		pony.Pace = float64(pony.Wins) - pony.Projected*float64(pony.Games)/regularSeasonGames
+		p = append(p, pony)
+	}
+	sort.Slice(p, func(i, j int) bool {
+		if p[i].Pace != p[j].Pace {
+			return p[i].Pace > p[j].Pace
+		}
+		return p[i].Team < p[j].Team
+	})
+	return p
+}
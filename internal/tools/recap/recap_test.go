@@ -0,0 +1,249 @@
+package recap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+func testInput() Input {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	iowa := &fs.DocumentRef{ID: "iowa"}
+	msu := &fs.DocumentRef{ID: "msu"}
+	alice := &fs.DocumentRef{ID: "alice"}
+	bob := &fs.DocumentRef{ID: "bob"}
+	carol := &fs.DocumentRef{ID: "carol"}
+	sg1 := &fs.DocumentRef{ID: "sg1", Path: "slates/s/games/sg1"}
+	sg2 := &fs.DocumentRef{ID: "sg2", Path: "slates/s/games/sg2"}
+
+	h1, a1 := 21, 28
+	h2, a2 := 17, 24
+	return Input{
+		Season:  2021,
+		Week:    3,
+		Pickers: map[string]string{"alice": "Alice", "bob": "Bob", "carol": "Carol"},
+		Teams:   map[string]string{"mich": "Michigan", "osu": "Ohio State", "iowa": "Iowa", "msu": "Michigan State"},
+		Previous: []firestore.WeekResult{
+			{Week: 1, Scores: map[string]int{"alice": 2, "bob": 5, "carol": 3}},
+			{Week: 2, Scores: map[string]int{"alice": 2, "bob": 0, "carol": 1}},
+		},
+		SlateGames: []firestore.SlateGame{
+			{Game: &fs.DocumentRef{ID: "g1"}, Value: 1, HomeFavored: true, HomeRank: 4},
+			{Game: &fs.DocumentRef{ID: "g2"}, Value: 5, Superdog: true, HomeFavored: true},
+		},
+		SlateRefs: []*fs.DocumentRef{sg1, sg2},
+		Games: map[string]firestore.Game{
+			"g1": {HomeTeam: mich, AwayTeam: osu, HomePoints: &h1, AwayPoints: &a1},
+			"g2": {HomeTeam: iowa, AwayTeam: msu, HomePoints: &h2, AwayPoints: &a2},
+		},
+		Picks: []firestore.Pick{
+			{SlateGame: sg1, PickedTeam: osu, Picker: alice, PredictedProbability: .3},
+			{SlateGame: sg1, PickedTeam: mich, Picker: bob, PredictedProbability: .8},
+			{SlateGame: sg1, PickedTeam: mich, Picker: carol, PredictedProbability: .6},
+			{SlateGame: sg2, PickedTeam: msu, Picker: carol},
+		},
+		StreakPicks: []firestore.StreakPick{
+			{Picker: alice, PickedTeams: []*fs.DocumentRef{osu, msu}},
+			{Picker: bob, PickedTeams: []*fs.DocumentRef{osu, mich}},
+			{Picker: carol},
+		},
+		PonyTeams: map[string]float64{"iowa": 6, "msu": -9},
+		PonyWins:  map[string]int{"iowa": 1, "msu": 3},
+		PonyGames: map[string]int{"iowa": 3, "msu": 3},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r, err := Build(testInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Decided {
+		t.Error("expected week to be decided")
+	}
+
+	// before: Alice 4 (3rd), Bob 5 (1st), Carol 4 (2nd, tied); after: Alice 5, Bob 5, Carol 9
+	wantStandings := []Standing{
+		{Picker: "Carol", Rank: 1, PreviousRank: 2, Points: 9, WeekPoints: 5},
+		{Picker: "Alice", Rank: 2, PreviousRank: 2, Points: 5, WeekPoints: 1},
+		{Picker: "Bob", Rank: 2, PreviousRank: 1, Points: 5, WeekPoints: 0},
+	}
+	if len(r.Standings) != len(wantStandings) {
+		t.Fatalf("expected %d standings, got %v", len(wantStandings), r.Standings)
+	}
+	for i, want := range wantStandings {
+		if r.Standings[i] != want {
+			t.Errorf("standing %d: expected %v, got %v", i, want, r.Standings[i])
+		}
+	}
+	if m := r.Standings[0].Movement(); m != 1 {
+		t.Errorf("expected Carol to move up 1, got %d", m)
+	}
+	if m := r.Standings[2].Movement(); m != -1 {
+		t.Errorf("expected Bob to move down 1, got %d", m)
+	}
+
+	if len(r.BestPicks) != 1 || r.BestPicks[0].Picker != "Alice" || r.BestPicks[0].Game != "Ohio State @ Michigan" {
+		t.Errorf("expected Alice's pick of Ohio State to be the best pick, got %v", r.BestPicks)
+	}
+	if len(r.WorstPicks) != 2 || r.WorstPicks[0].Picker != "Bob" || r.WorstPicks[1].Picker != "Carol" {
+		t.Errorf("expected Bob's then Carol's picks to be the worst picks, got %v", r.WorstPicks)
+	}
+	if len(r.SuperdogHits) != 1 || r.SuperdogHits[0].Team != "Michigan State" || r.SuperdogHits[0].Value != 5 {
+		t.Errorf("expected Carol's Michigan State superdog to hit, got %v", r.SuperdogHits)
+	}
+
+	if len(r.Upsets) != 2 {
+		t.Fatalf("expected 2 upsets, got %v", r.Upsets)
+	}
+	if u := r.Upsets[0]; u.Winner != "Ohio State" || u.Loser != "Michigan" || u.LoserRank != 4 || u.Score != "28-21" {
+		t.Errorf("unexpected upset %v", u)
+	}
+
+	if len(r.Survivors) != 2 || r.Survivors[0].Picker != "Alice" || !r.Survivors[1].Bye {
+		t.Errorf("expected Alice and Carol (bye) to survive, got %v", r.Survivors)
+	}
+	if len(r.Busted) != 1 || r.Busted[0].Picker != "Bob" {
+		t.Errorf("expected Bob's streak to bust, got %v", r.Busted)
+	}
+
+	if len(r.Ponies) != 2 {
+		t.Fatalf("expected 2 ponies, got %v", r.Ponies)
+	}
+	if p := r.Ponies[0]; p.Team != "Michigan State" || !p.Top25 || p.Projected != 9 || p.Pace != .75 || p.Losses() != 0 {
+		t.Errorf("expected Michigan State to lead the ponies, got %v", p)
+	}
+	if p := r.Ponies[1]; p.Team != "Iowa" || p.Pace != -.5 || p.Losses() != 2 {
+		t.Errorf("expected Iowa to trail the ponies, got %v", p)
+	}
+}
+
+func TestBuildUndecided(t *testing.T) {
+	in := testInput()
+	in.Games["g1"] = firestore.Game{HomeTeam: in.Games["g1"].HomeTeam, AwayTeam: in.Games["g1"].AwayTeam}
+	r, err := Build(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Decided {
+		t.Error("expected week with an unplayed game to be undecided")
+	}
+	if len(r.BestPicks) != 0 || len(r.WorstPicks) != 0 {
+		t.Errorf("expected no best or worst picks from an unplayed game, got %v and %v", r.BestPicks, r.WorstPicks)
+	}
+	if len(r.Survivors) != 1 || !r.Survivors[0].Bye || len(r.Busted) != 0 {
+		t.Errorf("expected only the bye to survive, got %v and busted %v", r.Survivors, r.Busted)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	r, err := Build(testInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{FormatMarkdown, FormatHTML} {
+		tmpl, err := Template(format, "")
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, r); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		out := buf.String()
+		for _, want := range []string{"2021 Week 3", "Carol", "▲1", "#4 Michigan", "Michigan State (T25)", "3-0", "+0.8"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: expected recap to contain %q:\n%s", format, want, out)
+			}
+		}
+	}
+
+	if _, err := Template("pdf", ""); err == nil {
+		t.Error("expected error for unrecognized format")
+	}
+}
+
+func TestCustomTemplate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "custom.tmpl")
+	if err := os.WriteFile(file, []byte(`{{range .Standings}}{{.Rank}}. {{.Picker}} {{move .Movement}}
+{{end}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := Template(FormatHTML, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := Build(testInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		t.Fatal(err)
+	}
+	want := "1. Carol ▲1\n2. Alice –\n2. Bob ▼1\n"
+	if buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestAddRemainingStreakPicks(t *testing.T) {
+	mich := &fs.DocumentRef{ID: "mich"}
+	osu := &fs.DocumentRef{ID: "osu"}
+	iowa := &fs.DocumentRef{ID: "iowa"}
+	alice := &fs.DocumentRef{ID: "alice"}
+	bob := &fs.DocumentRef{ID: "bob"}
+	carol := &fs.DocumentRef{ID: "carol"}
+	dave := &fs.DocumentRef{ID: "dave"}
+
+	picks := []firestore.StreakPick{{Picker: alice, PickedTeams: []*fs.DocumentRef{osu}}}
+	before := map[string]firestore.StreakTeamsRemaining{
+		"alice": {Picker: alice, TeamsRemaining: []*fs.DocumentRef{mich, osu}, PickTypesRemaining: []int{1, 2}},
+		"bob":   {Picker: bob, TeamsRemaining: []*fs.DocumentRef{mich, osu, iowa}, PickTypesRemaining: []int{1, 1, 1}},
+		"carol": {Picker: carol, TeamsRemaining: []*fs.DocumentRef{mich, osu}, PickTypesRemaining: []int{1, 2}},
+		"dave":  {Picker: dave, TeamsRemaining: []*fs.DocumentRef{mich, osu}, PickTypesRemaining: []int{1, 2}},
+	}
+	after := map[string]firestore.StreakTeamsRemaining{
+		"alice": {Picker: alice, TeamsRemaining: []*fs.DocumentRef{mich}, PickTypesRemaining: []int{1, 1}},
+		// a double down
+		"bob": {Picker: bob, TeamsRemaining: []*fs.DocumentRef{osu}, PickTypesRemaining: []int{1, 1, 0}},
+		// a bye
+		"carol": {Picker: carol, TeamsRemaining: []*fs.DocumentRef{mich, osu}, PickTypesRemaining: []int{0, 2}},
+		// nothing picked
+		"dave": {Picker: dave, TeamsRemaining: []*fs.DocumentRef{mich, osu}, PickTypesRemaining: []int{1, 2}},
+	}
+
+	got := addRemainingStreakPicks(picks, before, after)
+	if len(got) != 3 {
+		t.Fatalf("expected alice's stored pick plus bob's and carol's picks, got %v", got)
+	}
+	if got[0].Picker != alice || len(got[0].PickedTeams) != 1 || got[0].PickedTeams[0] != osu {
+		t.Errorf("expected alice's stored pick to be kept, got %v", got[0])
+	}
+	if got[1].Picker != bob || len(got[1].PickedTeams) != 2 || got[1].PickedTeams[0] != mich || got[1].PickedTeams[1] != iowa {
+		t.Errorf("expected bob to have picked michigan and iowa, got %v", got[1])
+	}
+	if got[2].Picker != carol || len(got[2].PickedTeams) != 0 {
+		t.Errorf("expected carol to have picked a bye, got %v", got[2])
+	}
+
+	in := testInput()
+	in.StreakPicks = addRemainingStreakPicks(nil, before, after)
+	r, err := Build(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// michigan and iowa lost, so bob busts; alice's ohio state won and carol's bye survives
+	if len(r.Busted) != 1 || r.Busted[0].Picker != "Bob" {
+		t.Errorf("expected bob to bust, got %v", r.Busted)
+	}
+	if len(r.Survivors) != 2 || r.Survivors[0].Picker != "Alice" || r.Survivors[1].Picker != "Carol" || !r.Survivors[1].Bye {
+		t.Errorf("expected alice and carol (bye) to survive, got %v", r.Survivors)
+	}
+}
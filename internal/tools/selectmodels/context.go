@@ -0,0 +1,27 @@
+package selectmodels
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+)
+
+type Context struct {
+	context.Context
+
+	DryRun          bool
+	FirestoreClient *fs.Client
+
+	Season int
+	Week   int
+
+	// History is the number of seasons before Season whose games are also used to evaluate the models.
+	History int
+
+	// MinGames is the number of decided games of a type a model must have predicted to be chosen for that type.
+	MinGames int
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
@@ -0,0 +1,127 @@
+package selectmodels
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// SelectModels evaluates every model's predictions of past slate games by game type and stores the best model for each type in the week's model selections.
+// Games from the weeks of the season before Week and from the History seasons before it are used.
+func SelectModels(ctx *Context) error {
+	seasons, seasonRefs, err := firestore.GetSeasons(ctx, ctx.FirestoreClient)
+	if err != nil {
+		return fmt.Errorf("SelectModels: failed to get seasons: %w", err)
+	}
+
+	var weekRef *fs.DocumentRef
+	history := make(firestore.ModelHistory)
+	selection := firestore.ModelSelection{}
+	for i, season := range seasons {
+		if season.Year > ctx.Season || season.Year < ctx.Season-ctx.History {
+			continue
+		}
+		weeks, weekRefs, err := firestore.GetWeeks(ctx, seasonRefs[i])
+		if err != nil {
+			return fmt.Errorf("SelectModels: failed to get weeks of season %d: %w", season.Year, err)
+		}
+		for j, week := range weeks {
+			if season.Year == ctx.Season && week.Number == ctx.Week {
+				weekRef = weekRefs[j]
+			}
+			if season.Year == ctx.Season && week.Number >= ctx.Week {
+				continue
+			}
+			n, err := addWeek(ctx, ctx.FirestoreClient, history, weekRefs[j])
+			if err != nil {
+				return fmt.Errorf("SelectModels: failed to evaluate models in season %d week %d: %w", season.Year, week.Number, err)
+			}
+			if n > 0 {
+				selection.Weeks++
+			}
+		}
+		selection.Seasons = append(selection.Seasons, season.Year)
+	}
+	if weekRef == nil {
+		return fmt.Errorf("SelectModels: week %d of season %d not found", ctx.Week, ctx.Season)
+	}
+	sort.Ints(selection.Seasons)
+	log.Printf("Evaluated models over %d weeks of seasons %v", selection.Weeks, selection.Seasons)
+
+	selection.Choices = history.Select(ctx.MinGames)
+	printChoices(selection.Choices)
+
+	ref := weekRef.Collection(firestore.MODEL_SELECTIONS_COLLECTION).NewDoc()
+	if ctx.DryRun {
+		log.Printf("DRY RUN: would write selection to %s:", ref.Path)
+		for _, c := range selection.Choices {
+			log.Print(c)
+		}
+		return nil
+	}
+
+	_, err = ref.Create(ctx, &selection)
+	if err != nil {
+		return fmt.Errorf("SelectModels: failed to store selection: %w", err)
+	}
+	log.Printf("Stored model selection to %s", ref.Path)
+	return nil
+}
+
+// addWeek adds the predictions of the decided games on the week's most recent slate to the history.
+// It returns the number of slate games added.
+func addWeek(ctx context.Context, client *fs.Client, history firestore.ModelHistory, weekRef *fs.DocumentRef) (int, error) {
+	sgames, _, err := firestore.GetSlateGames(ctx, weekRef)
+	if _, ok := err.(firestore.NoSlateError); ok {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	games, gameRefs, err := firestore.GetGames(ctx, weekRef)
+	if err != nil {
+		return 0, err
+	}
+	gamesByID := make(map[string]firestore.Game)
+	for i, ref := range gameRefs {
+		gamesByID[ref.ID] = games[i]
+	}
+
+	n := 0
+	for _, sg := range sgames {
+		game, ok := gamesByID[sg.Game.ID]
+		if !ok || game.HomePoints == nil || game.AwayPoints == nil {
+			continue
+		}
+		preds, _, err := firestore.GetPredictions(ctx, client, sg.Game)
+		if err != nil {
+			return n, err
+		}
+		for _, p := range preds {
+			history.Add(sg, game, p)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func printChoices(choices []firestore.ModelChoice) {
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Game Type", "Model", "Correct", "Games", "MAE", "Reason"})
+	for _, c := range choices {
+		model := "-"
+		if c.Model != nil {
+			model = c.Model.ID
+		}
+		t.AppendRow(table.Row{c.GameType, model, c.Correct, c.Games, fmt.Sprintf("%0.2f", c.MAE), c.Reason})
+	}
+	t.SetStyle(table.StyleLight)
+	t.Render()
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
 )
 
 type Context struct {
@@ -20,6 +21,7 @@ type Context struct {
 	SplitTimeFrom   time.Time
 	SplitTimeTo     time.Time
 	NewWeekNumber   int
+	PickLock        firestore.PickLock
 }
 
 func NewContext(ctx context.Context) *Context {
@@ -0,0 +1,30 @@
+package setupseason
+
+import (
+	"fmt"
+	"log"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// SetPickLock sets when the season's picks lock.
+func SetPickLock(ctx *Context) error {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("SetPickLock: failed to get season: %w", err)
+	}
+
+	if ctx.DryRun {
+		log.Print("DRY RUN: would perform the following actions in Firestore:")
+		log.Printf("SET: %s pick_lock to %v (%s)", seasonRef.Path, ctx.PickLock, ctx.PickLock)
+		return nil
+	}
+
+	_, err = seasonRef.Update(ctx, []fs.Update{{Path: "pick_lock", Value: &ctx.PickLock}})
+	if err != nil {
+		return fmt.Errorf("SetPickLock: failed to update season: %w", err)
+	}
+	log.Printf("Season %d: %s", ctx.Season, ctx.PickLock)
+	return nil
+}
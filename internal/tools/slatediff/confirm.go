@@ -0,0 +1,32 @@
+package slatediff
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// Confirmer decides whether a stale pick whose game's terms changed in the new slate should be migrated anyway.
+type Confirmer interface {
+	ConfirmMigration(s firestore.StalePick) (bool, error)
+}
+
+// SurveyConfirmer asks the user on the terminal whether to migrate each pick.
+type SurveyConfirmer struct{}
+
+// ConfirmMigration implements Confirmer.
+func (SurveyConfirmer) ConfirmMigration(s firestore.StalePick) (bool, error) {
+	picker := "<unknown picker>"
+	if s.Pick.Picker != nil {
+		picker = s.Pick.Picker.ID
+	}
+	ok := false
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf("The terms of %s's pick %s changed (%v). Migrate it to %s anyway?", picker, s.Ref.ID, s.Change.Fields, s.Change.NewRef.ID),
+	}
+	if err := survey.AskOne(prompt, &ok); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
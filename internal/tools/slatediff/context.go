@@ -0,0 +1,44 @@
+package slatediff
+
+import (
+	"context"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+type Context struct {
+	context.Context
+
+	Force  bool
+	DryRun bool
+
+	FirestoreClient *fs.Client
+
+	Season int
+	Week   int
+
+	// Old is the ID of the slate to compare against. If empty, the second most recently parsed slate is used.
+	Old string
+	// New is the ID of the slate to compare. If empty, the most recently parsed slate is used.
+	New string
+
+	// Migrate moves stale picks to the matching games in the new slate.
+	Migrate bool
+
+	// Confirmer confirms the migration of picks whose games' terms changed. If nil, those picks are not migrated.
+	Confirmer Confirmer
+
+	// Override is the reason given for migrating picks that are locked. If empty, locked picks are not migrated.
+	Override string
+
+	// Source is the program migrating picks, as recorded in the pick change log.
+	Source string
+
+	// Actor is the authenticated commissioner migrating picks, as recorded in the pick change log.
+	Actor firestore.Picker
+}
+
+func NewContext(ctx context.Context) *Context {
+	return &Context{Context: ctx}
+}
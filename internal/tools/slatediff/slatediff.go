@@ -0,0 +1,279 @@
+package slatediff
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/firestore"
+)
+
+// DiffSlates compares two slates from the same week, reports picks that refer to games outside of the new slate, and optionally migrates them.
+func DiffSlates(ctx *Context) error {
+	_, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("DiffSlates: failed to get season: %w", err)
+	}
+
+	_, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("DiffSlates: failed to get week: %w", err)
+	}
+
+	oldRef, newRef, err := pickSlates(ctx, weekRef, ctx.Old, ctx.New)
+	if err != nil {
+		return fmt.Errorf("DiffSlates: %w", err)
+	}
+
+	diff, stale, err := Compare(ctx, weekRef, oldRef, newRef)
+	if err != nil {
+		return fmt.Errorf("DiffSlates: %w", err)
+	}
+
+	fmt.Printf("Changes from slate %s to slate %s:\n%s\n", oldRef.ID, newRef.ID, diff)
+	PrintStalePicks(stale)
+
+	if !ctx.Migrate || len(stale) == 0 {
+		return nil
+	}
+
+	if err := Migrate(ctx, stale); err != nil {
+		return fmt.Errorf("DiffSlates: %w", err)
+	}
+	return nil
+}
+
+// pickSlates returns the references of the slates to compare, defaulting to the two most recently parsed slates.
+func pickSlates(ctx context.Context, weekRef *fs.DocumentRef, oldID, newID string) (*fs.DocumentRef, *fs.DocumentRef, error) {
+	_, refs, err := firestore.GetSlates(ctx, weekRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get slates: %w", err)
+	}
+
+	var newRef *fs.DocumentRef
+	if newID != "" {
+		newRef = weekRef.Collection(firestore.SLATES_COLLECTION).Doc(newID)
+	} else if len(refs) > 0 {
+		newRef = refs[0]
+	}
+
+	var oldRef *fs.DocumentRef
+	if oldID != "" {
+		oldRef = weekRef.Collection(firestore.SLATES_COLLECTION).Doc(oldID)
+	} else {
+		// The most recent slate that is not the new slate.
+		for _, ref := range refs {
+			if newRef == nil || ref.ID != newRef.ID {
+				oldRef = ref
+				break
+			}
+		}
+	}
+
+	if oldRef == nil || newRef == nil {
+		return nil, nil, fmt.Errorf("at least two slates are needed to compare, found %d", len(refs))
+	}
+	return oldRef, newRef, nil
+}
+
+// Compare compares the games of two slates and finds the picks of the week that refer to games outside of the new slate.
+func Compare(ctx context.Context, weekRef, oldRef, newRef *fs.DocumentRef) (firestore.SlateDiff, []firestore.StalePick, error) {
+	var diff firestore.SlateDiff
+	oldGames, oldGameRefs, err := firestore.GetSlateGamesFromSlate(ctx, oldRef)
+	if err != nil {
+		return diff, nil, fmt.Errorf("failed to get games from slate %s: %w", oldRef.ID, err)
+	}
+	newGames, newGameRefs, err := firestore.GetSlateGamesFromSlate(ctx, newRef)
+	if err != nil {
+		return diff, nil, fmt.Errorf("failed to get games from slate %s: %w", newRef.ID, err)
+	}
+	diff = firestore.DiffSlateGames(oldGames, oldGameRefs, newGames, newGameRefs)
+
+	picks, pickRefs, err := firestore.GetWeekPicks(ctx, weekRef)
+	if err != nil {
+		return diff, nil, fmt.Errorf("failed to get picks: %w", err)
+	}
+	return diff, firestore.FindStalePicks(picks, pickRefs, diff), nil
+}
+
+// PrintStalePicks reports stale picks and whether or not they can be migrated.
+func PrintStalePicks(stale []firestore.StalePick) {
+	if len(stale) == 0 {
+		fmt.Println("No stale picks.")
+		return
+	}
+	fmt.Printf("%d stale picks:\n", len(stale))
+	for _, s := range stale {
+		picker := "<unknown picker>"
+		if s.Pick.Picker != nil {
+			picker = s.Pick.Picker.ID
+		}
+		switch {
+		case !s.Migratable():
+			fmt.Printf("  %s: pick %s refers to %s, which is not in the new slate: pick again\n", picker, s.Ref.ID, s.Change.OldRef.Path)
+		case len(s.Change.Fields) > 0:
+			fmt.Printf("  %s: pick %s can be migrated to %s, but the terms changed (%v): confirm the migration or pick again\n", picker, s.Ref.ID, s.Change.NewRef.ID, s.Change.Fields)
+		default:
+			fmt.Printf("  %s: pick %s can be migrated to %s\n", picker, s.Ref.ID, s.Change.NewRef.ID)
+		}
+	}
+}
+
+// Migrate points stale picks at the matching games in the new slate, recording each migration in the pick change log.
+// Picks whose games' terms are unchanged are migrated as they are. Picks whose games' terms changed are only migrated if ctx.Confirmer confirms them,
+// and their predictions are recomputed against the new terms. Locked picks are only migrated if ctx.Override gives a reason.
+// Picks that cannot be migrated are skipped.
+func Migrate(ctx *Context, stale []firestore.StalePick) error {
+	season, seasonRef, err := firestore.GetSeason(ctx, ctx.FirestoreClient, ctx.Season)
+	if err != nil {
+		return fmt.Errorf("failed to get season: %w", err)
+	}
+	week, weekRef, err := firestore.GetWeek(ctx, seasonRef, ctx.Week)
+	if err != nil {
+		return fmt.Errorf("failed to get week: %w", err)
+	}
+	perfs, _, err := firestore.GetMostRecentModelPerformances(ctx, ctx.FirestoreClient, weekRef)
+	if err != nil {
+		return fmt.Errorf("failed to get model performances: %w", err)
+	}
+	perfLookup := make(map[string]firestore.ModelPerformance)
+	for _, p := range perfs {
+		perfLookup[p.Model.ID] = p
+	}
+
+	var picks []firestore.Pick
+	var refs []*fs.DocumentRef
+	var changes []firestore.PickChange
+	for _, s := range stale {
+		if !s.Migratable() {
+			continue
+		}
+		pick := s.Pick
+		pick.SlateGame = s.Change.NewRef
+		if len(s.Change.Fields) > 0 {
+			ok := false
+			if ctx.Confirmer != nil {
+				if ok, err = ctx.Confirmer.ConfirmMigration(s); err != nil {
+					return err
+				}
+			}
+			if !ok {
+				log.Printf("Not migrating pick %s: the terms of its game changed (%v)", s.Ref.ID, s.Change.Fields)
+				continue
+			}
+			if pick, err = refill(ctx, pick, *s.Change.New, perfLookup); err != nil {
+				return fmt.Errorf("failed to update pick %s for the new terms: %w", s.Ref.ID, err)
+			}
+		}
+		picks = append(picks, pick)
+		refs = append(refs, s.Ref)
+		changes = append(changes, firestore.NewPickChange(&s.Pick, pick, s.Ref, ctx.Source, ctx.Actor))
+	}
+
+	locked, err := firestore.LockedPickChanges(ctx, season.PickLock, week, changes, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to check pick locks: %w", err)
+	}
+	if ctx.Override == "" && len(locked) > 0 {
+		// leave locked picks where they are and migrate the rest
+		var keepPicks []firestore.Pick
+		var keepRefs []*fs.DocumentRef
+		var keepChanges []firestore.PickChange
+		for i := range changes {
+			if l, ok := locked[i]; ok {
+				log.Printf("Not migrating pick %s: %s (override with a reason to migrate it anyway)", refs[i].ID, l)
+				continue
+			}
+			keepPicks = append(keepPicks, picks[i])
+			keepRefs = append(keepRefs, refs[i])
+			keepChanges = append(keepChanges, changes[i])
+		}
+		picks, refs, changes = keepPicks, keepRefs, keepChanges
+		locked = nil
+	}
+	if err := firestore.OverrideLocks(changes, locked, ctx.Override); err != nil {
+		return err
+	}
+	lockedByPicker := make(map[string][]firestore.PickLockedError)
+	pickerRefs := make(map[string]*fs.DocumentRef)
+	for i, c := range changes {
+		if l, ok := locked[i]; ok {
+			log.Printf("Overriding lock: %s", l)
+			lockedByPicker[c.Picker.ID] = append(lockedByPicker[c.Picker.ID], l)
+			pickerRefs[c.Picker.ID] = c.Picker
+		}
+	}
+
+	if ctx.DryRun {
+		log.Print("DRY RUN: would migrate the following picks:")
+		for i, c := range changes {
+			log.Printf("%s: %s -> %s", refs[i].Path, c.FromSlateGame.Path, c.SlateGame.Path)
+		}
+		return nil
+	}
+
+	err = ctx.FirestoreClient.RunTransaction(ctx, func(c context.Context, t *fs.Transaction) error {
+		for i, pick := range picks {
+			if err := t.Set(refs[i], &pick); err != nil {
+				return err
+			}
+		}
+		if err := firestore.RecordPickChanges(t, weekRef, changes); err != nil {
+			return err
+		}
+		for id, l := range lockedByPicker {
+			if err := firestore.RecordLockOverride(t, weekRef, firestore.NewLockOverride(pickerRefs[id], l, ctx.Override, ctx.Source, ctx.Actor)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate picks: %w", err)
+	}
+	log.Printf("Migrated %d of %d stale picks", len(picks), len(stale))
+	return nil
+}
+
+// refill recomputes the predicted spread and probability of a pick against the terms of its new slate game, keeping the picked team.
+// Picks made without a model prediction are left as they are.
+func refill(ctx *Context, pick firestore.Pick, sg firestore.SlateGame, perfs map[string]firestore.ModelPerformance) (firestore.Pick, error) {
+	if pick.ModelPrediction == nil {
+		return pick, nil
+	}
+	snap, err := sg.Game.Get(ctx)
+	if err != nil {
+		return pick, err
+	}
+	var game firestore.Game
+	if err := snap.DataTo(&game); err != nil {
+		return pick, err
+	}
+	snap, err = pick.ModelPrediction.Get(ctx)
+	if err != nil {
+		return pick, err
+	}
+	var pred firestore.ModelPrediction
+	if err := snap.DataTo(&pred); err != nil {
+		return pick, err
+	}
+	perf, ok := perfs[firestore.RefID(pred.Model)]
+	if !ok {
+		return pick, fmt.Errorf("no performance found for model %s", firestore.RefID(pred.Model))
+	}
+	if pred.HomeTeam != nil && pred.HomeTeam.ID != game.HomeTeam.ID {
+		pred.Spread = -pred.Spread
+		pred.HomeTeam, pred.AwayTeam = pred.AwayTeam, pred.HomeTeam
+	}
+
+	var p firestore.Pick
+	p.FillOut(game, perf, pred, pick.ModelPrediction, sg.NoisySpread)
+	pick.PredictedSpread = p.PredictedSpread
+	pick.PredictedProbability = p.PredictedProbability
+	if pick.PickedTeam != nil && pick.PickedTeam.ID != p.PickedTeam.ID {
+		pick.PredictedProbability = 1 - p.PredictedProbability
+	}
+	return pick, nil
+}
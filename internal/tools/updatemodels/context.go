@@ -4,6 +4,7 @@ import (
 	"context"
 
 	fs "cloud.google.com/go/firestore"
+	"github.com/reallyasi9/b1gpickem/internal/tools/editteams"
 )
 
 type Context struct {
@@ -16,6 +17,9 @@ type Context struct {
 	Week            int
 	ModelNames      []string
 	SystemNames     []string
+
+	// Resolver resolves team names that are missing or duplicated. If nil, the user is asked on the terminal.
+	Resolver editteams.NameResolver
 }
 
 func NewContext(ctx context.Context) *Context {
@@ -26,15 +26,10 @@ func GetPredictions(ctx *Context) error {
 
 	year := strconv.Itoa(ctx.Season)
 	seasonRef := ctx.FirestoreClient.Collection(firestore.SEASONS_COLLECTION).Doc(year)
-	teams, refs, err := firestore.GetTeams(ctx, seasonRef)
+	teamLookup, err := firestore.GetTeamResolver(ctx, seasonRef)
 	if err != nil {
 		return fmt.Errorf("GetPredictions: Failed to get teams: %w", err)
 	}
-
-	teamLookup, err2 := firestore.NewTeamRefsByOtherName(teams, refs)
-	if err2 != nil {
-		panic(err2)
-	}
 	tps, err := pt.Matchups(teamLookup)
 	if err != nil {
 		return fmt.Errorf("GetPredictions: Failed to match teams to refs: %w", err)
@@ -194,17 +189,17 @@ func headerMap(record []string) (map[string]int, error) {
 	return out, nil
 }
 
-func (pt *predictionTable) Matchups(lookup firestore.TeamRefsByName) ([]firestore.Matchup, error) {
+func (pt *predictionTable) Matchups(lookup firestore.TeamLookup) ([]firestore.Matchup, error) {
 	tps := make([]firestore.Matchup, len(pt.homeTeams))
 	for i := range pt.homeTeams {
 		ht := pt.homeTeams[i]
 		at := pt.awayTeams[i]
 
-		href, ok := lookup[ht]
+		href, ok := lookup.Lookup(ht, firestore.OtherName)
 		if !ok {
 			return nil, fmt.Errorf("no team matching home team '%s' in game %d", ht, i)
 		}
-		aref, ok := lookup[at]
+		aref, ok := lookup.Lookup(at, firestore.OtherName)
 		if !ok {
 			return nil, fmt.Errorf("no team matching away team '%s' in game %d", at, i)
 		}
@@ -61,36 +61,16 @@ func UpdateSagarin(ctx *Context) error {
 	if err != nil {
 		return fmt.Errorf("GetPredictions: Failed to get teams: %w", err)
 	}
-	var teamLookup firestore.TeamRefsByName
-	var err2 *firestore.DuplicateTeamNameError
-	for {
-		teamLookup, err2 = firestore.NewTeamRefsByOtherName(teams, teamRefs)
-		if err2 == nil {
-			break
-		}
-		updateMap, err3 := editteams.SurveyReplaceName(teams, teamRefs, err2.Name, err2.Teams, err2.Refs, err2.NameType)
-		if err3 != nil {
-			panic(err3)
-		}
-
-		for ref, t := range updateMap {
-			fmt.Printf("Updating %s to add %s name %s\n", ref.ID, err2.NameType, err2.Name)
-
-			editContext := &editteams.Context{
-				Context:         ctx.Context,
-				Force:           ctx.Force,
-				DryRun:          ctx.DryRun,
-				FirestoreClient: ctx.FirestoreClient,
-				ID:              ref.ID,
-				Team:            t,
-				Season:          ctx.Season,
-				Append:          false,
-			}
-			err := editteams.EditTeam(editContext)
-			if err != nil {
-				panic(err)
-			}
-		}
+	editContext := &editteams.Context{
+		Context:         ctx.Context,
+		Force:           ctx.Force,
+		DryRun:          ctx.DryRun,
+		FirestoreClient: ctx.FirestoreClient,
+		Season:          ctx.Season,
+	}
+	teamLookup, err := editteams.NewTeamResolver(editContext, ctx.Resolver, teams, teamRefs)
+	if err != nil {
+		return fmt.Errorf("GetPredictions: Failed to make team resolver: %w", err)
 	}
 
 	models, refs, err := firestore.GetModels(ctx, ctx.FirestoreClient)
@@ -112,41 +92,25 @@ func UpdateSagarin(ctx *Context) error {
 	var sagTable map[string]sagarinElement
 	var err3 []error
 
-SagarinParseLoop:
 	for {
 		sagTable, err3 = parseSagarinTable(SAG_URL, teamLookup, modelRefs)
 		if err3 == nil {
 			break
 		}
-		for _, err := range err3 {
-			if e, ok := err.(firestore.NameNotFoundError); ok {
-				t, ref, err4 := editteams.SurveyAddName(teams, teamRefs, e.Name, e.NameType)
-				if err4 != nil {
-					panic(err4)
-				}
-
-				fmt.Printf("Updating %s to add %s name %s\n", ref.ID, e.NameType, e.Name)
-
-				editContext := &editteams.Context{
-					Context:         ctx.Context,
-					Force:           ctx.Force,
-					DryRun:          ctx.DryRun,
-					FirestoreClient: ctx.FirestoreClient,
-					ID:              ref.ID,
-					Team:            t,
-					Season:          ctx.Season,
-					Append:          false,
-				}
-				err := editteams.EditTeam(editContext)
-				if err != nil {
-					panic(err)
-				}
 
-				teamLookup[e.Name] = ref
-				continue SagarinParseLoop
-			} else {
+		// Resolve every missing name before parsing again so that all unresolved names are reported at once.
+		var unresolved []error
+		for _, err := range err3 {
+			e, ok := err.(firestore.NameNotFoundError)
+			if !ok {
 				return fmt.Errorf("GetPredictions: Failed to create Sagarin table: %w", err)
 			}
+			if _, err := editteams.ResolveName(editContext, ctx.Resolver, teamLookup, e.Name, e.NameType); err != nil {
+				unresolved = append(unresolved, err)
+			}
+		}
+		if len(unresolved) > 0 {
+			return fmt.Errorf("GetPredictions: Failed to resolve %d team names: %v", len(unresolved), unresolved)
 		}
 	}
 
@@ -206,7 +170,7 @@ SagarinParseLoop:
 type sagarinElement []firestore.ModelTeamPoints
 
 // parseSagarinTable parses the table provided by Sagarin for each team.
-func parseSagarinTable(f string, lookup firestore.TeamRefsByName, modelRefs []*fs.DocumentRef) (map[string]sagarinElement, []error) {
+func parseSagarinTable(f string, lookup firestore.TeamLookup, modelRefs []*fs.DocumentRef) (map[string]sagarinElement, []error) {
 	var rc io.ReadCloser
 	if _, err := url.Parse(f); err == nil {
 		// <sigh> Oh Sagarin...
@@ -274,7 +238,7 @@ func parseSagarinTable(f string, lookup firestore.TeamRefsByName, modelRefs []*f
 		}
 		seenTeams[name] = struct{}{}
 
-		teamRef, exists := lookup[name]
+		teamRef, exists := lookup.Lookup(name, firestore.OtherName)
 		if !exists {
 			e := firestore.NameNotFoundError{Name: name, NameType: firestore.OtherName}
 			errs = append(errs, e)